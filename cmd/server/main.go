@@ -48,18 +48,48 @@ func main() {
 	defer asynqClient.Close()
 
 	// Initialize repositories
-	ingredientRepo := repository.NewIngredientRepository(dbConn)
+	ingredientRepo := repository.NewIngredientRepository(dbConn, cfg.LowStockThresholdPercent)
 	orderRepo := repository.NewOrderRepository(dbConn)
 	productRepo := repository.NewProductRepository(dbConn)
+	receiptRepo := repository.NewReceiptRepository(dbConn)
+	movementRepo := repository.NewStockMovementRepository(dbConn)
+	idempotencyRepo := repository.NewIdempotencyRepository(dbConn, cfg.IdempotencyKeyTTL)
+	outboxRepo := repository.NewOutboxRepository(dbConn)
+	packRepo := repository.NewIngredientPackRepository(dbConn)
+	prepRepo := repository.NewPrepRecipeRepository(dbConn)
+	wasteRepo := repository.NewWasteRepository(dbConn)
+	countRepo := repository.NewStockCountRepository(dbConn)
+	reportRepo := repository.NewReportRepository(dbConn)
+	supplierRepo := repository.NewSupplierRepository(dbConn)
 	taskQueueRepo := repository.NewTaskQueueRepository(asynqClient)
 	// Initialize services
-	orderService := service.NewOrderService(orderRepo, productRepo, ingredientRepo)
-	ingredientService := service.NewIngredientService(ingredientRepo, taskQueueRepo)
+	orderService := service.NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
+	ingredientService := service.NewIngredientService(ingredientRepo, movementRepo, outboxRepo, packRepo, prepRepo)
+	productService := service.NewProductService(productRepo, ingredientRepo, prepRepo)
+	receiptService := service.NewReceiptService(receiptRepo, ingredientRepo, movementRepo, packRepo)
+	outboxService := service.NewOutboxService(outboxRepo, taskQueueRepo)
+	wasteService := service.NewWasteService(wasteRepo, ingredientRepo, movementRepo, outboxRepo)
+	countService := service.NewStockCountService(countRepo, ingredientRepo, movementRepo, outboxRepo)
+	reportService := service.NewReportService(reportRepo)
+	supplierService := service.NewSupplierService(supplierRepo, ingredientRepo)
 
 	// Initialize controllers
-	orderController := controllers.NewOrderController(orderService, ingredientService)
-
-	go worker.RunTaskProcessor(cfg, RedisClientOpts, ingredientRepo)
+	orderController := controllers.NewOrderController(orderService)
+	ingredientController := controllers.NewIngredientController(ingredientService)
+	productController := controllers.NewProductController(productService)
+	receiptController := controllers.NewReceiptController(receiptService)
+	wasteController := controllers.NewWasteController(wasteService)
+	countController := controllers.NewStockCountController(countService)
+	reportController := controllers.NewReportController(reportService)
+	supplierController := controllers.NewSupplierController(supplierService)
+
+	go worker.RunTaskProcessor(cfg, RedisClientOpts, ingredientRepo, outboxRepo, idempotencyRepo)
+	go worker.RunTaskScheduler(cfg, RedisClientOpts)
+
+	// Publish outbox messages until the server shuts down
+	relayCtx, stopRelay := context.WithCancel(context.Background())
+	defer stopRelay()
+	go worker.RunOutboxRelay(relayCtx, outboxService, cfg.OutboxRelayInterval)
 
 	// Create router
 	r := chi.NewRouter()
@@ -76,7 +106,7 @@ func main() {
 	// CORS middleware
 	r.Use(cors.Handler(cors.Options{
 		AllowedOrigins:   []string{"https://*", "http://*"},
-		AllowedMethods:   []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
 		AllowedHeaders:   []string{"Accept", "Authorization", "Content-Type", "X-CSRF-Token"},
 		ExposedHeaders:   []string{"Link"},
 		AllowCredentials: false,
@@ -88,8 +118,91 @@ func main() {
 
 	// Routes
 	r.Route("/api/v1", func(r chi.Router) {
-		r.Post("/orders", orderController.CreateOrder)
-
+		r.Post("/orders:preview", orderController.PreviewOrder)
+		r.Route("/orders", func(r chi.Router) {
+			r.Get("/", orderController.ListOrders)
+			r.Post("/", orderController.CreateOrder)
+			r.Get("/{id}", orderController.GetOrder)
+			r.Post("/{id}/cancel", orderController.CancelOrder)
+		})
+
+		r.Route("/ingredients", func(r chi.Router) {
+			r.Get("/", ingredientController.ListIngredients)
+			r.Post("/", ingredientController.CreateIngredient)
+			r.Get("/{id}", ingredientController.GetIngredient)
+			r.Put("/{id}", ingredientController.RenameIngredient)
+			r.Patch("/{id}", ingredientController.UpdateTotalStock)
+			r.Delete("/{id}", ingredientController.ArchiveIngredient)
+			r.Put("/{id}/threshold", ingredientController.SetLowStockThreshold)
+			r.Delete("/{id}/threshold", ingredientController.ResetLowStockThreshold)
+			r.Put("/{id}/yield", ingredientController.SetYield)
+			r.Delete("/{id}/yield", ingredientController.ResetYield)
+			r.Get("/{id}/movements", ingredientController.ListStockMovements)
+			r.Get("/{id}/lots", ingredientController.ListLots)
+			r.Get("/{id}/suppliers", supplierController.ListIngredientSuppliers)
+			r.Get("/{id}/packs", ingredientController.ListPacks)
+			r.Post("/{id}/packs", ingredientController.CreatePack)
+			r.Delete("/{id}/packs/{packID}", ingredientController.DeletePack)
+			r.Get("/{id}/recipe", ingredientController.GetPrepRecipe)
+			r.Put("/{id}/recipe", ingredientController.SetPrepRecipe)
+			r.Delete("/{id}/recipe", ingredientController.DeletePrepRecipe)
+		})
+
+		r.Get("/lots/expiring", ingredientController.ListExpiringLots)
+
+		r.Route("/products", func(r chi.Router) {
+			r.Get("/", productController.ListProducts)
+			r.Post("/", productController.CreateProduct)
+			r.Get("/capacity", productController.ListProductCapacities)
+			r.Get("/{id}", productController.GetProduct)
+			r.Get("/{id}/capacity", productController.GetProductCapacity)
+			r.Put("/{id}/availability", productController.SetAvailability)
+			r.Put("/{id}/ingredients", productController.ReplaceRecipe)
+			r.Put("/{id}/ingredients/{ingredientID}", productController.SetProductIngredient)
+			r.Delete("/{id}/ingredients/{ingredientID}", productController.RemoveProductIngredient)
+			r.Get("/{id}/modifiers", productController.ListModifiers)
+			r.Post("/{id}/modifiers", productController.CreateModifier)
+			r.Delete("/{id}/modifiers/{modifierID}", productController.DeleteModifier)
+			r.Get("/{id}/variants", productController.ListVariants)
+			r.Post("/{id}/variants", productController.CreateVariant)
+			r.Delete("/{id}/variants/{variantID}", productController.DeleteVariant)
+		})
+
+		r.Get("/menu/availability", productController.ListMenuAvailability)
+
+		r.Route("/suppliers", func(r chi.Router) {
+			r.Get("/", supplierController.ListSuppliers)
+			r.Post("/", supplierController.CreateSupplier)
+			r.Get("/{id}", supplierController.GetSupplier)
+			r.Put("/{id}", supplierController.UpdateSupplier)
+			r.Delete("/{id}", supplierController.DeleteSupplier)
+			r.Get("/{id}/ingredients", supplierController.ListSupplierIngredients)
+			r.Put("/{id}/ingredients/{ingredientID}", supplierController.SetSupplierIngredient)
+			r.Delete("/{id}/ingredients/{ingredientID}", supplierController.RemoveSupplierIngredient)
+		})
+
+		r.Route("/receipts", func(r chi.Router) {
+			r.Post("/", receiptController.CreateReceipt)
+			r.Get("/{id}", receiptController.GetReceipt)
+		})
+
+		r.Route("/waste", func(r chi.Router) {
+			r.Post("/", wasteController.RecordWaste)
+			r.Get("/{id}", wasteController.GetWasteEntry)
+		})
+
+		r.Route("/counts", func(r chi.Router) {
+			r.Post("/", countController.OpenSession)
+			r.Get("/{id}", countController.GetSession)
+			r.Post("/{id}/entries", countController.SubmitCounts)
+			r.Post("/{id}/close", countController.CloseSession)
+			r.Get("/{id}/variance", countController.GetVarianceReport)
+		})
+
+		r.Route("/reports", func(r chi.Router) {
+			r.Get("/waste", wasteController.GetWasteReport)
+			r.Get("/variance", reportController.GetUsageVarianceReport)
+		})
 	})
 
 	// Health check
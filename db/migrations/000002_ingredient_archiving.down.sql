@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_ingredients_archived_at;
+
+ALTER TABLE ingredients DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,4 @@
+ALTER TABLE ingredients ADD COLUMN archived_at TIMESTAMP WITH TIME ZONE;
+
+-- optimization for listing active ingredients
+CREATE INDEX idx_ingredients_archived_at ON ingredients (archived_at);
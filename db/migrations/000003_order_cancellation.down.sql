@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS order_consumptions;
+
+ALTER TABLE orders
+    DROP COLUMN IF EXISTS cancelled_at,
+    DROP COLUMN IF EXISTS status;
@@ -0,0 +1,19 @@
+ALTER TABLE orders
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'placed' CHECK (status IN ('placed', 'cancelled')),
+    ADD COLUMN cancelled_at TIMESTAMP WITH TIME ZONE;
+
+-- ingredient amounts consumed by each order, snapshotted when the order is placed
+-- so a cancellation restores exactly what was deducted even if recipes changed since
+CREATE TABLE order_consumptions (
+    order_id INTEGER REFERENCES orders(id),
+    ingredient_id INTEGER REFERENCES ingredients(id),
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount >= 0),
+    PRIMARY KEY (order_id, ingredient_id)
+);
+
+-- Backfill existing orders from the current recipes
+INSERT INTO order_consumptions (order_id, ingredient_id, amount)
+SELECT oi.order_id, pi.ingredient_id, SUM(pi.amount * oi.quantity)
+FROM order_items oi
+JOIN product_ingredients pi ON pi.product_id = oi.product_id
+GROUP BY oi.order_id, pi.ingredient_id;
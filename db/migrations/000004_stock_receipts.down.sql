@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS stock_receipt_items;
+DROP TABLE IF EXISTS stock_receipts;
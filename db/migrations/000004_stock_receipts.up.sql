@@ -0,0 +1,14 @@
+CREATE TABLE stock_receipts (
+    id SERIAL PRIMARY KEY,
+    received_by VARCHAR(100) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    received_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE stock_receipt_items (
+    receipt_id INTEGER REFERENCES stock_receipts(id),
+    ingredient_id INTEGER REFERENCES ingredients(id),
+    quantity NUMERIC(10, 2) NOT NULL CHECK (quantity > 0),
+    increase_total_stock BOOLEAN NOT NULL DEFAULT FALSE,
+    PRIMARY KEY (receipt_id, ingredient_id)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE ingredients
+    DROP CONSTRAINT IF EXISTS ingredients_low_stock_threshold_check,
+    DROP COLUMN IF EXISTS low_stock_threshold,
+    DROP COLUMN IF EXISTS low_stock_threshold_type;
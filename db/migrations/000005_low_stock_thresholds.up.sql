@@ -0,0 +1,5 @@
+-- per-ingredient low stock threshold, NULL means the global default percentage applies
+ALTER TABLE ingredients
+    ADD COLUMN low_stock_threshold_type VARCHAR(20) CHECK (low_stock_threshold_type IN ('percentage', 'quantity')),
+    ADD COLUMN low_stock_threshold NUMERIC(10, 2) CHECK (low_stock_threshold >= 0),
+    ADD CONSTRAINT ingredients_low_stock_threshold_check CHECK ((low_stock_threshold_type IS NULL) = (low_stock_threshold IS NULL));
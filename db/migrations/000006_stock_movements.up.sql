@@ -0,0 +1,27 @@
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    delta NUMERIC(10, 2) NOT NULL,
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('order', 'cancellation', 'restock', 'adjustment', 'waste')),
+    reference_id INTEGER,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_movements_ingredient ON stock_movements (ingredient_id, id);
+
+-- Movements are an audit trail, reject any attempt to rewrite history
+CREATE FUNCTION reject_stock_movement_change() RETURNS trigger AS $$
+BEGIN
+    RAISE EXCEPTION 'stock movements are immutable';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER stock_movements_immutable
+    BEFORE UPDATE OR DELETE ON stock_movements
+    FOR EACH ROW EXECUTE FUNCTION reject_stock_movement_change();
+
+-- Open the ledger with the stock on hand so movements add up to current_stock
+INSERT INTO stock_movements (ingredient_id, delta, reason)
+SELECT id, current_stock, 'adjustment'
+FROM ingredients
+WHERE current_stock <> 0;
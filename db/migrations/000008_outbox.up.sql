@@ -0,0 +1,12 @@
+CREATE TABLE outbox (
+    id SERIAL PRIMARY KEY,
+    task_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    dispatched_at TIMESTAMP WITH TIME ZONE
+);
+
+-- optimization for the relay polling undispatched messages
+CREATE INDEX idx_outbox_pending ON outbox (id) WHERE dispatched_at IS NULL;
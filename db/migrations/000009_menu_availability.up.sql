@@ -0,0 +1,2 @@
+-- Set by staff, whether a product is in stock is worked out from its recipe capacity
+ALTER TABLE products ADD COLUMN marked_unavailable BOOLEAN NOT NULL DEFAULT FALSE;
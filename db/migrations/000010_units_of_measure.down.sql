@@ -0,0 +1,2 @@
+ALTER TABLE product_ingredients DROP COLUMN unit;
+ALTER TABLE ingredients DROP COLUMN unit;
@@ -0,0 +1,8 @@
+-- Ingredient stock is kept in a base unit, recipes may use any unit of the same kind
+ALTER TABLE ingredients
+    ADD COLUMN unit VARCHAR(10) NOT NULL DEFAULT 'g' CHECK (unit IN ('g', 'ml', 'piece'));
+
+-- Existing recipe amounts are in grams, like the seeded ingredients
+ALTER TABLE product_ingredients
+    ADD COLUMN unit VARCHAR(10) NOT NULL DEFAULT 'g'
+        CHECK (unit IN ('g', 'mg', 'kg', 'oz', 'lb', 'ml', 'l', 'tsp', 'tbsp', 'fl_oz', 'cup', 'piece', 'dozen'));
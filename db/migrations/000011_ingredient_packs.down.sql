@@ -0,0 +1,6 @@
+ALTER TABLE stock_receipt_items
+    DROP CONSTRAINT stock_receipt_items_pack_check,
+    DROP COLUMN pack_count,
+    DROP COLUMN pack_id;
+
+DROP TABLE IF EXISTS ingredient_packs;
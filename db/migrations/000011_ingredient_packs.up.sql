@@ -0,0 +1,14 @@
+-- Packs an ingredient is purchased in, such as a 5 kg box, sized in the ingredient base unit
+CREATE TABLE ingredient_packs (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    label VARCHAR(100) NOT NULL,
+    size NUMERIC(10, 2) NOT NULL CHECK (size > 0),
+    UNIQUE (ingredient_id, label)
+);
+
+-- Receipt items received in packs keep the pack they were counted in
+ALTER TABLE stock_receipt_items
+    ADD COLUMN pack_id INTEGER REFERENCES ingredient_packs(id),
+    ADD COLUMN pack_count NUMERIC(10, 2) CHECK (pack_count > 0),
+    ADD CONSTRAINT stock_receipt_items_pack_check CHECK ((pack_id IS NULL) = (pack_count IS NULL));
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS prep_ingredients;
+DROP TABLE IF EXISTS prep_recipes;
@@ -0,0 +1,18 @@
+-- A prep item is an ingredient the kitchen makes from other ingredients, such as
+-- a sauce. One batch of its recipe makes yield of it, in its base unit.
+CREATE TABLE prep_recipes (
+    ingredient_id INTEGER PRIMARY KEY REFERENCES ingredients(id),
+    yield NUMERIC(10, 2) NOT NULL CHECK (yield > 0)
+);
+
+CREATE TABLE prep_ingredients (
+    prep_id INTEGER NOT NULL REFERENCES prep_recipes(ingredient_id) ON DELETE CASCADE,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    unit VARCHAR(10) NOT NULL
+        CHECK (unit IN ('g', 'mg', 'kg', 'oz', 'lb', 'ml', 'l', 'tsp', 'tbsp', 'fl_oz', 'cup', 'piece', 'dozen')),
+    PRIMARY KEY (prep_id, ingredient_id),
+    CHECK (prep_id <> ingredient_id)
+);
+
+CREATE INDEX idx_prep_ingredients_ingredient ON prep_ingredients (ingredient_id);
@@ -0,0 +1,13 @@
+DROP INDEX IF EXISTS idx_order_items_order;
+
+-- Lines of the same product are merged back into one
+DELETE FROM order_items a
+USING order_items b
+WHERE a.order_id = b.order_id AND a.product_id = b.product_id AND a.id > b.id;
+
+ALTER TABLE order_items
+    DROP COLUMN modifiers,
+    DROP COLUMN id;
+ALTER TABLE order_items ADD PRIMARY KEY (order_id, product_id);
+
+DROP TABLE IF EXISTS product_modifiers;
@@ -0,0 +1,25 @@
+-- Changes to a product recipe customers may ask for, such as extra cheese or no onion.
+-- add puts amount of ingredient_id on top of the recipe, remove leaves ingredient_id out
+-- and replace uses amount of replacement_ingredient_id instead of ingredient_id.
+CREATE TABLE product_modifiers (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    name VARCHAR(100) NOT NULL,
+    action VARCHAR(10) NOT NULL CHECK (action IN ('add', 'remove', 'replace')),
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    replacement_ingredient_id INTEGER REFERENCES ingredients(id),
+    amount NUMERIC(10, 2) CHECK (amount > 0),
+    unit VARCHAR(10)
+        CHECK (unit IN ('g', 'mg', 'kg', 'oz', 'lb', 'ml', 'l', 'tsp', 'tbsp', 'fl_oz', 'cup', 'piece', 'dozen')),
+    UNIQUE (product_id, name),
+    CHECK ((action = 'remove') = (amount IS NULL AND unit IS NULL)),
+    CHECK ((action = 'replace') = (replacement_ingredient_id IS NOT NULL))
+);
+
+-- The same product may now be ordered on several lines with different modifiers
+ALTER TABLE order_items DROP CONSTRAINT order_items_pkey;
+ALTER TABLE order_items
+    ADD COLUMN id SERIAL PRIMARY KEY,
+    ADD COLUMN modifiers JSONB NOT NULL DEFAULT '[]';
+
+CREATE INDEX idx_order_items_order ON order_items (order_id);
@@ -0,0 +1,4 @@
+ALTER TABLE order_items DROP COLUMN IF EXISTS variant_id;
+
+DROP TABLE IF EXISTS product_variant_ingredients;
+DROP TABLE IF EXISTS product_variants;
@@ -0,0 +1,20 @@
+-- Sizes of a product, such as a double burger. A variant scales the product recipe
+-- by multiplier, except for the ingredients it overrides the amount of.
+CREATE TABLE product_variants (
+    id SERIAL PRIMARY KEY,
+    product_id INTEGER NOT NULL REFERENCES products(id),
+    name VARCHAR(100) NOT NULL,
+    multiplier NUMERIC(10, 3) NOT NULL DEFAULT 1 CHECK (multiplier > 0),
+    UNIQUE (product_id, name)
+);
+
+CREATE TABLE product_variant_ingredients (
+    variant_id INTEGER NOT NULL REFERENCES product_variants(id) ON DELETE CASCADE,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    amount NUMERIC(10, 2) NOT NULL CHECK (amount > 0),
+    unit VARCHAR(10) NOT NULL
+        CHECK (unit IN ('g', 'mg', 'kg', 'oz', 'lb', 'ml', 'l', 'tsp', 'tbsp', 'fl_oz', 'cup', 'piece', 'dozen')),
+    PRIMARY KEY (variant_id, ingredient_id)
+);
+
+ALTER TABLE order_items ADD COLUMN variant_id INTEGER REFERENCES product_variants(id);
@@ -0,0 +1,4 @@
+ALTER TABLE order_consumptions DROP COLUMN IF EXISTS theoretical_amount;
+ALTER TABLE prep_ingredients DROP COLUMN IF EXISTS yield_percent;
+ALTER TABLE product_ingredients DROP COLUMN IF EXISTS yield_percent;
+ALTER TABLE ingredients DROP COLUMN IF EXISTS yield_percent;
@@ -0,0 +1,16 @@
+-- Share of an ingredient left for the recipe after peeling, trimming or cooking
+-- loss. A recipe line needing amount takes amount * 100 / yield_percent from
+-- stock, lines may set their own yield instead of the one of their ingredient.
+ALTER TABLE ingredients
+    ADD COLUMN yield_percent NUMERIC(5, 2) CHECK (yield_percent > 0 AND yield_percent <= 100);
+ALTER TABLE product_ingredients
+    ADD COLUMN yield_percent NUMERIC(5, 2) CHECK (yield_percent > 0 AND yield_percent <= 100);
+ALTER TABLE prep_ingredients
+    ADD COLUMN yield_percent NUMERIC(5, 2) CHECK (yield_percent > 0 AND yield_percent <= 100);
+
+-- What the recipes called for before yield loss, amount being what was taken from stock
+ALTER TABLE order_consumptions ADD COLUMN theoretical_amount NUMERIC(10, 2);
+UPDATE order_consumptions SET theoretical_amount = amount;
+ALTER TABLE order_consumptions
+    ALTER COLUMN theoretical_amount SET NOT NULL,
+    ADD CHECK (theoretical_amount >= 0);
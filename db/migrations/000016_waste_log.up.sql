@@ -0,0 +1,10 @@
+CREATE TABLE waste_entries (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    quantity NUMERIC(10, 2) NOT NULL CHECK (quantity > 0),
+    reason VARCHAR(20) NOT NULL CHECK (reason IN ('spoiled', 'dropped', 'expired', 'comp')),
+    notes TEXT NOT NULL DEFAULT '',
+    recorded_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_waste_entries_recorded_at ON waste_entries (recorded_at);
@@ -0,0 +1,12 @@
+-- Movements are immutable, let the count corrections through as adjustments
+ALTER TABLE stock_movements DISABLE TRIGGER stock_movements_immutable;
+UPDATE stock_movements SET reason = 'adjustment' WHERE reason = 'count';
+ALTER TABLE stock_movements ENABLE TRIGGER stock_movements_immutable;
+
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_reason_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_reason_check
+    CHECK (reason IN ('order', 'cancellation', 'restock', 'adjustment', 'waste'));
+
+DROP TABLE IF EXISTS stock_count_results;
+DROP TABLE IF EXISTS stock_count_entries;
+DROP TABLE IF EXISTS stock_count_sessions;
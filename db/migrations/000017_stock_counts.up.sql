@@ -0,0 +1,32 @@
+CREATE TABLE stock_count_sessions (
+    id SERIAL PRIMARY KEY,
+    opened_by VARCHAR(100) NOT NULL,
+    notes TEXT NOT NULL DEFAULT '',
+    status VARCHAR(10) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'closed')),
+    opened_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    closed_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Each member of staff keeps one count per ingredient, counts of several
+-- staff add up as they cover different storage areas
+CREATE TABLE stock_count_entries (
+    session_id INTEGER NOT NULL REFERENCES stock_count_sessions(id),
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    counted_by VARCHAR(100) NOT NULL,
+    quantity NUMERIC(10, 2) NOT NULL CHECK (quantity >= 0),
+    counted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (session_id, ingredient_id, counted_by)
+);
+
+-- The stock the system had for each counted ingredient when it was last counted
+CREATE TABLE stock_count_results (
+    session_id INTEGER NOT NULL REFERENCES stock_count_sessions(id),
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    system_stock NUMERIC(10, 2) NOT NULL,
+    counted NUMERIC(10, 2) NOT NULL,
+    PRIMARY KEY (session_id, ingredient_id)
+);
+
+ALTER TABLE stock_movements DROP CONSTRAINT stock_movements_reason_check;
+ALTER TABLE stock_movements ADD CONSTRAINT stock_movements_reason_check
+    CHECK (reason IN ('order', 'cancellation', 'restock', 'adjustment', 'waste', 'count'));
@@ -0,0 +1,23 @@
+CREATE TABLE ingredient_lots (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    receipt_id INTEGER REFERENCES stock_receipts(id),
+    lot_number VARCHAR(100) NOT NULL DEFAULT '',
+    expires_at TIMESTAMP WITH TIME ZONE,
+    received_quantity NUMERIC(10, 2) NOT NULL CHECK (received_quantity >= 0),
+    remaining_quantity NUMERIC(10, 2) NOT NULL CHECK (remaining_quantity >= 0 AND remaining_quantity <= received_quantity),
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (receipt_id, ingredient_id)
+);
+
+-- Stock that came in without a receipt, such as the opening stock or the
+-- stock of cancelled orders, is kept in a single undated lot per ingredient
+CREATE UNIQUE INDEX idx_ingredient_lots_loose ON ingredient_lots (ingredient_id) WHERE receipt_id IS NULL;
+CREATE INDEX idx_ingredient_lots_fefo ON ingredient_lots (ingredient_id, expires_at, id) WHERE remaining_quantity > 0;
+CREATE INDEX idx_ingredient_lots_expiry ON ingredient_lots (expires_at) WHERE remaining_quantity > 0;
+
+-- Open the lots with the stock on hand so they add up to current_stock
+INSERT INTO ingredient_lots (ingredient_id, received_quantity, remaining_quantity)
+SELECT id, current_stock, current_stock
+FROM ingredients
+WHERE current_stock > 0;
@@ -0,0 +1,8 @@
+-- A lot raises a single expiry alert per alert window, the window is part of
+-- the key so that widening it alerts the lots it newly covers
+CREATE TABLE lot_expiry_alerts (
+    lot_id INTEGER NOT NULL REFERENCES ingredient_lots(id),
+    window_days INTEGER NOT NULL CHECK (window_days > 0),
+    alerted_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (lot_id, window_days)
+);
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS supplier_ingredients;
+DROP TABLE IF EXISTS suppliers;
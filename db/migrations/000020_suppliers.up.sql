@@ -0,0 +1,24 @@
+CREATE TABLE suppliers (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    contact_email VARCHAR(254) NOT NULL,
+    lead_time_days INTEGER NOT NULL DEFAULT 0 CHECK (lead_time_days >= 0),
+    minimum_order NUMERIC(10, 2) NOT NULL DEFAULT 0 CHECK (minimum_order >= 0),
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_suppliers_name ON suppliers (LOWER(name));
+
+-- The price list of a supplier, unit_price is per base unit of the ingredient
+-- so that offers in different pack sizes compare directly
+CREATE TABLE supplier_ingredients (
+    supplier_id INTEGER NOT NULL REFERENCES suppliers(id) ON DELETE CASCADE,
+    ingredient_id INTEGER NOT NULL REFERENCES ingredients(id),
+    pack_size NUMERIC(10, 2) NOT NULL CHECK (pack_size > 0),
+    unit_price NUMERIC(12, 4) NOT NULL CHECK (unit_price >= 0),
+    preferred BOOLEAN NOT NULL DEFAULT false,
+    PRIMARY KEY (supplier_id, ingredient_id)
+);
+
+-- An ingredient has at most one preferred supplier
+CREATE UNIQUE INDEX idx_supplier_ingredients_preferred ON supplier_ingredients (ingredient_id) WHERE preferred;
@@ -0,0 +1,9 @@
+-- Fails while a receipt lists an ingredient more than once
+ALTER TABLE ingredient_lots
+    DROP COLUMN receipt_item_id,
+    ADD CONSTRAINT ingredient_lots_receipt_id_ingredient_id_key UNIQUE (receipt_id, ingredient_id);
+
+DROP INDEX IF EXISTS idx_stock_receipt_items_receipt;
+ALTER TABLE stock_receipt_items
+    DROP COLUMN id,
+    ADD PRIMARY KEY (receipt_id, ingredient_id);
@@ -0,0 +1,16 @@
+-- A delivery may bring an ingredient in several lots with their own expiry,
+-- so receipt items are keyed by their own id and each lot received on a
+-- receipt points at the item it came in with
+ALTER TABLE stock_receipt_items
+    DROP CONSTRAINT stock_receipt_items_pkey,
+    ADD COLUMN id SERIAL PRIMARY KEY;
+CREATE INDEX idx_stock_receipt_items_receipt ON stock_receipt_items (receipt_id);
+
+ALTER TABLE ingredient_lots
+    DROP CONSTRAINT ingredient_lots_receipt_id_ingredient_id_key,
+    ADD COLUMN receipt_item_id INTEGER UNIQUE REFERENCES stock_receipt_items(id);
+
+UPDATE ingredient_lots l
+SET receipt_item_id = ri.id
+FROM stock_receipt_items ri
+WHERE ri.receipt_id = l.receipt_id AND ri.ingredient_id = l.ingredient_id;
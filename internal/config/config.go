@@ -1,6 +1,8 @@
 package config
 
 import (
+	"time"
+
 	_ "github.com/lib/pq"
 	"github.com/spf13/viper"
 )
@@ -18,6 +20,19 @@ type Config struct {
 	EmailSenderAddress  string `mapstructure:"EMAIL_SENDER_ADDRESS"`
 	EmailSenderPassword string `mapstructure:"EMAIL_SENDER_PASSWORD"`
 	TestMerchantEmail   string `mapstructure:"TEST_MERCHANT_EMAIL"`
+	// LowStockThresholdPercent is the default percentage of the total stock
+	// below which an ingredient without its own threshold is considered low
+	LowStockThresholdPercent float64 `mapstructure:"LOW_STOCK_THRESHOLD_PERCENT"`
+	// IdempotencyKeyTTL is how long an Idempotency-Key of a created order is remembered
+	IdempotencyKeyTTL time.Duration `mapstructure:"IDEMPOTENCY_KEY_TTL"`
+	// IdempotencyPurgeSchedule is the cron spec of the purge of expired Idempotency-Keys
+	IdempotencyPurgeSchedule string `mapstructure:"IDEMPOTENCY_PURGE_SCHEDULE"`
+	// OutboxRelayInterval is how often pending outbox messages are published to the task queue
+	OutboxRelayInterval time.Duration `mapstructure:"OUTBOX_RELAY_INTERVAL"`
+	// ExpiryAlertSchedule is the cron spec of the scan for lots expiring soon
+	ExpiryAlertSchedule string `mapstructure:"EXPIRY_ALERT_SCHEDULE"`
+	// ExpiryAlertWindowDays is how many days ahead of their expiry lots are alerted
+	ExpiryAlertWindowDays int `mapstructure:"EXPIRY_ALERT_WINDOW_DAYS"`
 }
 
 // LoadConfig read configuration from the file or environment variables
@@ -27,6 +42,13 @@ func LoadConfig(path, name string) (config Config, err error) {
 	viper.AddConfigPath(path)  // Add the current working directory as a search path
 	viper.AutomaticEnv()       // Load environment variables from the system
 
+	viper.SetDefault("LOW_STOCK_THRESHOLD_PERCENT", 50)
+	viper.SetDefault("IDEMPOTENCY_KEY_TTL", "24h")
+	viper.SetDefault("IDEMPOTENCY_PURGE_SCHEDULE", "@daily")
+	viper.SetDefault("OUTBOX_RELAY_INTERVAL", "5s")
+	viper.SetDefault("EXPIRY_ALERT_SCHEDULE", "@hourly")
+	viper.SetDefault("EXPIRY_ALERT_WINDOW_DAYS", 3)
+
 	err = viper.ReadInConfig() // Read the configuration from the .env file
 	if err != nil {
 		return
@@ -0,0 +1,635 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/service"
+	"stockk/internal/validator"
+
+	"github.com/go-chi/render"
+)
+
+type IngredientController struct {
+	ingredientService service.IngredientService
+}
+
+func NewIngredientController(ingredientService service.IngredientService) *IngredientController {
+	return &IngredientController{
+		ingredientService: ingredientService,
+	}
+}
+
+const (
+	defaultMovementPageSize = 50
+	maxMovementPageSize     = 200
+	defaultExpiryWindowDays = 7
+	maxExpiryWindowDays     = 365
+)
+
+type lowStockThresholdRequest struct {
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+}
+
+type createIngredientRequest struct {
+	Name              string                    `json:"name"`
+	Unit              string                    `json:"unit"`
+	TotalStock        float64                   `json:"total_stock"`
+	CurrentStock      float64                   `json:"current_stock"`
+	LowStockThreshold *lowStockThresholdRequest `json:"low_stock_threshold"`
+	YieldPercent      *float64                  `json:"yield_percent"`
+}
+
+type createPackRequest struct {
+	Label string  `json:"label"`
+	Size  float64 `json:"size"`
+}
+
+type prepRecipeRequest struct {
+	Yield       float64             `json:"yield"`
+	Ingredients []recipeLineRequest `json:"ingredients"`
+}
+
+type renameIngredientRequest struct {
+	Name string `json:"name"`
+}
+
+type updateTotalStockRequest struct {
+	TotalStock float64 `json:"total_stock"`
+}
+
+type setYieldRequest struct {
+	YieldPercent float64 `json:"yield_percent"`
+}
+
+func (ic *IngredientController) ListIngredients(w http.ResponseWriter, r *http.Request) {
+	ingredients, err := ic.ingredientService.ListIngredients(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredients)
+}
+
+func (ic *IngredientController) GetIngredient(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredient, err := ic.ingredientService.GetIngredient(r.Context(), ingredientID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) CreateIngredient(w http.ResponseWriter, r *http.Request) {
+	var request createIngredientRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateCreateIngredientRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredient, err := ic.ingredientService.CreateIngredient(r.Context(), &models.Ingredient{
+		Name:              request.Name,
+		Unit:              request.Unit,
+		TotalStock:        request.TotalStock,
+		CurrentStock:      request.CurrentStock,
+		LowStockThreshold: toLowStockThreshold(request.LowStockThreshold),
+		YieldPercent:      request.YieldPercent,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) RenameIngredient(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request renameIngredientRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateName(request.Name); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ingredient name",
+			err.Error(),
+		))
+		return
+	}
+
+	ingredient, err := ic.ingredientService.RenameIngredient(r.Context(), ingredientID, request.Name)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) UpdateTotalStock(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request updateTotalStockRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateStock(request.TotalStock); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid total stock",
+			err.Error(),
+		))
+		return
+	}
+
+	ingredient, err := ic.ingredientService.UpdateTotalStock(r.Context(), ingredientID, request.TotalStock)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) ArchiveIngredient(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := ic.ingredientService.ArchiveIngredient(r.Context(), ingredientID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ic *IngredientController) SetLowStockThreshold(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request lowStockThresholdRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateLowStockThreshold(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	threshold := toLowStockThreshold(&request)
+	ingredient, err := ic.ingredientService.SetLowStockThreshold(r.Context(), ingredientID, &threshold)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) ResetLowStockThreshold(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredient, err := ic.ingredientService.SetLowStockThreshold(r.Context(), ingredientID, nil)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) SetYield(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request setYieldRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateYieldPercent(&request.YieldPercent); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredient, err := ic.ingredientService.SetYieldPercent(r.Context(), ingredientID, &request.YieldPercent)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) ResetYield(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredient, err := ic.ingredientService.SetYieldPercent(r.Context(), ingredientID, nil)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, ingredient)
+}
+
+func (ic *IngredientController) ListStockMovements(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	filter, err := parseStockMovementFilter(r)
+	if err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+	filter.IngredientID = ingredientID
+
+	page, err := ic.ingredientService.ListStockMovements(r.Context(), filter)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, page)
+}
+
+func (ic *IngredientController) ListLots(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	lots, err := ic.ingredientService.ListLots(r.Context(), ingredientID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, lots)
+}
+
+func (ic *IngredientController) ListExpiringLots(w http.ResponseWriter, r *http.Request) {
+	days, err := parseIntQuery(r, "days")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if days == 0 {
+		days = defaultExpiryWindowDays
+	}
+	if days > maxExpiryWindowDays {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			fmt.Sprintf("Query parameter \"days\" must not exceed %d", maxExpiryWindowDays),
+		))
+		return
+	}
+
+	lots, err := ic.ingredientService.ListExpiringLots(r.Context(), days)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, lots)
+}
+
+func (ic *IngredientController) ListPacks(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	packs, err := ic.ingredientService.ListPacks(r.Context(), ingredientID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, packs)
+}
+
+func (ic *IngredientController) CreatePack(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request createPackRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateName(request.Label); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid pack label",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := validator.ValidateAmount(request.Size); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid pack size",
+			err.Error(),
+		))
+		return
+	}
+
+	pack, err := ic.ingredientService.CreatePack(r.Context(), &models.IngredientPack{
+		IngredientID: ingredientID,
+		Label:        request.Label,
+		Size:         request.Size,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, pack)
+}
+
+func (ic *IngredientController) DeletePack(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	packID, err := parseIDParam(r, "packID")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := ic.ingredientService.DeletePack(r.Context(), ingredientID, packID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (ic *IngredientController) GetPrepRecipe(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	recipe, err := ic.ingredientService.GetPrepRecipe(r.Context(), ingredientID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, recipe)
+}
+
+func (ic *IngredientController) SetPrepRecipe(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request prepRecipeRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateAmount(request.Yield); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid prep yield",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := validateRecipe(request.Ingredients); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	lines := make([]models.PrepIngredient, 0, len(request.Ingredients))
+	for _, line := range request.Ingredients {
+		lines = append(lines, models.PrepIngredient{
+			IngredientID: line.IngredientID,
+			Amount:       line.Amount,
+			Unit:         line.Unit,
+			YieldPercent: line.YieldPercent,
+		})
+	}
+
+	recipe, err := ic.ingredientService.SetPrepRecipe(r.Context(), &models.PrepRecipe{
+		IngredientID: ingredientID,
+		Yield:        request.Yield,
+		Ingredients:  lines,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, recipe)
+}
+
+func (ic *IngredientController) DeletePrepRecipe(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := ic.ingredientService.DeletePrepRecipe(r.Context(), ingredientID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseStockMovementFilter reads the pagination and date range query parameters of a movement listing.
+func parseStockMovementFilter(r *http.Request) (models.StockMovementFilter, error) {
+	var filter models.StockMovementFilter
+	var err error
+
+	if filter.Cursor, err = parseIntQuery(r, "cursor"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = parseIntQuery(r, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseTimeQuery(r, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(r, "to"); err != nil {
+		return filter, err
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultMovementPageSize
+	}
+	if filter.Limit > maxMovementPageSize {
+		return filter, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			fmt.Sprintf("Query parameter \"limit\" must not exceed %d", maxMovementPageSize),
+		)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			"Query parameter \"from\" must be before \"to\"",
+		)
+	}
+
+	return filter, nil
+}
+
+// validateCreateIngredientRequest validates the incoming ingredient creation request.
+func validateCreateIngredientRequest(request *createIngredientRequest) error {
+	if err := validator.ValidateName(request.Name); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ingredient name",
+			err.Error(),
+		)
+	}
+
+	// Ingredients created before units existed were all measured in grams
+	if request.Unit == "" {
+		request.Unit = models.UnitGram
+	}
+	if err := validator.ValidateBaseUnit(request.Unit); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ingredient unit",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateStock(request.TotalStock); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid total stock",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateStock(request.CurrentStock); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid current stock",
+			err.Error(),
+		)
+	}
+
+	if err := validateYieldPercent(request.YieldPercent); err != nil {
+		return err
+	}
+
+	if request.LowStockThreshold != nil {
+		return validateLowStockThreshold(request.LowStockThreshold)
+	}
+
+	return nil
+}
+
+// validateLowStockThreshold validates a low stock threshold setting.
+func validateLowStockThreshold(request *lowStockThresholdRequest) error {
+	if err := validator.ValidateThreshold(request.Type, request.Value); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid low stock threshold",
+			err.Error(),
+		)
+	}
+	return nil
+}
+
+// toLowStockThreshold converts an optional threshold setting, falling back to the default threshold.
+func toLowStockThreshold(request *lowStockThresholdRequest) models.LowStockThreshold {
+	if request == nil {
+		return models.LowStockThreshold{Default: true}
+	}
+	return models.LowStockThreshold{Type: request.Type, Value: request.Value}
+}
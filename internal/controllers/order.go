@@ -1,8 +1,11 @@
 package controllers
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 
 	"log/slog"
@@ -16,20 +19,26 @@ import (
 )
 
 type OrderController struct {
-	orderService      service.OrderService
-	ingredientService service.IngredientService
+	orderService service.OrderService
 }
 
 func NewOrderController(
 	orderService service.OrderService,
-	ingredientService service.IngredientService,
 ) *OrderController {
 	return &OrderController{
-		orderService:      orderService,
-		ingredientService: ingredientService,
+		orderService: orderService,
 	}
 }
 
+const (
+	defaultOrderPageSize = 20
+	maxOrderPageSize     = 100
+
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+)
+
 type orderRequest struct {
 	Products []models.OrderItem `json:"products"`
 }
@@ -51,16 +60,28 @@ func (oc *OrderController) CreateOrder(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Create order and update ingredient stocks
-	order, err := oc.orderService.CreateOrder(r.Context(), orderRequest.Products)
+	// Create order and update ingredient stocks, at most once per idempotency key
+	var order *models.Order
+	var replayed bool
+	var err error
+	if key := r.Header.Get(idempotencyKeyHeader); key != "" {
+		if err := validateIdempotencyKey(key); err != nil {
+			slog.Error("Request validation failed", "error", err)
+			handleServiceError(w, err)
+			return
+		}
+		order, replayed, err = oc.orderService.CreateOrderIdempotent(r.Context(), key, hashOrderRequest(&orderRequest), orderRequest.Products)
+	} else {
+		order, err = oc.orderService.CreateOrder(r.Context(), orderRequest.Products)
+	}
 	if err != nil {
 		handleServiceError(w, err)
 		return
 	}
 
-	// Check ingredient levels and send alerts if necessary
-	if err := oc.ingredientService.CheckIngredientLevelsAndAlert(r.Context()); err != nil {
-		slog.Warn("Failed to send ingredient alert email", "error", err)
+	if replayed {
+		// The order was created by an earlier request, stock was not touched again
+		w.Header().Set(idempotentReplayedHeader, "true")
 	}
 
 	// Respond with the created order
@@ -68,6 +89,145 @@ func (oc *OrderController) CreateOrder(w http.ResponseWriter, r *http.Request) {
 	render.JSON(w, r, order)
 }
 
+// PreviewOrder reports the stock impact of an order without placing it.
+func (oc *OrderController) PreviewOrder(w http.ResponseWriter, r *http.Request) {
+	var orderRequest orderRequest
+
+	// Decode the request body
+	if err := json.NewDecoder(r.Body).Decode(&orderRequest); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, errors.New("Invalid request payload"))
+		return
+	}
+
+	// Validate the request like a real order
+	if err := validateCreateOrderRequest(&orderRequest); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	preview, err := oc.orderService.PreviewOrder(r.Context(), orderRequest.Products)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, preview)
+}
+
+func (oc *OrderController) GetOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	order, err := oc.orderService.GetOrder(r.Context(), orderID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, order)
+}
+
+func (oc *OrderController) ListOrders(w http.ResponseWriter, r *http.Request) {
+	filter, err := parseOrderFilter(r)
+	if err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	page, err := oc.orderService.ListOrders(r.Context(), filter)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, page)
+}
+
+func (oc *OrderController) CancelOrder(w http.ResponseWriter, r *http.Request) {
+	orderID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	order, err := oc.orderService.CancelOrder(r.Context(), orderID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, order)
+}
+
+// parseOrderFilter builds the order listing filter from the query string.
+func parseOrderFilter(r *http.Request) (models.OrderFilter, error) {
+	var filter models.OrderFilter
+	var err error
+
+	if filter.Cursor, err = parseIntQuery(r, "cursor"); err != nil {
+		return filter, err
+	}
+	if filter.Limit, err = parseIntQuery(r, "limit"); err != nil {
+		return filter, err
+	}
+	if filter.ProductID, err = parseIntQuery(r, "product_id"); err != nil {
+		return filter, err
+	}
+	if filter.From, err = parseTimeQuery(r, "from"); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseTimeQuery(r, "to"); err != nil {
+		return filter, err
+	}
+
+	if filter.Limit == 0 {
+		filter.Limit = defaultOrderPageSize
+	}
+	if filter.Limit > maxOrderPageSize {
+		return filter, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			fmt.Sprintf("Query parameter \"limit\" must not exceed %d", maxOrderPageSize),
+		)
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return filter, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			"Query parameter \"from\" must be before \"to\"",
+		)
+	}
+
+	return filter, nil
+}
+
+// validateIdempotencyKey validates the Idempotency-Key header of a request.
+func validateIdempotencyKey(key string) error {
+	if len(key) > maxIdempotencyKeyLength {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid Idempotency-Key header",
+			fmt.Sprintf("Idempotency-Key must not exceed %d characters", maxIdempotencyKeyLength),
+		)
+	}
+	return nil
+}
+
+// hashOrderRequest fingerprints the decoded order request, so retries that
+// only differ in formatting are recognised as the same request.
+func hashOrderRequest(orderReq *orderRequest) string {
+	// Encoding a slice of plain structs cannot fail
+	body, _ := json.Marshal(orderReq)
+	sum := sha256.Sum256(body)
+	return hex.EncodeToString(sum[:])
+}
+
 // validateCreateOrderRequest validates the incoming order request.
 func validateCreateOrderRequest(orderReq *orderRequest) error {
 	if orderReq == nil {
@@ -105,5 +265,25 @@ func validateProduct(product models.OrderItem) error {
 		)
 	}
 
+	if product.VariantID != nil {
+		if err := validator.ValidateID(*product.VariantID); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid variant ID",
+				err.Error(),
+			)
+		}
+	}
+
+	for _, modifierID := range product.Modifiers {
+		if err := validator.ValidateID(modifierID); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid modifier ID",
+				err.Error(),
+			)
+		}
+	}
+
 	return nil
 }
@@ -0,0 +1,661 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/service"
+	"stockk/internal/validator"
+
+	"github.com/go-chi/render"
+)
+
+type ProductController struct {
+	productService service.ProductService
+}
+
+func NewProductController(productService service.ProductService) *ProductController {
+	return &ProductController{
+		productService: productService,
+	}
+}
+
+type recipeLineRequest struct {
+	IngredientID int      `json:"ingredient_id"`
+	Amount       float64  `json:"amount"`
+	Unit         string   `json:"unit"`          // defaults to the ingredient base unit
+	YieldPercent *float64 `json:"yield_percent"` // defaults to the ingredient yield
+}
+
+type createProductRequest struct {
+	Name        string              `json:"name"`
+	Ingredients []recipeLineRequest `json:"ingredients"`
+}
+
+type replaceRecipeRequest struct {
+	Ingredients []recipeLineRequest `json:"ingredients"`
+}
+
+type setProductIngredientRequest struct {
+	Amount       float64  `json:"amount"`
+	Unit         string   `json:"unit"`          // defaults to the ingredient base unit
+	YieldPercent *float64 `json:"yield_percent"` // defaults to the ingredient yield
+}
+
+type createModifierRequest struct {
+	Name                    string  `json:"name"`
+	Action                  string  `json:"action"`
+	IngredientID            int     `json:"ingredient_id"`
+	ReplacementIngredientID *int    `json:"replacement_ingredient_id"`
+	Amount                  float64 `json:"amount"`
+	Unit                    string  `json:"unit"` // defaults to the base unit of the ingredient added
+}
+
+type createVariantRequest struct {
+	Name       string              `json:"name"`
+	Multiplier *float64            `json:"multiplier"` // defaults to 1
+	Overrides  []recipeLineRequest `json:"overrides"`
+}
+
+type setAvailabilityRequest struct {
+	MarkedUnavailable *bool `json:"marked_unavailable"`
+}
+
+func (pc *ProductController) ListProducts(w http.ResponseWriter, r *http.Request) {
+	products, err := pc.productService.ListProducts(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, products)
+}
+
+func (pc *ProductController) GetProduct(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	product, err := pc.productService.GetProduct(r.Context(), productID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, product)
+}
+
+func (pc *ProductController) CreateProduct(w http.ResponseWriter, r *http.Request) {
+	var request createProductRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateName(request.Name); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid product name",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := validateRecipe(request.Ingredients); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	product, err := pc.productService.CreateProduct(r.Context(), &models.Product{
+		Name:        request.Name,
+		Ingredients: toProductIngredients(0, request.Ingredients),
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, product)
+}
+
+func (pc *ProductController) ReplaceRecipe(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request replaceRecipeRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateRecipe(request.Ingredients); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	product, err := pc.productService.ReplaceProductIngredients(r.Context(), productID, toProductIngredients(productID, request.Ingredients))
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, product)
+}
+
+func (pc *ProductController) SetProductIngredient(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredientID, err := parseIDParam(r, "ingredientID")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request setProductIngredientRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateAmount(request.Amount); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ingredient amount",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := validateRecipeUnit(request.Unit); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateYieldPercent(request.YieldPercent); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	product, err := pc.productService.SetProductIngredient(r.Context(), models.ProductIngredient{
+		ProductID:    productID,
+		IngredientID: ingredientID,
+		Amount:       request.Amount,
+		Unit:         request.Unit,
+		YieldPercent: request.YieldPercent,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, product)
+}
+
+func (pc *ProductController) RemoveProductIngredient(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredientID, err := parseIDParam(r, "ingredientID")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	product, err := pc.productService.RemoveProductIngredient(r.Context(), productID, ingredientID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, product)
+}
+
+func (pc *ProductController) GetProductCapacity(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	variantID, err := parseIntQuery(r, "variant_id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var variant *int
+	if variantID > 0 {
+		variant = &variantID
+	}
+
+	capacity, err := pc.productService.GetProductCapacity(r.Context(), productID, variant)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, capacity)
+}
+
+func (pc *ProductController) ListProductCapacities(w http.ResponseWriter, r *http.Request) {
+	capacities, err := pc.productService.ListProductCapacities(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, capacities)
+}
+
+func (pc *ProductController) ListMenuAvailability(w http.ResponseWriter, r *http.Request) {
+	menu, err := pc.productService.ListMenuAvailability(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, menu)
+}
+
+func (pc *ProductController) SetAvailability(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request setAvailabilityRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if request.MarkedUnavailable == nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid availability",
+			"marked_unavailable is required",
+		))
+		return
+	}
+
+	product, err := pc.productService.SetProductMarkedUnavailable(r.Context(), productID, *request.MarkedUnavailable)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, product)
+}
+
+func (pc *ProductController) ListModifiers(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	modifiers, err := pc.productService.ListModifiers(r.Context(), productID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, modifiers)
+}
+
+func (pc *ProductController) CreateModifier(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request createModifierRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateCreateModifierRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	modifier, err := pc.productService.CreateModifier(r.Context(), &models.ProductModifier{
+		ProductID:               productID,
+		Name:                    request.Name,
+		Action:                  request.Action,
+		IngredientID:            request.IngredientID,
+		ReplacementIngredientID: request.ReplacementIngredientID,
+		Amount:                  request.Amount,
+		Unit:                    request.Unit,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, modifier)
+}
+
+func (pc *ProductController) DeleteModifier(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	modifierID, err := parseIDParam(r, "modifierID")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := pc.productService.DeleteModifier(r.Context(), productID, modifierID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (pc *ProductController) ListVariants(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	variants, err := pc.productService.ListVariants(r.Context(), productID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, variants)
+}
+
+func (pc *ProductController) CreateVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request createVariantRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateCreateVariantRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	variant := &models.ProductVariant{
+		ProductID:  productID,
+		Name:       request.Name,
+		Multiplier: 1,
+		Overrides:  make([]models.VariantIngredient, 0, len(request.Overrides)),
+	}
+	if request.Multiplier != nil {
+		variant.Multiplier = *request.Multiplier
+	}
+	for _, line := range request.Overrides {
+		variant.Overrides = append(variant.Overrides, models.VariantIngredient{
+			IngredientID: line.IngredientID,
+			Amount:       line.Amount,
+			Unit:         line.Unit,
+		})
+	}
+
+	variant, err = pc.productService.CreateVariant(r.Context(), variant)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, variant)
+}
+
+func (pc *ProductController) DeleteVariant(w http.ResponseWriter, r *http.Request) {
+	productID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	variantID, err := parseIDParam(r, "variantID")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := pc.productService.DeleteVariant(r.Context(), productID, variantID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateCreateVariantRequest validates a product variant definition.
+func validateCreateVariantRequest(request *createVariantRequest) error {
+	if err := validator.ValidateName(request.Name); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid variant name",
+			err.Error(),
+		)
+	}
+
+	if request.Multiplier != nil {
+		if err := validator.ValidateAmount(*request.Multiplier); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid variant multiplier",
+				err.Error(),
+			)
+		}
+	}
+
+	for _, line := range request.Overrides {
+		if line.YieldPercent != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid variant",
+				"Overrides keep the yield of the product recipe line",
+			)
+		}
+	}
+
+	return validateRecipe(request.Overrides)
+}
+
+// validateCreateModifierRequest validates a product modifier definition, an
+// add or a replace needs an amount, only a replace names a replacement.
+func validateCreateModifierRequest(request *createModifierRequest) error {
+	if err := validator.ValidateName(request.Name); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid modifier name",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateModifierAction(request.Action); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid modifier action",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateID(request.IngredientID); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ingredient ID",
+			err.Error(),
+		)
+	}
+
+	if request.Action == models.ModifierActionReplace {
+		if request.ReplacementIngredientID == nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid replacement ingredient ID",
+				"A replace modifier needs a replacement_ingredient_id",
+			)
+		}
+		if err := validator.ValidateID(*request.ReplacementIngredientID); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid replacement ingredient ID",
+				err.Error(),
+			)
+		}
+		if *request.ReplacementIngredientID == request.IngredientID {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid replacement ingredient ID",
+				"An ingredient cannot replace itself",
+			)
+		}
+	} else if request.ReplacementIngredientID != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid replacement ingredient ID",
+			fmt.Sprintf("Only a %s modifier takes a replacement_ingredient_id", models.ModifierActionReplace),
+		)
+	}
+
+	if request.Action == models.ModifierActionRemove {
+		return nil
+	}
+
+	if err := validator.ValidateAmount(request.Amount); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid modifier amount",
+			err.Error(),
+		)
+	}
+
+	return validateRecipeUnit(request.Unit)
+}
+
+// validateRecipe validates the recipe lines of a product request.
+func validateRecipe(lines []recipeLineRequest) error {
+	seen := make(map[int]bool, len(lines))
+	for _, line := range lines {
+		if err := validator.ValidateID(line.IngredientID); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid ingredient ID",
+				err.Error(),
+			)
+		}
+
+		if err := validator.ValidateAmount(line.Amount); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid ingredient amount",
+				err.Error(),
+			)
+		}
+
+		if err := validateRecipeUnit(line.Unit); err != nil {
+			return err
+		}
+
+		if err := validateYieldPercent(line.YieldPercent); err != nil {
+			return err
+		}
+
+		if seen[line.IngredientID] {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Duplicate recipe ingredient",
+				fmt.Sprintf("Ingredient with ID %d is listed more than once", line.IngredientID),
+			)
+		}
+		seen[line.IngredientID] = true
+	}
+
+	return nil
+}
+
+// validateRecipeUnit validates the optional unit of a recipe amount, whether
+// it suits the ingredient is checked against its base unit by the service.
+func validateRecipeUnit(unit string) error {
+	if unit == "" {
+		return nil
+	}
+	if err := validator.ValidateUnit(unit); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ingredient unit",
+			err.Error(),
+		)
+	}
+	return nil
+}
+
+// validateYieldPercent validates an optional yield percentage.
+func validateYieldPercent(yieldPercent *float64) error {
+	if yieldPercent == nil {
+		return nil
+	}
+	if err := validator.ValidateYieldPercent(*yieldPercent); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid yield percentage",
+			err.Error(),
+		)
+	}
+	return nil
+}
+
+func toProductIngredients(productID int, lines []recipeLineRequest) []models.ProductIngredient {
+	ingredients := make([]models.ProductIngredient, 0, len(lines))
+	for _, line := range lines {
+		ingredients = append(ingredients, models.ProductIngredient{
+			ProductID:    productID,
+			IngredientID: line.IngredientID,
+			Amount:       line.Amount,
+			Unit:         line.Unit,
+			YieldPercent: line.YieldPercent,
+		})
+	}
+	return ingredients
+}
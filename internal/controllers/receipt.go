@@ -0,0 +1,182 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/service"
+	"stockk/internal/validator"
+
+	"github.com/go-chi/render"
+)
+
+const maxLotNumberLength = 100
+
+type ReceiptController struct {
+	receiptService service.ReceiptService
+}
+
+func NewReceiptController(receiptService service.ReceiptService) *ReceiptController {
+	return &ReceiptController{
+		receiptService: receiptService,
+	}
+}
+
+type receiptRequest struct {
+	ReceivedBy string                    `json:"received_by"`
+	Notes      string                    `json:"notes"`
+	Items      []models.StockReceiptItem `json:"items"`
+}
+
+func (rc *ReceiptController) CreateReceipt(w http.ResponseWriter, r *http.Request) {
+	var request receiptRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateReceiptRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	receipt, err := rc.receiptService.ReceiveStock(r.Context(), &models.StockReceipt{
+		ReceivedBy: request.ReceivedBy,
+		Notes:      request.Notes,
+		Items:      request.Items,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, receipt)
+}
+
+func (rc *ReceiptController) GetReceipt(w http.ResponseWriter, r *http.Request) {
+	receiptID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	receipt, err := rc.receiptService.GetReceipt(r.Context(), receiptID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, receipt)
+}
+
+// validateReceiptRequest validates the incoming goods receipt request.
+func validateReceiptRequest(request *receiptRequest) error {
+	if err := validator.ValidateName(request.ReceivedBy); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid receiver",
+			err.Error(),
+		)
+	}
+
+	if len(request.Items) == 0 {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid receipt items",
+			"At least one item must be received",
+		)
+	}
+
+	// An ingredient may come in several lots, each lot is listed once
+	type receiptLot struct {
+		ingredientID int
+		lotNumber    string
+		expiresAt    time.Time
+	}
+	seen := make(map[receiptLot]bool, len(request.Items))
+	for _, item := range request.Items {
+		if err := validator.ValidateID(item.IngredientID); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid ingredient ID",
+				err.Error(),
+			)
+		}
+
+		if err := validateReceiptQuantity(item); err != nil {
+			return err
+		}
+
+		if len(strings.TrimSpace(item.LotNumber)) > maxLotNumberLength {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid lot number",
+				fmt.Sprintf("Lot number must not exceed %d characters", maxLotNumberLength),
+			)
+		}
+
+		lot := receiptLot{ingredientID: item.IngredientID, lotNumber: strings.TrimSpace(item.LotNumber)}
+		if item.ExpiresAt != nil {
+			lot.expiresAt = item.ExpiresAt.UTC()
+		}
+		if seen[lot] {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Duplicate receipt item",
+				fmt.Sprintf("Ingredient with ID %d is listed more than once with the same lot number and expiry", item.IngredientID),
+			)
+		}
+		seen[lot] = true
+	}
+
+	return nil
+}
+
+// validateReceiptQuantity validates the quantity of a receipt item, given
+// either in the ingredient base unit or as a number of packs.
+func validateReceiptQuantity(item models.StockReceiptItem) error {
+	if item.PackID == nil {
+		if err := validator.ValidateAmount(item.Quantity); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid received quantity",
+				err.Error(),
+			)
+		}
+		return nil
+	}
+
+	if item.Quantity != 0 {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid received quantity",
+			"Either quantity or pack_id and packs must be given, not both",
+		)
+	}
+
+	if err := validator.ValidateID(*item.PackID); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid pack ID",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateAmount(item.Packs); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid received packs",
+			err.Error(),
+		)
+	}
+
+	return nil
+}
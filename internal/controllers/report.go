@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	"stockk/internal/models"
+	"stockk/internal/service"
+
+	"github.com/go-chi/render"
+)
+
+type ReportController struct {
+	reportService service.ReportService
+}
+
+func NewReportController(reportService service.ReportService) *ReportController {
+	return &ReportController{
+		reportService: reportService,
+	}
+}
+
+func (rc *ReportController) GetUsageVarianceReport(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriodQuery(r)
+	if err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	report, err := rc.reportService.GetUsageVarianceReport(r.Context(), models.UsageReportFilter{From: from, To: to})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, report)
+}
@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/validator"
+
+	"github.com/go-chi/chi/v5"
+)
+
+// decodeJSON decodes the request body into dst, reporting malformed payloads
+// as validation errors.
+func decodeJSON(r *http.Request, dst interface{}) error {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid request payload",
+			err.Error(),
+		)
+	}
+	return nil
+}
+
+// parseIDParam reads a positive integer ID from the named URL parameter.
+func parseIDParam(r *http.Request, name string) (int, error) {
+	id, err := strconv.Atoi(chi.URLParam(r, name))
+	if err == nil {
+		err = validator.ValidateID(id)
+	}
+	if err != nil {
+		return 0, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ID",
+			fmt.Sprintf("URL parameter %q must be a positive integer", name),
+		)
+	}
+	return id, nil
+}
+
+// parseIntQuery reads an optional non-negative integer query parameter,
+// returning 0 when it is absent.
+func parseIntQuery(r *http.Request, name string) (int, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return 0, nil
+	}
+	value, err := strconv.Atoi(raw)
+	if err != nil || value < 0 {
+		return 0, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			fmt.Sprintf("Query parameter %q must be a non-negative integer", name),
+		)
+	}
+	return value, nil
+}
+
+// parseTimeQuery reads an optional RFC 3339 timestamp query parameter,
+// returning nil when it is absent.
+func parseTimeQuery(r *http.Request, name string) (*time.Time, error) {
+	raw := r.URL.Query().Get(name)
+	if raw == "" {
+		return nil, nil
+	}
+	value, err := time.Parse(time.RFC3339, raw)
+	if err != nil {
+		return nil, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			fmt.Sprintf("Query parameter %q must be an RFC 3339 timestamp", name),
+		)
+	}
+	return &value, nil
+}
+
+// parsePeriodQuery reads the optional "from" and "to" RFC 3339 query
+// parameters bounding a report, from must come before to.
+func parsePeriodQuery(r *http.Request) (*time.Time, *time.Time, error) {
+	from, err := parseTimeQuery(r, "from")
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parseTimeQuery(r, "to")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if from != nil && to != nil && !from.Before(*to) {
+		return nil, nil, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid query parameter",
+			"Query parameter \"from\" must be before \"to\"",
+		)
+	}
+
+	return from, to, nil
+}
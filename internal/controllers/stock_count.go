@@ -0,0 +1,204 @@
+package controllers
+
+import (
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/service"
+	"stockk/internal/validator"
+
+	"github.com/go-chi/render"
+)
+
+type StockCountController struct {
+	countService service.StockCountService
+}
+
+func NewStockCountController(countService service.StockCountService) *StockCountController {
+	return &StockCountController{
+		countService: countService,
+	}
+}
+
+type openCountRequest struct {
+	OpenedBy string `json:"opened_by"`
+	Notes    string `json:"notes"`
+}
+
+type countItemRequest struct {
+	IngredientID int     `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+}
+
+type submitCountsRequest struct {
+	CountedBy string             `json:"counted_by"`
+	Items     []countItemRequest `json:"items"`
+}
+
+func (cc *StockCountController) OpenSession(w http.ResponseWriter, r *http.Request) {
+	var request openCountRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateName(request.OpenedBy); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid opener",
+			err.Error(),
+		))
+		return
+	}
+
+	session, err := cc.countService.OpenSession(r.Context(), &models.StockCountSession{
+		OpenedBy: request.OpenedBy,
+		Notes:    request.Notes,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, session)
+}
+
+func (cc *StockCountController) GetSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	session, err := cc.countService.GetSession(r.Context(), sessionID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, session)
+}
+
+func (cc *StockCountController) SubmitCounts(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request submitCountsRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateSubmitCountsRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	counts := make([]models.StockCount, 0, len(request.Items))
+	for _, item := range request.Items {
+		counts = append(counts, models.StockCount{
+			IngredientID: item.IngredientID,
+			CountedBy:    request.CountedBy,
+			Quantity:     item.Quantity,
+		})
+	}
+
+	session, err := cc.countService.SubmitCounts(r.Context(), sessionID, counts)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, session)
+}
+
+func (cc *StockCountController) CloseSession(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	report, err := cc.countService.CloseSession(r.Context(), sessionID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, report)
+}
+
+func (cc *StockCountController) GetVarianceReport(w http.ResponseWriter, r *http.Request) {
+	sessionID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	report, err := cc.countService.GetVarianceReport(r.Context(), sessionID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, report)
+}
+
+// validateSubmitCountsRequest validates the counts a member of staff submits.
+func validateSubmitCountsRequest(request *submitCountsRequest) error {
+	if err := validator.ValidateName(request.CountedBy); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid counter",
+			err.Error(),
+		)
+	}
+
+	if len(request.Items) == 0 {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid count items",
+			"At least one ingredient must be counted",
+		)
+	}
+
+	seen := make(map[int]bool, len(request.Items))
+	for _, item := range request.Items {
+		if err := validator.ValidateID(item.IngredientID); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid ingredient ID",
+				err.Error(),
+			)
+		}
+
+		if err := validator.ValidateStock(item.Quantity); err != nil {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid counted quantity",
+				err.Error(),
+			)
+		}
+
+		if seen[item.IngredientID] {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Duplicate count item",
+				fmt.Sprintf("Ingredient with ID %d is listed more than once", item.IngredientID),
+			)
+		}
+		seen[item.IngredientID] = true
+	}
+
+	return nil
+}
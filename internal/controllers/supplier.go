@@ -0,0 +1,284 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/service"
+	"stockk/internal/validator"
+
+	"github.com/go-chi/render"
+)
+
+type SupplierController struct {
+	supplierService service.SupplierService
+}
+
+func NewSupplierController(supplierService service.SupplierService) *SupplierController {
+	return &SupplierController{
+		supplierService: supplierService,
+	}
+}
+
+type supplierRequest struct {
+	Name         string  `json:"name"`
+	ContactEmail string  `json:"contact_email"`
+	LeadTimeDays int     `json:"lead_time_days"`
+	MinimumOrder float64 `json:"minimum_order"`
+}
+
+type setSupplierIngredientRequest struct {
+	PackSize  float64 `json:"pack_size"`
+	UnitPrice float64 `json:"unit_price"`
+	Preferred bool    `json:"preferred"`
+}
+
+func (sc *SupplierController) CreateSupplier(w http.ResponseWriter, r *http.Request) {
+	var request supplierRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateSupplierRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	supplier, err := sc.supplierService.CreateSupplier(r.Context(), &models.Supplier{
+		Name:         request.Name,
+		ContactEmail: request.ContactEmail,
+		LeadTimeDays: request.LeadTimeDays,
+		MinimumOrder: request.MinimumOrder,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, supplier)
+}
+
+func (sc *SupplierController) ListSuppliers(w http.ResponseWriter, r *http.Request) {
+	suppliers, err := sc.supplierService.ListSuppliers(r.Context())
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, suppliers)
+}
+
+func (sc *SupplierController) GetSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	supplier, err := sc.supplierService.GetSupplier(r.Context(), supplierID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, supplier)
+}
+
+func (sc *SupplierController) UpdateSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request supplierRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateSupplierRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	supplier, err := sc.supplierService.UpdateSupplier(r.Context(), &models.Supplier{
+		ID:           supplierID,
+		Name:         request.Name,
+		ContactEmail: request.ContactEmail,
+		LeadTimeDays: request.LeadTimeDays,
+		MinimumOrder: request.MinimumOrder,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, supplier)
+}
+
+func (sc *SupplierController) DeleteSupplier(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := sc.supplierService.DeleteSupplier(r.Context(), supplierID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (sc *SupplierController) ListSupplierIngredients(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	items, err := sc.supplierService.ListSupplierIngredients(r.Context(), supplierID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, items)
+}
+
+func (sc *SupplierController) SetSupplierIngredient(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredientID, err := parseIDParam(r, "ingredientID")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	var request setSupplierIngredientRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validator.ValidateAmount(request.PackSize); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid pack size",
+			err.Error(),
+		))
+		return
+	}
+
+	if err := validator.ValidatePrice(request.UnitPrice); err != nil {
+		handleServiceError(w, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid unit price",
+			err.Error(),
+		))
+		return
+	}
+
+	items, err := sc.supplierService.SetSupplierIngredient(r.Context(), models.SupplierIngredient{
+		SupplierID:   supplierID,
+		IngredientID: ingredientID,
+		PackSize:     request.PackSize,
+		UnitPrice:    request.UnitPrice,
+		Preferred:    request.Preferred,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, items)
+}
+
+func (sc *SupplierController) RemoveSupplierIngredient(w http.ResponseWriter, r *http.Request) {
+	supplierID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	ingredientID, err := parseIDParam(r, "ingredientID")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := sc.supplierService.RemoveSupplierIngredient(r.Context(), supplierID, ingredientID); err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (sc *SupplierController) ListIngredientSuppliers(w http.ResponseWriter, r *http.Request) {
+	ingredientID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	items, err := sc.supplierService.ListIngredientSuppliers(r.Context(), ingredientID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, items)
+}
+
+// validateSupplierRequest validates the incoming supplier request.
+func validateSupplierRequest(request *supplierRequest) error {
+	if err := validator.ValidateName(request.Name); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid supplier name",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateEmail(request.ContactEmail); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid contact email",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateLeadTime(request.LeadTimeDays); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid lead time",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidatePrice(request.MinimumOrder); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid minimum order",
+			err.Error(),
+		)
+	}
+
+	return nil
+}
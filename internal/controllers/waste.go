@@ -0,0 +1,121 @@
+package controllers
+
+import (
+	"log/slog"
+	"net/http"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/service"
+	"stockk/internal/validator"
+
+	"github.com/go-chi/render"
+)
+
+type WasteController struct {
+	wasteService service.WasteService
+}
+
+func NewWasteController(wasteService service.WasteService) *WasteController {
+	return &WasteController{
+		wasteService: wasteService,
+	}
+}
+
+type wasteRequest struct {
+	IngredientID int     `json:"ingredient_id"`
+	Quantity     float64 `json:"quantity"`
+	Reason       string  `json:"reason"`
+	Notes        string  `json:"notes"`
+}
+
+func (wc *WasteController) RecordWaste(w http.ResponseWriter, r *http.Request) {
+	var request wasteRequest
+	if err := decodeJSON(r, &request); err != nil {
+		slog.Error("Invalid request payload", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	if err := validateWasteRequest(&request); err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	entry, err := wc.wasteService.RecordWaste(r.Context(), &models.WasteEntry{
+		IngredientID: request.IngredientID,
+		Quantity:     request.Quantity,
+		Reason:       request.Reason,
+		Notes:        request.Notes,
+	})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.Status(r, http.StatusCreated)
+	render.JSON(w, r, entry)
+}
+
+func (wc *WasteController) GetWasteEntry(w http.ResponseWriter, r *http.Request) {
+	entryID, err := parseIDParam(r, "id")
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	entry, err := wc.wasteService.GetWasteEntry(r.Context(), entryID)
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, entry)
+}
+
+func (wc *WasteController) GetWasteReport(w http.ResponseWriter, r *http.Request) {
+	from, to, err := parsePeriodQuery(r)
+	if err != nil {
+		slog.Error("Request validation failed", "error", err)
+		handleServiceError(w, err)
+		return
+	}
+
+	report, err := wc.wasteService.GetWasteReport(r.Context(), models.WasteReportFilter{From: from, To: to})
+	if err != nil {
+		handleServiceError(w, err)
+		return
+	}
+
+	render.JSON(w, r, report)
+}
+
+// validateWasteRequest validates the incoming waste entry request.
+func validateWasteRequest(request *wasteRequest) error {
+	if err := validator.ValidateID(request.IngredientID); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid ingredient ID",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateAmount(request.Quantity); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid wasted quantity",
+			err.Error(),
+		)
+	}
+
+	if err := validator.ValidateWasteReason(request.Reason); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid waste reason",
+			err.Error(),
+		)
+	}
+
+	return nil
+}
@@ -56,4 +56,6 @@ const (
 	ErrCodeInternalServer    = 500
 	ErrCodeValidation        = 400
 	ErrCodeInsufficientStock = 409
+	ErrCodeConflict          = 409
+	ErrCodeUnprocessable     = 422
 )
@@ -2,39 +2,574 @@ package models
 
 import "time"
 
+// Low stock threshold types.
+const (
+	ThresholdTypePercentage = "percentage" // percentage of the total stock
+	ThresholdTypeQuantity   = "quantity"   // absolute quantity of stock
+)
+
+// LowStockThreshold is the stock level below which an ingredient is considered low.
+type LowStockThreshold struct {
+	Type  string  `json:"type"`
+	Value float64 `json:"value"`
+	// Default is set when the ingredient has no threshold of its own
+	// and the configured default percentage applies.
+	Default bool `json:"default"`
+}
+
 // Ingredient represents the details of each ingredient.
 type Ingredient struct {
-	ID           int     `json:"id"`
-	Name         string  `json:"name"`
-	TotalStock   float64 `json:"total_stock"`
-	CurrentStock float64 `json:"current_stock"`
-	AlertSent    bool    `json:"alert_sent"`
+	ID                int               `json:"id"`
+	Name              string            `json:"name"`
+	Unit              string            `json:"unit"` // base unit the stock is kept in
+	TotalStock        float64           `json:"total_stock"`
+	CurrentStock      float64           `json:"current_stock"`
+	AlertSent         bool              `json:"alert_sent"`
+	LowStockThreshold LowStockThreshold `json:"low_stock_threshold"`
+	// YieldPercent is the share of the ingredient left for recipes after
+	// peeling, trimming or cooking loss, nil when nothing is lost
+	YieldPercent *float64   `json:"yield_percent,omitempty"`
+	ArchivedAt   *time.Time `json:"archived_at,omitempty"`
+}
+
+// IsLowStockAt reports whether stock would be below the ingredient threshold.
+func (i Ingredient) IsLowStockAt(stock float64) bool {
+	if i.LowStockThreshold.Type == ThresholdTypeQuantity {
+		return stock < i.LowStockThreshold.Value
+	}
+	return stock < i.TotalStock*i.LowStockThreshold.Value/100
 }
 
 // Product represents the details of each product.
 type Product struct {
-	ID          int                 `json:"id"`
-	Name        string              `json:"name"`
-	Ingredients []ProductIngredient `json:"ingredients"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	// Available is set while the product is in stock and not marked unavailable
+	Available bool `json:"available"`
+	// InStock is computed by the product service from the current stock, it is
+	// cleared while the recipe capacity is below a single unit
+	InStock bool `json:"in_stock"`
+	// MarkedUnavailable takes the product off the menu regardless of stock,
+	// for when the kitchen runs out of something that is not tracked
+	MarkedUnavailable bool                `json:"marked_unavailable"`
+	Ingredients       []ProductIngredient `json:"ingredients"`
+}
+
+// MenuItemAvailability is whether a product can currently be ordered.
+type MenuItemAvailability struct {
+	ProductID         int    `json:"product_id"`
+	Name              string `json:"name"`
+	Available         bool   `json:"available"`
+	InStock           bool   `json:"in_stock"`
+	MarkedUnavailable bool   `json:"marked_unavailable"`
 }
 
 // ProductIngredient represents the relationship between products and ingredients,
 // including the amount of ingredient required for each product.
 type ProductIngredient struct {
-	ProductID    int     `json:"product_id"`
+	ProductID      int     `json:"product_id"`
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name,omitempty"`
+	Amount         float64 `json:"amount"` // Amount of ingredient needed for this product, in Unit
+	Unit           string  `json:"unit"`
+	// IngredientUnit is the base unit the ingredient stock is kept in
+	IngredientUnit string `json:"ingredient_unit,omitempty"`
+	// YieldPercent overrides the yield of the ingredient for this line
+	YieldPercent *float64 `json:"yield_percent,omitempty"`
+	// IngredientYieldPercent is the yield of the ingredient itself
+	IngredientYieldPercent *float64 `json:"ingredient_yield_percent,omitempty"`
+}
+
+// BaseAmount returns the amount of ingredient needed in the ingredient base unit.
+// An empty Unit means the amount is already in the base unit.
+func (pi ProductIngredient) BaseAmount() (float64, error) {
+	if pi.Unit == "" || pi.Unit == pi.IngredientUnit {
+		return pi.Amount, nil
+	}
+	return ConvertAmount(pi.Amount, pi.Unit, pi.IngredientUnit)
+}
+
+// GrossAmount returns the amount of stock to take for amount to be left after
+// the yield loss, the first yield percentage set applies. Without one nothing
+// is lost.
+func GrossAmount(amount float64, yieldPercents ...*float64) float64 {
+	for _, yieldPercent := range yieldPercents {
+		if yieldPercent != nil {
+			return amount * 100 / *yieldPercent
+		}
+	}
+	return amount
+}
+
+// ProductVariant is a size of a product, such as a double burger. Its recipe
+// is the product recipe scaled by Multiplier, except for the ingredients it
+// overrides the amount of.
+type ProductVariant struct {
+	ID         int                 `json:"id"`
+	ProductID  int                 `json:"product_id"`
+	Name       string              `json:"name"`
+	Multiplier float64             `json:"multiplier"`
+	Overrides  []VariantIngredient `json:"overrides"`
+}
+
+// VariantIngredient overrides the amount of an ingredient of the product recipe for a variant.
+type VariantIngredient struct {
+	VariantID    int     `json:"variant_id"`
 	IngredientID int     `json:"ingredient_id"`
-	Amount       float64 `json:"amount"` // Amount of ingredient needed for this product
+	Amount       float64 `json:"amount"` // in Unit
+	Unit         string  `json:"unit"`
+}
+
+// Recipe returns the recipe of the variant from the recipe of its product.
+func (v ProductVariant) Recipe(ingredients []ProductIngredient) []ProductIngredient {
+	overrides := make(map[int]VariantIngredient, len(v.Overrides))
+	for _, override := range v.Overrides {
+		overrides[override.IngredientID] = override
+	}
+
+	recipe := make([]ProductIngredient, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		if override, ok := overrides[ingredient.IngredientID]; ok {
+			ingredient.Amount, ingredient.Unit = override.Amount, override.Unit
+		} else {
+			ingredient.Amount *= v.Multiplier
+		}
+		recipe = append(recipe, ingredient)
+	}
+	return recipe
+}
+
+// Product modifier actions.
+const (
+	ModifierActionAdd     = "add"
+	ModifierActionRemove  = "remove"
+	ModifierActionReplace = "replace"
+)
+
+// ProductModifier is a change to a product recipe customers may ask for when
+// ordering, such as extra cheese or no onion.
+type ProductModifier struct {
+	ID        int    `json:"id"`
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	Action    string `json:"action"`
+	// IngredientID is the ingredient added, removed or replaced
+	IngredientID int `json:"ingredient_id"`
+	// ReplacementIngredientID is the ingredient used instead, for a replace
+	ReplacementIngredientID *int `json:"replacement_ingredient_id,omitempty"`
+	// Amount of ingredient added or of the replacement, in Unit. Unused for a remove.
+	Amount float64 `json:"amount,omitempty"`
+	Unit   string  `json:"unit,omitempty"`
+	// IngredientUnit is the base unit of the ingredient Amount is taken from
+	IngredientUnit string `json:"ingredient_unit,omitempty"`
+	// IngredientYieldPercent is the yield of the ingredient Amount is taken from
+	IngredientYieldPercent *float64 `json:"ingredient_yield_percent,omitempty"`
+}
+
+// AmountIngredientID returns the ingredient Amount is taken from.
+func (m ProductModifier) AmountIngredientID() int {
+	if m.ReplacementIngredientID != nil {
+		return *m.ReplacementIngredientID
+	}
+	return m.IngredientID
 }
 
+// BaseAmount returns Amount in the base unit of the ingredient it is taken from.
+func (m ProductModifier) BaseAmount() (float64, error) {
+	if m.Unit == "" || m.Unit == m.IngredientUnit {
+		return m.Amount, nil
+	}
+	return ConvertAmount(m.Amount, m.Unit, m.IngredientUnit)
+}
+
+// PrepRecipe is the batch recipe of a prep item, an ingredient such as a sauce
+// that the kitchen makes from other ingredients. Recipes use prep items like any
+// other ingredient, orders take their prepared stock first and make the rest.
+type PrepRecipe struct {
+	IngredientID int              `json:"ingredient_id"`
+	Yield        float64          `json:"yield"` // Amount one batch makes, in the prep item base unit
+	Ingredients  []PrepIngredient `json:"ingredients"`
+}
+
+// PrepIngredient is an ingredient used by one batch of a prep recipe.
+type PrepIngredient struct {
+	PrepID         int     `json:"prep_id"`
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name,omitempty"`
+	Amount         float64 `json:"amount"` // Amount of ingredient needed for one batch, in Unit
+	Unit           string  `json:"unit"`
+	// IngredientUnit is the base unit the ingredient stock is kept in
+	IngredientUnit string `json:"ingredient_unit,omitempty"`
+	// YieldPercent overrides the yield of the ingredient for this line
+	YieldPercent *float64 `json:"yield_percent,omitempty"`
+	// IngredientYieldPercent is the yield of the ingredient itself
+	IngredientYieldPercent *float64 `json:"ingredient_yield_percent,omitempty"`
+}
+
+// BaseAmount returns the amount of ingredient one batch needs in the ingredient base unit.
+func (pi PrepIngredient) BaseAmount() (float64, error) {
+	if pi.Unit == "" || pi.Unit == pi.IngredientUnit {
+		return pi.Amount, nil
+	}
+	return ConvertAmount(pi.Amount, pi.Unit, pi.IngredientUnit)
+}
+
+// ProductCapacity is how many units of a product the current stock can make.
+type ProductCapacity struct {
+	ProductID int    `json:"product_id"`
+	Name      string `json:"name"`
+	// VariantID is set for the capacity of a variant of the product
+	VariantID   *int   `json:"variant_id,omitempty"`
+	VariantName string `json:"variant_name,omitempty"`
+	// MaxQuantity is nil for products without a recipe, stock never limits them
+	MaxQuantity *int `json:"max_quantity"`
+	// LimitingIngredientID is the ingredient that runs out first
+	LimitingIngredientID   *int   `json:"limiting_ingredient_id"`
+	LimitingIngredientName string `json:"limiting_ingredient_name,omitempty"`
+}
+
+// Order statuses.
+const (
+	OrderStatusPlaced    = "placed"
+	OrderStatusCancelled = "cancelled"
+)
+
 // Order represents a customer order.
 type Order struct {
-	ID        int         `json:"id"`
-	Items     []OrderItem `json:"items"`
-	CreatedAt time.Time   `json:"created_at"`
+	ID          int         `json:"id"`
+	Status      string      `json:"status"`
+	Items       []OrderItem `json:"items"`
+	CreatedAt   time.Time   `json:"created_at"`
+	CancelledAt *time.Time  `json:"cancelled_at,omitempty"`
 }
 
 // OrderItem represents an individual item in the order.
 type OrderItem struct {
-	ProductID int `json:"product_id"`
-	Quantity  int `json:"quantity"`
+	ProductID int   `json:"product_id"`
+	Quantity  int   `json:"quantity"`
+	VariantID *int  `json:"variant_id,omitempty"` // Size of the product ordered, the base recipe when nil
+	Modifiers []int `json:"modifiers,omitempty"`  // IDs of the product modifiers applied to the item
+}
+
+// OrderConsumption is the total amount of an ingredient deducted by an order.
+type OrderConsumption struct {
+	OrderID      int     `json:"order_id"`
+	IngredientID int     `json:"ingredient_id"`
+	Amount       float64 `json:"amount"` // taken from stock, yield loss included
+	// Theoretical is the amount the recipes call for, before yield loss
+	Theoretical float64 `json:"theoretical"`
+}
+
+// OrderPreview is the projected effect of an order on the ingredient stock,
+// computed without placing the order.
+type OrderPreview struct {
+	// Feasible is set when the order could be placed with the current stock
+	Feasible          bool                   `json:"feasible"`
+	Ingredients       []IngredientProjection `json:"ingredients"`
+	InsufficientItems []InsufficientItem     `json:"insufficient_items"`
+}
+
+// IngredientProjection is the stock an ingredient would have left after an order.
+type IngredientProjection struct {
+	IngredientID int     `json:"ingredient_id"`
+	Name         string  `json:"name"`
+	CurrentStock float64 `json:"current_stock"`
+	// Theoretical is the amount the recipes call for, Required adds the yield
+	// loss to it and is what the order takes from stock
+	Theoretical    float64 `json:"theoretical"`
+	Required       float64 `json:"required"`
+	ProjectedStock float64 `json:"projected_stock"`
+	Insufficient   bool    `json:"insufficient"`
+	// CrossesThreshold is set when the order would bring the ingredient
+	// below its low stock threshold
+	CrossesThreshold bool `json:"crosses_threshold"`
+}
+
+// InsufficientItem is an order item that uses an ingredient without enough stock.
+type InsufficientItem struct {
+	ProductID     int   `json:"product_id"`
+	VariantID     *int  `json:"variant_id,omitempty"`
+	Quantity      int   `json:"quantity"`
+	IngredientIDs []int `json:"ingredient_ids"`
+}
+
+// OrderFilter narrows down and paginates order listings.
+// Orders are returned newest first and Cursor is the ID of the last order
+// of the previous page.
+type OrderFilter struct {
+	From      *time.Time
+	To        *time.Time
+	ProductID int
+	Cursor    int
+	Limit     int
+}
+
+// OrderPage is a single page of an order listing.
+type OrderPage struct {
+	Orders     []Order `json:"orders"`
+	NextCursor *int    `json:"next_cursor"`
+}
+
+// IdempotencyKey remembers the outcome of a request sent with an
+// Idempotency-Key header so that retries replay it instead of repeating it.
+type IdempotencyKey struct {
+	Key         string
+	RequestHash string
+	Response    []byte
+	ExpiresAt   time.Time
+}
+
+// OutboxMessage is a background task stored in the transaction that caused it
+// and published to the task queue once that transaction has committed.
+type OutboxMessage struct {
+	ID        int
+	TaskType  string
+	Payload   []byte
+	Attempts  int
+	CreatedAt time.Time
+}
+
+// Reasons for a stock movement.
+const (
+	MovementReasonOrder        = "order"
+	MovementReasonCancellation = "cancellation"
+	MovementReasonRestock      = "restock"
+	MovementReasonAdjustment   = "adjustment"
+	MovementReasonWaste        = "waste"
+	MovementReasonCount        = "count"
+)
+
+// StockMovement is an immutable ledger entry recording a change of the
+// current stock of an ingredient. ReferenceID points at the order, receipt
+// or other record that caused the change, if any.
+type StockMovement struct {
+	ID           int       `json:"id"`
+	IngredientID int       `json:"ingredient_id"`
+	Delta        float64   `json:"delta"`
+	Reason       string    `json:"reason"`
+	ReferenceID  *int      `json:"reference_id"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// StockMovementFilter narrows down and paginates the movements of an ingredient.
+// Movements are returned newest first and Cursor is the ID of the last
+// movement of the previous page.
+type StockMovementFilter struct {
+	IngredientID int
+	From         *time.Time
+	To           *time.Time
+	Cursor       int
+	Limit        int
+}
+
+// StockMovementPage is a single page of stock movements.
+type StockMovementPage struct {
+	Movements  []StockMovement `json:"movements"`
+	NextCursor *int            `json:"next_cursor"`
+}
+
+// StockReceipt is a delivery of goods that increases ingredient stock.
+type StockReceipt struct {
+	ID         int                `json:"id"`
+	ReceivedBy string             `json:"received_by"`
+	Notes      string             `json:"notes,omitempty"`
+	ReceivedAt time.Time          `json:"received_at"`
+	Items      []StockReceiptItem `json:"items"`
+}
+
+// StockReceiptItem is the quantity of a single ingredient received.
+// When IncreaseTotalStock is set the reference total stock grows by the same quantity.
+// Items received in packs set PackID and Packs, Quantity is then derived from the pack size.
+// The quantity received becomes a lot with the supplier LotNumber and ExpiresAt, if given,
+// an ingredient delivered in several lots is listed once per lot.
+type StockReceiptItem struct {
+	ID                 int        `json:"id"`
+	IngredientID       int        `json:"ingredient_id"`
+	Quantity           float64    `json:"quantity"`
+	PackID             *int       `json:"pack_id,omitempty"`
+	Packs              float64    `json:"packs,omitempty"`
+	IncreaseTotalStock bool       `json:"increase_total_stock"`
+	LotNumber          string     `json:"lot_number,omitempty"`
+	ExpiresAt          *time.Time `json:"expires_at,omitempty"`
+}
+
+// IngredientPack is a pack an ingredient is purchased in, such as a 5 kg box.
+type IngredientPack struct {
+	ID           int     `json:"id"`
+	IngredientID int     `json:"ingredient_id"`
+	Label        string  `json:"label"`
+	Size         float64 `json:"size"` // in the ingredient base unit
+}
+
+// Reasons for wasting stock.
+const (
+	WasteReasonSpoiled = "spoiled"
+	WasteReasonDropped = "dropped"
+	WasteReasonExpired = "expired"
+	WasteReasonComp    = "comp"
+)
+
+// WasteEntry is stock thrown away or given away instead of being sold,
+// Quantity is in the ingredient base unit.
+type WasteEntry struct {
+	ID           int       `json:"id"`
+	IngredientID int       `json:"ingredient_id"`
+	Quantity     float64   `json:"quantity"`
+	Reason       string    `json:"reason"`
+	Notes        string    `json:"notes,omitempty"`
+	RecordedAt   time.Time `json:"recorded_at"`
+}
+
+// WasteReportFilter bounds the waste entries a report covers.
+type WasteReportFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// WasteReport sums up the waste recorded in a period by reason.
+type WasteReport struct {
+	From    *time.Time         `json:"from"`
+	To      *time.Time         `json:"to"`
+	Reasons []WasteReasonTotal `json:"reasons"`
+}
+
+// WasteReasonTotal is the waste recorded for a reason, per ingredient as
+// quantities in different units do not add up.
+type WasteReasonTotal struct {
+	Reason      string                 `json:"reason"`
+	Entries     int                    `json:"entries"`
+	Ingredients []WasteIngredientTotal `json:"ingredients"`
+}
+
+// WasteIngredientTotal is the quantity of an ingredient wasted for a reason.
+type WasteIngredientTotal struct {
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name"`
+	Unit           string  `json:"unit"`
+	Quantity       float64 `json:"quantity"`
+	Entries        int     `json:"entries"`
+}
+
+// Statuses of a stock count session.
+const (
+	CountStatusOpen   = "open"
+	CountStatusClosed = "closed"
+)
+
+// StockCountSession is a physical count of the stock on hand. Staff submit
+// counts while it is open, closing it sets the current stock of every counted
+// ingredient to the quantity counted.
+type StockCountSession struct {
+	ID       int          `json:"id"`
+	OpenedBy string       `json:"opened_by"`
+	Notes    string       `json:"notes,omitempty"`
+	Status   string       `json:"status"`
+	OpenedAt time.Time    `json:"opened_at"`
+	ClosedAt *time.Time   `json:"closed_at"`
+	Counts   []StockCount `json:"counts"`
+}
+
+// StockCount is the quantity of an ingredient a member of staff counted, in
+// the ingredient base unit. Counts of the same ingredient by several staff
+// add up.
+type StockCount struct {
+	SessionID    int       `json:"-"`
+	IngredientID int       `json:"ingredient_id"`
+	CountedBy    string    `json:"counted_by"`
+	Quantity     float64   `json:"quantity"`
+	CountedAt    time.Time `json:"counted_at"`
+}
+
+// StockCountVariance compares the quantity of an ingredient counted with the
+// stock the system had, a negative Variance is stock that went missing.
+type StockCountVariance struct {
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name"`
+	Unit           string  `json:"unit"`
+	SystemStock    float64 `json:"system_stock"`
+	Counted        float64 `json:"counted"`
+	Variance       float64 `json:"variance"`
+}
+
+// StockCountReport is the variance report of a count session, measured
+// against the system stock when each ingredient was last counted, so
+// movements between the count and the close do not show as variance.
+type StockCountReport struct {
+	SessionID   int                  `json:"session_id"`
+	Status      string               `json:"status"`
+	ClosedAt    *time.Time           `json:"closed_at"`
+	Ingredients []StockCountVariance `json:"ingredients"`
+}
+
+// UsageReportFilter bounds the period a usage variance report covers.
+type UsageReportFilter struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// UsageVarianceReport compares what the recipes of the orders in a period
+// should have used with what left the stock.
+type UsageVarianceReport struct {
+	From        *time.Time      `json:"from"`
+	To          *time.Time      `json:"to"`
+	Ingredients []UsageVariance `json:"ingredients"`
+}
+
+// UsageVariance is the theoretical and actual usage of an ingredient in its
+// base unit. Theoretical includes the yield loss the recipes expect, Actual
+// is what orders, waste and stock count corrections took out of stock. A
+// positive Variance is stock used beyond the recipes, VariancePercent is
+// relative to Theoretical and left out when nothing was expected.
+type UsageVariance struct {
+	IngredientID    int      `json:"ingredient_id"`
+	IngredientName  string   `json:"ingredient_name"`
+	Unit            string   `json:"unit"`
+	Theoretical     float64  `json:"theoretical"`
+	YieldLoss       float64  `json:"yield_loss"`
+	Actual          float64  `json:"actual"`
+	Waste           float64  `json:"waste"`
+	Variance        float64  `json:"variance"`
+	VariancePercent *float64 `json:"variance_percent"`
+}
+
+// IngredientLot is a batch of an ingredient received together, the current
+// stock of an ingredient is the remaining quantity of its lots. Orders and
+// other deductions take from the lot expiring first. Stock that came in
+// without a receipt sits in an undated lot without ReceiptID, lots received
+// on a receipt keep the ReceiptItemID they came in with.
+type IngredientLot struct {
+	ID                int        `json:"id"`
+	IngredientID      int        `json:"ingredient_id"`
+	IngredientName    string     `json:"ingredient_name,omitempty"`
+	ReceiptID         *int       `json:"receipt_id"`
+	ReceiptItemID     *int       `json:"receipt_item_id,omitempty"`
+	LotNumber         string     `json:"lot_number"`
+	ExpiresAt         *time.Time `json:"expires_at"`
+	ReceivedQuantity  float64    `json:"received_quantity"`
+	RemainingQuantity float64    `json:"remaining_quantity"`
+	ReceivedAt        time.Time  `json:"received_at"`
+}
+
+// Supplier is a vendor ingredients are purchased from. MinimumOrder is the
+// order value below which the supplier does not deliver.
+type Supplier struct {
+	ID           int       `json:"id"`
+	Name         string    `json:"name"`
+	ContactEmail string    `json:"contact_email"`
+	LeadTimeDays int       `json:"lead_time_days"`
+	MinimumOrder float64   `json:"minimum_order"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// SupplierIngredient is the offer of a supplier for an ingredient, sold in
+// packs of PackSize at UnitPrice per base unit. Preferred marks the supplier
+// an ingredient is reordered from, an ingredient has at most one.
+type SupplierIngredient struct {
+	SupplierID     int     `json:"supplier_id"`
+	SupplierName   string  `json:"supplier_name"`
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name"`
+	PackSize       float64 `json:"pack_size"` // in the ingredient base unit
+	UnitPrice      float64 `json:"unit_price"`
+	Preferred      bool    `json:"preferred"`
 }
@@ -0,0 +1,64 @@
+package models
+
+import "fmt"
+
+// Base units ingredient stock is kept in.
+const (
+	UnitGram       = "g"
+	UnitMilliliter = "ml"
+	UnitPiece      = "piece"
+)
+
+// unit is a unit of measure expressed as a multiple of a base unit.
+type unit struct {
+	base   string
+	factor float64
+}
+
+// units lists every unit recipes may use, keyed by symbol.
+var units = map[string]unit{
+	UnitGram: {UnitGram, 1},
+	"mg":     {UnitGram, 0.001},
+	"kg":     {UnitGram, 1000},
+	"oz":     {UnitGram, 28.349523125},
+	"lb":     {UnitGram, 453.59237},
+
+	UnitMilliliter: {UnitMilliliter, 1},
+	"l":            {UnitMilliliter, 1000},
+	"tsp":          {UnitMilliliter, 4.92892159375},
+	"tbsp":         {UnitMilliliter, 14.78676478125},
+	"fl_oz":        {UnitMilliliter, 29.5735295625},
+	"cup":          {UnitMilliliter, 236.5882365},
+
+	UnitPiece: {UnitPiece, 1},
+	"dozen":   {UnitPiece, 12},
+}
+
+// IsBaseUnit reports whether symbol is a unit ingredient stock can be kept in.
+func IsBaseUnit(symbol string) bool {
+	u, ok := units[symbol]
+	return ok && u.base == symbol
+}
+
+// IsUnit reports whether symbol is a known unit of measure.
+func IsUnit(symbol string) bool {
+	_, ok := units[symbol]
+	return ok
+}
+
+// ConvertAmount converts amount from one unit to another of the same kind,
+// it fails for unknown units and for incompatible ones such as grams and pieces.
+func ConvertAmount(amount float64, from string, to string) (float64, error) {
+	fromUnit, ok := units[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", from)
+	}
+	toUnit, ok := units[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown unit %q", to)
+	}
+	if fromUnit.base != toUnit.base {
+		return 0, fmt.Errorf("%s cannot be converted to %s", from, to)
+	}
+	return amount * fromUnit.factor / toUnit.factor, nil
+}
@@ -0,0 +1,119 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"sync"
+	"testing"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go/modules/postgres"
+
+	"stockk/internal/db"
+	internalErrors "stockk/internal/errors"
+)
+
+// TestIngredientRepository_ConcurrentOrders places burgers in parallel against
+// a real Postgres and checks that no stock update is lost and no ingredient is
+// oversold. The seeded onion stock (1000g, 20g per burger) only covers 50 burgers.
+func TestIngredientRepository_ConcurrentOrders(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping Postgres concurrency test in short mode")
+	}
+
+	ctx := context.Background()
+
+	ctr, err := postgres.Run(
+		ctx,
+		"postgres:17-alpine",
+		postgres.WithDatabase("stock-test"),
+		postgres.WithUsername("testuser"),
+		postgres.WithPassword("testpassword"),
+		postgres.BasicWaitStrategies(),
+		postgres.WithSQLDriver("pgx"),
+	)
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, ctr.Terminate(ctx))
+	}()
+
+	dbURL, err := ctr.ConnectionString(ctx)
+	require.NoError(t, err)
+
+	dbConn, err := sql.Open("pgx", dbURL)
+	require.NoError(t, err)
+	defer dbConn.Close()
+	dbConn.SetMaxOpenConns(20)
+
+	db.RunDBMigrations(dbConn, "file://../../db/migrations")
+
+	repo := NewIngredientRepository(dbConn, 50)
+
+	// Burger recipe from the seed data, in ingredient ID order
+	burger := []struct {
+		ingredientID int
+		amount       float64
+	}{
+		{1, 150}, // Beef
+		{2, 30},  // Cheese
+		{3, 20},  // Onion
+	}
+
+	placeBurger := func() (err error) {
+		tx, err := repo.BeginTransaction()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if err != nil {
+				_ = tx.Rollback()
+			}
+		}()
+
+		for _, line := range burger {
+			if err = repo.DecrementStock(ctx, tx, line.ingredientID, line.amount); err != nil {
+				return err
+			}
+		}
+		return tx.Commit()
+	}
+
+	const orders = 60
+	var wg sync.WaitGroup
+	errs := make(chan error, orders)
+	for i := 0; i < orders; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- placeBurger()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	placed := 0
+	for err := range errs {
+		if err == nil {
+			placed++
+			continue
+		}
+		var appErr *internalErrors.AppError
+		require.True(t, errors.As(err, &appErr), "unexpected error: %v", err)
+		require.Equal(t, internalErrors.ErrCodeInsufficientStock, appErr.Code)
+	}
+	require.Equal(t, 50, placed)
+
+	expectedStock := map[int]float64{
+		1: 20000 - 50*150,
+		2: 5000 - 50*30,
+		3: 0,
+	}
+	for id, expected := range expectedStock {
+		var stock float64
+		err := dbConn.QueryRow("SELECT current_stock FROM ingredients WHERE id = $1", id).Scan(&stock)
+		require.NoError(t, err)
+		require.Equal(t, expected, stock, "ingredient %d", id)
+	}
+}
@@ -0,0 +1,130 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type IdempotencyRepository interface {
+	ClaimKey(ctx context.Context, tx Transaction, key string, requestHash string) (*models.IdempotencyKey, error)
+	SaveResponse(ctx context.Context, tx Transaction, key string, response []byte) error
+	PurgeExpiredKeys(ctx context.Context) (int64, error)
+}
+
+type idempotencyRepository struct {
+	db *sql.DB
+	// ttl is how long a key is remembered before it can be reused
+	ttl time.Duration
+}
+
+func NewIdempotencyRepository(db *sql.DB, ttl time.Duration) IdempotencyRepository {
+	return &idempotencyRepository{db: db, ttl: ttl}
+}
+
+var _ IdempotencyRepository = (*idempotencyRepository)(nil)
+
+// ClaimKey reserves key for the request inside tx, taking over keys that have
+// expired. When a live key already exists it is returned instead and nothing
+// is claimed. A concurrent request with the same key waits until the
+// transaction that claimed it finishes.
+func (r *idempotencyRepository) ClaimKey(ctx context.Context, tx Transaction, key string, requestHash string) (*models.IdempotencyKey, error) {
+	claimQuery := `
+		INSERT INTO idempotency_keys (key, request_hash, expires_at)
+		VALUES ($1, $2, CURRENT_TIMESTAMP + make_interval(secs => $3))
+		ON CONFLICT (key) DO UPDATE
+		SET request_hash = EXCLUDED.request_hash,
+			response = NULL,
+			created_at = CURRENT_TIMESTAMP,
+			expires_at = EXCLUDED.expires_at
+		WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP
+		RETURNING key
+	`
+
+	var claimed string
+	err := tx.QueryRowContext(ctx, claimQuery, key, requestHash, r.ttl.Seconds()).Scan(&claimed)
+	if err == nil {
+		return nil, nil
+	}
+	if err != sql.ErrNoRows {
+		slog.Error("failed to claim idempotency key", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	// The key is in use, load what was stored for it
+	existingQuery := `
+		SELECT key, request_hash, response, expires_at
+		FROM idempotency_keys
+		WHERE key = $1
+	`
+
+	var existing models.IdempotencyKey
+	err = tx.QueryRowContext(ctx, existingQuery, key).Scan(
+		&existing.Key,
+		&existing.RequestHash,
+		&existing.Response,
+		&existing.ExpiresAt,
+	)
+	if err != nil {
+		slog.Error("failed to retrieve idempotency key", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return &existing, nil
+}
+
+// SaveResponse stores the response to replay for a claimed key.
+func (r *idempotencyRepository) SaveResponse(ctx context.Context, tx Transaction, key string, response []byte) error {
+	query := `
+		UPDATE idempotency_keys
+		SET response = $1
+		WHERE key = $2
+	`
+
+	result, err := tx.ExecContext(ctx, query, response, key)
+	if err != nil {
+		slog.Error("failed to save idempotent response", "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to save idempotent response", "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Idempotency key %q not found", key))
+	}
+
+	return nil
+}
+
+// PurgeExpiredKeys deletes the keys that expired and returns how many were
+// deleted. Expired keys are taken over by ClaimKey anyway, purging them only
+// keeps the table from growing with keys that are never reused.
+func (r *idempotencyRepository) PurgeExpiredKeys(ctx context.Context) (int64, error) {
+	query := `
+		DELETE FROM idempotency_keys
+		WHERE expires_at <= CURRENT_TIMESTAMP
+	`
+
+	result, err := r.db.ExecContext(ctx, query)
+	if err != nil {
+		slog.Error("failed to purge expired idempotency keys", "error", err)
+		return 0, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to purge expired idempotency keys", "error", err)
+		return 0, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return purged, nil
+}
@@ -0,0 +1,137 @@
+package repository
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIdempotencyRepository_ClaimKey(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewIdempotencyRepository(db, time.Hour)
+
+	mock.ExpectBegin()
+
+	// Mock the claim of a fresh key
+	mock.ExpectQuery(`INSERT INTO idempotency_keys \(key, request_hash, expires_at\) VALUES \(\$1, \$2, CURRENT_TIMESTAMP \+ make_interval\(secs => \$3\)\) ON CONFLICT \(key\) DO UPDATE .* WHERE idempotency_keys.expires_at <= CURRENT_TIMESTAMP RETURNING key`).
+		WithArgs("key-1", "hash-1", 3600.0).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}).AddRow("key-1"))
+
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	existing, err := repo.ClaimKey(context.Background(), tx, "key-1", "hash-1")
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Nil(t, existing)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIdempotencyRepository_ClaimKey_Existing(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewIdempotencyRepository(db, time.Hour)
+
+	expiresAt := time.Now().Add(time.Hour)
+	response := []byte(`{"id":7}`)
+
+	mock.ExpectBegin()
+
+	// Mock the claim hitting a live key
+	mock.ExpectQuery(`INSERT INTO idempotency_keys`).
+		WithArgs("key-1", "hash-1", 3600.0).
+		WillReturnRows(sqlmock.NewRows([]string{"key"}))
+
+	// Mock loading the stored key
+	mock.ExpectQuery(`SELECT key, request_hash, response, expires_at FROM idempotency_keys WHERE key = \$1`).
+		WithArgs("key-1").
+		WillReturnRows(sqlmock.NewRows([]string{"key", "request_hash", "response", "expires_at"}).
+			AddRow("key-1", "hash-1", response, expiresAt))
+
+	mock.ExpectRollback()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	existing, err := repo.ClaimKey(context.Background(), tx, "key-1", "hash-1")
+
+	// Assertions
+	assert.NoError(t, err)
+	if assert.NotNil(t, existing) {
+		assert.Equal(t, "hash-1", existing.RequestHash)
+		assert.Equal(t, response, existing.Response)
+		assert.Equal(t, expiresAt, existing.ExpiresAt)
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatalf("Failed to rollback transaction: %v", err)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIdempotencyRepository_PurgeExpiredKeys(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewIdempotencyRepository(db, time.Hour)
+
+	// Mock the delete of the expired keys
+	mock.ExpectExec(`DELETE FROM idempotency_keys WHERE expires_at <= CURRENT_TIMESTAMP`).
+		WillReturnResult(sqlmock.NewResult(0, 4))
+
+	// Call the method under test
+	purged, err := repo.PurgeExpiredKeys(context.Background())
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4), purged)
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
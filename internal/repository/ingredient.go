@@ -5,6 +5,9 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"strconv"
+	"strings"
+	"time"
 
 	"stockk/internal/errors"
 	internalErrors "stockk/internal/errors"
@@ -12,25 +15,67 @@ import (
 )
 
 type IngredientRepository interface {
+	BeginTransaction() (Transaction, error)
 	GetIngredientByID(ctx context.Context, tx Transaction, ingredientID int) (*models.Ingredient, error)
-	UpdateStock(ctx context.Context, tx Transaction, ingredientID int, newStock float64) error
-	CheckLowStockIngredients(ctx context.Context) ([]models.Ingredient, error)
-	MarkAlertSent(ctx context.Context, ingredientID int) error
+	UpdateStock(ctx context.Context, tx Transaction, ingredientID int, newStock float64) (float64, error)
+	DecrementStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error
+	IncrementStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error
+	LockStock(ctx context.Context, tx Transaction, ingredientIDs []int) (map[int]float64, error)
+	IncrementTotalStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error
+	ResetAlertIfReplenished(ctx context.Context, tx Transaction, ingredientID int) error
+	ClaimLowStockAlerts(ctx context.Context, tx Transaction, ingredientIDs []int) ([]models.Ingredient, error)
+	ListIngredients(ctx context.Context) ([]models.Ingredient, error)
+	CreateIngredient(ctx context.Context, tx Transaction, ingredient *models.Ingredient) error
+	RenameIngredient(ctx context.Context, ingredientID int, name string) error
+	UpdateTotalStock(ctx context.Context, ingredientID int, totalStock float64) error
+	ArchiveIngredient(ctx context.Context, ingredientID int) error
+	SetLowStockThreshold(ctx context.Context, ingredientID int, threshold *models.LowStockThreshold) error
+	SetYieldPercent(ctx context.Context, ingredientID int, yieldPercent *float64) error
+	ReceiveLot(ctx context.Context, tx Transaction, lot *models.IngredientLot) error
+	ListLots(ctx context.Context, ingredientID int) ([]models.IngredientLot, error)
+	ListExpiringLots(ctx context.Context, before time.Time) ([]models.IngredientLot, error)
+	ClaimExpiryAlerts(ctx context.Context, tx Transaction, windowDays int) ([]models.IngredientLot, error)
 }
 
 type ingredientRepository struct {
 	db *sql.DB
+	// defaultThreshold is the low stock percentage applied to ingredients
+	// that do not define a threshold of their own
+	defaultThreshold float64
 }
 
-func NewIngredientRepository(db *sql.DB) IngredientRepository {
-	return &ingredientRepository{db: db}
+func NewIngredientRepository(db *sql.DB, defaultLowStockThreshold float64) IngredientRepository {
+	return &ingredientRepository{db: db, defaultThreshold: defaultLowStockThreshold}
 }
 
 var _ IngredientRepository = (*ingredientRepository)(nil)
 
+func (r *ingredientRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
+// thresholdColumns selects the effective low stock threshold of an ingredient,
+// falling back to the default percentage when none is set.
+func (r *ingredientRepository) thresholdColumns() string {
+	return fmt.Sprintf(
+		`COALESCE(low_stock_threshold_type, '%s'), COALESCE(low_stock_threshold, %s), low_stock_threshold IS NULL`,
+		models.ThresholdTypePercentage,
+		strconv.FormatFloat(r.defaultThreshold, 'f', -1, 64),
+	)
+}
+
+// lowStockCondition matches ingredients whose stock fell below their alert threshold.
+func (r *ingredientRepository) lowStockCondition() string {
+	return fmt.Sprintf(
+		`CASE WHEN low_stock_threshold_type = '%s' THEN current_stock < low_stock_threshold ELSE current_stock < total_stock * COALESCE(low_stock_threshold, %s) / 100 END`,
+		models.ThresholdTypeQuantity,
+		strconv.FormatFloat(r.defaultThreshold, 'f', -1, 64),
+	)
+}
+
 func (r *ingredientRepository) GetIngredientByID(ctx context.Context, tx Transaction, ingredientID int) (*models.Ingredient, error) {
 	query := `
-		SELECT id, name, total_stock, current_stock, alert_sent 
+		SELECT id, name, unit, total_stock, current_stock, alert_sent, ` + r.thresholdColumns() + `, yield_percent, archived_at
 		FROM ingredients 
 		WHERE id = $1
 	`
@@ -43,17 +88,29 @@ func (r *ingredientRepository) GetIngredientByID(ctx context.Context, tx Transac
 		err = tx.QueryRowContext(ctx, query, ingredientID).Scan(
 			&ingredient.ID,
 			&ingredient.Name,
+			&ingredient.Unit,
 			&ingredient.TotalStock,
 			&ingredient.CurrentStock,
 			&ingredient.AlertSent,
+			&ingredient.LowStockThreshold.Type,
+			&ingredient.LowStockThreshold.Value,
+			&ingredient.LowStockThreshold.Default,
+			&ingredient.YieldPercent,
+			&ingredient.ArchivedAt,
 		)
 	} else {
 		err = r.db.QueryRowContext(ctx, query, ingredientID).Scan(
 			&ingredient.ID,
 			&ingredient.Name,
+			&ingredient.Unit,
 			&ingredient.TotalStock,
 			&ingredient.CurrentStock,
 			&ingredient.AlertSent,
+			&ingredient.LowStockThreshold.Type,
+			&ingredient.LowStockThreshold.Value,
+			&ingredient.LowStockThreshold.Default,
+			&ingredient.YieldPercent,
+			&ingredient.ArchivedAt,
 		)
 	}
 
@@ -68,28 +125,205 @@ func (r *ingredientRepository) GetIngredientByID(ctx context.Context, tx Transac
 	return &ingredient, nil
 }
 
-func (r *ingredientRepository) UpdateStock(ctx context.Context, tx Transaction, ingredientID int, newStock float64) error {
+// UpdateStock sets the current stock of an ingredient, taking a decrease out
+// of its lots first expired first out and putting an increase in its undated
+// lot. It returns the stock the ingredient had under the row lock, so callers
+// can record the exact change. Without a transaction the stock and its lots
+// are changed in a transaction of their own.
+func (r *ingredientRepository) UpdateStock(ctx context.Context, tx Transaction, ingredientID int, newStock float64) (previousStock float64, err error) {
+	if tx == nil {
+		if tx, err = r.BeginTransaction(); err != nil {
+			return 0, err
+		}
+		defer func() {
+			if err != nil {
+				tx.Rollback()
+				return
+			}
+			if err = tx.Commit(); err != nil {
+				slog.Error("failed to commit ingredient stock", "ingredientID", ingredientID, "error", err)
+				previousStock, err = 0, errors.Wrap(errors.ErrInternalServer, "transaction failed")
+			}
+		}()
+	}
+
+	query := `
+		UPDATE ingredients i
+		SET current_stock = $1
+		FROM (SELECT id, current_stock FROM ingredients WHERE id = $2 FOR UPDATE) previous
+		WHERE i.id = previous.id
+		RETURNING previous.current_stock
+	`
+
+	err = tx.QueryRowContext(ctx, query, newStock, ingredientID).Scan(&previousStock)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", ingredientID))
+		}
+		slog.Error("failed to update ingredient stock", "ingredientID", ingredientID, "error", err)
+		return 0, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	switch delta := newStock - previousStock; {
+	case delta < 0:
+		err = r.consumeLots(ctx, tx, ingredientID, -delta)
+	case delta > 0:
+		err = r.addLooseStock(ctx, tx, ingredientID, delta)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return previousStock, nil
+}
+
+// DecrementStock atomically subtracts amount from the current stock of an
+// ingredient. The check and the update happen in a single statement so that
+// concurrent orders can neither lose updates nor drive the stock below zero.
+// The amount is taken out of the lots first expired first out. Archived
+// ingredients are off the menu, so no stock can be taken from them.
+func (r *ingredientRepository) DecrementStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error {
 	query := `
 		UPDATE ingredients 
-		SET current_stock = $1 
+		SET current_stock = current_stock - $1 
+		WHERE id = $2 AND current_stock >= $1 AND archived_at IS NULL
+	`
+
+	result, err := tx.ExecContext(ctx, query, amount, ingredientID)
+	if err != nil {
+		slog.Error("failed to decrement ingredient stock", "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to decrement ingredient stock", "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected > 0 {
+		return r.consumeLots(ctx, tx, ingredientID, amount)
+	}
+
+	// Nothing was updated, tell an unknown or archived ingredient apart from a short stock
+	var name string
+	var archived bool
+	err = tx.QueryRowContext(ctx, `SELECT name, archived_at IS NOT NULL FROM ingredients WHERE id = $1`, ingredientID).Scan(&name, &archived)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", ingredientID))
+		}
+		slog.Error("failed to decrement ingredient stock", "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if archived {
+		return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Ingredient archived", fmt.Sprintf("%s is archived", name))
+	}
+	return internalErrors.NewAppError(internalErrors.ErrCodeInsufficientStock, "Insufficient Ingredient stock", fmt.Sprintf("%s stock is insufficient", name))
+}
+
+// LockStock locks the rows of ingredients in ID order, so concurrent callers
+// cannot deadlock, and returns their current stock. Unknown IDs are left out.
+func (r *ingredientRepository) LockStock(ctx context.Context, tx Transaction, ingredientIDs []int) (map[int]float64, error) {
+	stock := make(map[int]float64, len(ingredientIDs))
+	if len(ingredientIDs) == 0 {
+		return stock, nil
+	}
+
+	placeholders := make([]string, len(ingredientIDs))
+	args := make([]interface{}, len(ingredientIDs))
+	for i, ingredientID := range ingredientIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = ingredientID
+	}
+
+	query := fmt.Sprintf(`
+		SELECT id, current_stock
+		FROM ingredients
+		WHERE id IN (%s)
+		ORDER BY id
+		FOR UPDATE
+	`, strings.Join(placeholders, ", "))
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to lock ingredient stock", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var ingredientID int
+		var currentStock float64
+		if err := rows.Scan(&ingredientID, &currentStock); err != nil {
+			slog.Error("failed to lock ingredient stock", "error", err)
+			return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+		}
+		stock[ingredientID] = currentStock
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to lock ingredient stock", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return stock, nil
+}
+
+// IncrementStock atomically adds amount to the current stock of an
+// ingredient, putting it in its undated lot.
+func (r *ingredientRepository) IncrementStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error {
+	if err := r.incrementCurrentStock(ctx, tx, ingredientID, amount); err != nil {
+		return err
+	}
+	return r.addLooseStock(ctx, tx, ingredientID, amount)
+}
+
+// incrementCurrentStock atomically adds amount to the current stock of an
+// ingredient, leaving its lots to the caller.
+func (r *ingredientRepository) incrementCurrentStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error {
+	query := `
+		UPDATE ingredients 
+		SET current_stock = current_stock + $1 
 		WHERE id = $2
 	`
-	var err error
-	var result sql.Result
-	if tx != nil {
-		result, err = tx.ExecContext(ctx, query, newStock, ingredientID)
-	} else {
-		result, err = r.db.ExecContext(ctx, query, newStock, ingredientID)
+
+	result, err := tx.ExecContext(ctx, query, amount, ingredientID)
+	if err != nil {
+		slog.Error("failed to increment ingredient stock", "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
 	}
+
+	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		slog.Error("failed to update ingredient stock", "ingredientID", ingredientID, "error", err)
+		slog.Error("failed to increment ingredient stock", "ingredientID", ingredientID, "error", err)
 		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", ingredientID))
+	}
+
+	return nil
+}
+
+// IncrementTotalStock atomically adds amount to the reference total stock of an ingredient.
+func (r *ingredientRepository) IncrementTotalStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error {
+	query := `
+		UPDATE ingredients 
+		SET total_stock = total_stock + $1 
+		WHERE id = $2
+	`
 
+	result, err := tx.ExecContext(ctx, query, amount, ingredientID)
+	if err != nil {
+		slog.Error("failed to increment ingredient total stock", "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		slog.Error("failed to update ingredient stock", "ingredientID", ingredientID, "error", err)
+		slog.Error("failed to increment ingredient total stock", "ingredientID", ingredientID, "error", err)
 		return errors.Wrap(errors.ErrInternalServer, "query failed")
 	}
 
@@ -100,20 +334,63 @@ func (r *ingredientRepository) UpdateStock(ctx context.Context, tx Transaction,
 	return nil
 }
 
-func (r *ingredientRepository) CheckLowStockIngredients(ctx context.Context) ([]models.Ingredient, error) {
+// ResetAlertIfReplenished re-arms the low stock alert of an ingredient once its
+// stock is back above the alert threshold, so the next shortage alerts again.
+func (r *ingredientRepository) ResetAlertIfReplenished(ctx context.Context, tx Transaction, ingredientID int) error {
 	query := `
-		SELECT id, name, total_stock, current_stock
-		FROM ingredients
-		WHERE (current_stock / total_stock * 100) < 50 AND alert_sent = false
+		UPDATE ingredients
+		SET alert_sent = false
+		WHERE id = $1 AND alert_sent = true AND NOT ` + r.lowStockCondition()
+
+	var err error
+	if tx != nil {
+		_, err = tx.ExecContext(ctx, query, ingredientID)
+	} else {
+		_, err = r.db.ExecContext(ctx, query, ingredientID)
+	}
+	if err != nil {
+		slog.Error("failed to reset ingredient alert status", "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// ClaimLowStockAlerts marks the given ingredients that are active, fell below
+// their threshold and have no pending alert as alerted, returning them.
+// Running it in the transaction that changed their stock makes sure a single
+// alert is raised per ingredient even under concurrent orders, and as the
+// stock update already locked their rows no other row is locked.
+func (r *ingredientRepository) ClaimLowStockAlerts(ctx context.Context, tx Transaction, ingredientIDs []int) ([]models.Ingredient, error) {
+	if len(ingredientIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(ingredientIDs))
+	args := make([]interface{}, len(ingredientIDs))
+	for i, ingredientID := range ingredientIDs {
+		placeholders[i] = fmt.Sprintf("$%d", i+1)
+		args[i] = ingredientID
+	}
+
+	query := `
+		UPDATE ingredients
+		SET alert_sent = true
+		WHERE id IN (` + strings.Join(placeholders, ", ") + `) AND ` + r.lowStockCondition() + ` AND alert_sent = false AND archived_at IS NULL
+		RETURNING id, name, total_stock, current_stock, ` + r.thresholdColumns() + `
 	`
 
-	rows, err := r.db.QueryContext(ctx, query)
+	rows, err := tx.QueryContext(ctx, query, args...)
 	if err != nil {
-		slog.Error("failed to retrieve low stock ingredients", "error", err)
+		slog.Error("failed to claim low stock alerts", "error", err)
 		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
 	}
 	defer rows.Close()
 
+	return scanLowStockIngredients(rows)
+}
+
+func scanLowStockIngredients(rows *sql.Rows) ([]models.Ingredient, error) {
 	var lowStockIngredients []models.Ingredient
 	for rows.Next() {
 		var ingredient models.Ingredient
@@ -122,6 +399,9 @@ func (r *ingredientRepository) CheckLowStockIngredients(ctx context.Context) ([]
 			&ingredient.Name,
 			&ingredient.TotalStock,
 			&ingredient.CurrentStock,
+			&ingredient.LowStockThreshold.Type,
+			&ingredient.LowStockThreshold.Value,
+			&ingredient.LowStockThreshold.Default,
 		); err != nil {
 			slog.Error("failed to retrieve low stock ingredients", "error", err)
 			return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
@@ -129,7 +409,7 @@ func (r *ingredientRepository) CheckLowStockIngredients(ctx context.Context) ([]
 		lowStockIngredients = append(lowStockIngredients, ingredient)
 	}
 
-	if err = rows.Err(); err != nil {
+	if err := rows.Err(); err != nil {
 		slog.Error("failed to retrieve low stock ingredients", "error", err)
 		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
 	}
@@ -137,25 +417,153 @@ func (r *ingredientRepository) CheckLowStockIngredients(ctx context.Context) ([]
 	return lowStockIngredients, nil
 }
 
-func (r *ingredientRepository) MarkAlertSent(ctx context.Context, ingredientID int) error {
+// ListIngredients returns every ingredient that has not been archived.
+func (r *ingredientRepository) ListIngredients(ctx context.Context) ([]models.Ingredient, error) {
 	query := `
-		UPDATE ingredients 
-		SET alert_sent = true 
-		WHERE id = $1
+		SELECT id, name, unit, total_stock, current_stock, alert_sent, ` + r.thresholdColumns() + `, yield_percent
+		FROM ingredients
+		WHERE archived_at IS NULL
+		ORDER BY id
 	`
 
-	result, err := r.db.ExecContext(ctx, query, ingredientID)
+	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
-		slog.Error("failed to update ingredient alert status", "error", err)
+		slog.Error("failed to list ingredients", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	ingredients := []models.Ingredient{}
+	for rows.Next() {
+		var ingredient models.Ingredient
+		if err := rows.Scan(
+			&ingredient.ID,
+			&ingredient.Name,
+			&ingredient.Unit,
+			&ingredient.TotalStock,
+			&ingredient.CurrentStock,
+			&ingredient.AlertSent,
+			&ingredient.LowStockThreshold.Type,
+			&ingredient.LowStockThreshold.Value,
+			&ingredient.LowStockThreshold.Default,
+			&ingredient.YieldPercent,
+		); err != nil {
+			slog.Error("failed to list ingredients", "error", err)
+			return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+		}
+		ingredients = append(ingredients, ingredient)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.Error("failed to list ingredients", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return ingredients, nil
+}
+
+// CreateIngredient inserts a new ingredient and sets its generated ID.
+// A default low stock threshold is stored as NULL so it follows the configured default.
+func (r *ingredientRepository) CreateIngredient(ctx context.Context, tx Transaction, ingredient *models.Ingredient) error {
+	query := `
+		INSERT INTO ingredients (name, unit, total_stock, current_stock, low_stock_threshold_type, low_stock_threshold, yield_percent)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	thresholdType, thresholdValue := thresholdArgs(&ingredient.LowStockThreshold)
+	err := tx.QueryRowContext(ctx, query, ingredient.Name, ingredient.Unit, ingredient.TotalStock, ingredient.CurrentStock, thresholdType, thresholdValue, ingredient.YieldPercent).Scan(&ingredient.ID)
+	if err != nil {
+		slog.Error("failed to create ingredient", "error", err)
 		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
 
+	// The initial stock opens the undated lot
+	if ingredient.CurrentStock > 0 {
+		return r.addLooseStock(ctx, tx, ingredient.ID, ingredient.CurrentStock)
 	}
+	return nil
+}
 
-	rowsAffected, err := result.RowsAffected()
+// RenameIngredient changes the name of an active ingredient.
+func (r *ingredientRepository) RenameIngredient(ctx context.Context, ingredientID int, name string) error {
+	query := `
+		UPDATE ingredients
+		SET name = $1
+		WHERE id = $2 AND archived_at IS NULL
+	`
+
+	return r.execIngredientUpdate(ctx, "failed to rename ingredient", ingredientID, query, name, ingredientID)
+}
+
+// UpdateTotalStock sets the reference total stock of an active ingredient.
+func (r *ingredientRepository) UpdateTotalStock(ctx context.Context, ingredientID int, totalStock float64) error {
+	query := `
+		UPDATE ingredients
+		SET total_stock = $1
+		WHERE id = $2 AND archived_at IS NULL
+	`
+
+	return r.execIngredientUpdate(ctx, "failed to update ingredient total stock", ingredientID, query, totalStock, ingredientID)
+}
+
+// ArchiveIngredient soft deletes an ingredient, keeping it available for
+// existing recipes and order history.
+func (r *ingredientRepository) ArchiveIngredient(ctx context.Context, ingredientID int) error {
+	query := `
+		UPDATE ingredients
+		SET archived_at = NOW()
+		WHERE id = $1 AND archived_at IS NULL
+	`
+
+	return r.execIngredientUpdate(ctx, "failed to archive ingredient", ingredientID, query, ingredientID)
+}
+
+// SetLowStockThreshold sets the low stock threshold of an active ingredient,
+// a nil threshold reverts it to the configured default.
+func (r *ingredientRepository) SetLowStockThreshold(ctx context.Context, ingredientID int, threshold *models.LowStockThreshold) error {
+	query := `
+		UPDATE ingredients
+		SET low_stock_threshold_type = $1, low_stock_threshold = $2
+		WHERE id = $3 AND archived_at IS NULL
+	`
+
+	thresholdType, thresholdValue := thresholdArgs(threshold)
+	return r.execIngredientUpdate(ctx, "failed to set ingredient low stock threshold", ingredientID, query, thresholdType, thresholdValue, ingredientID)
+}
+
+// SetYieldPercent sets the yield of an active ingredient, nil when nothing is lost.
+func (r *ingredientRepository) SetYieldPercent(ctx context.Context, ingredientID int, yieldPercent *float64) error {
+	query := `
+		UPDATE ingredients
+		SET yield_percent = $1
+		WHERE id = $2 AND archived_at IS NULL
+	`
+
+	return r.execIngredientUpdate(ctx, "failed to set ingredient yield", ingredientID, query, yieldPercent, ingredientID)
+}
+
+// thresholdArgs converts a threshold into its column values, NULL for the default one.
+func thresholdArgs(threshold *models.LowStockThreshold) (interface{}, interface{}) {
+	if threshold == nil || threshold.Default {
+		return nil, nil
+	}
+	return threshold.Type, threshold.Value
+}
+
+// execIngredientUpdate runs an update statement targeting a single ingredient
+// and reports a not found error when no row was affected.
+func (r *ingredientRepository) execIngredientUpdate(ctx context.Context, logMessage string, ingredientID int, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
 	if err != nil {
-		slog.Error("failed to update ingredient alert status", "error", err)
+		slog.Error(logMessage, "ingredientID", ingredientID, "error", err)
 		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error(logMessage, "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
 	}
 
 	if rowsAffected == 0 {
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+// The current stock of an ingredient is the remaining quantity of its lots.
+// Every change of current_stock is mirrored on the lots in the same
+// transaction, after the ingredient row is locked by the stock update so
+// that concurrent changes of the lots of an ingredient are serialized.
+
+// lotColumns selects a lot in the order scanLots reads it.
+const lotColumns = `l.id, l.ingredient_id, i.name, l.receipt_id, l.receipt_item_id, l.lot_number, l.expires_at, l.received_quantity, l.remaining_quantity, l.received_at`
+
+// ReceiveLot adds a received lot to the stock of an ingredient, setting its
+// generated ID and timestamp.
+func (r *ingredientRepository) ReceiveLot(ctx context.Context, tx Transaction, lot *models.IngredientLot) error {
+	if err := r.incrementCurrentStock(ctx, tx, lot.IngredientID, lot.ReceivedQuantity); err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO ingredient_lots (ingredient_id, receipt_id, receipt_item_id, lot_number, expires_at, received_quantity, remaining_quantity)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id, remaining_quantity, received_at
+	`
+
+	err := tx.QueryRowContext(ctx, query, lot.IngredientID, lot.ReceiptID, lot.ReceiptItemID, lot.LotNumber, lot.ExpiresAt, lot.ReceivedQuantity).
+		Scan(&lot.ID, &lot.RemainingQuantity, &lot.ReceivedAt)
+	if err != nil {
+		slog.Error("failed to create ingredient lot", "ingredientID", lot.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// ListLots returns the lots of an ingredient with stock left, in the order
+// they are consumed.
+func (r *ingredientRepository) ListLots(ctx context.Context, ingredientID int) ([]models.IngredientLot, error) {
+	query := `
+		SELECT ` + lotColumns + `
+		FROM ingredient_lots l
+		JOIN ingredients i ON i.id = l.ingredient_id
+		WHERE l.ingredient_id = $1 AND l.remaining_quantity > 0
+		ORDER BY l.expires_at NULLS LAST, l.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ingredientID)
+	if err != nil {
+		slog.Error("failed to list ingredient lots", "ingredientID", ingredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return scanLots(rows)
+}
+
+// ListExpiringLots returns the lots with stock left expiring before the given
+// time, expired ones included, soonest first.
+func (r *ingredientRepository) ListExpiringLots(ctx context.Context, before time.Time) ([]models.IngredientLot, error) {
+	query := `
+		SELECT ` + lotColumns + `
+		FROM ingredient_lots l
+		JOIN ingredients i ON i.id = l.ingredient_id
+		WHERE l.expires_at < $1 AND l.remaining_quantity > 0
+		ORDER BY l.expires_at, l.id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, before)
+	if err != nil {
+		slog.Error("failed to list expiring lots", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return scanLots(rows)
+}
+
+// ClaimExpiryAlerts records an alert for every lot with stock left that
+// expires within windowDays days and was not alerted for that window yet,
+// returning the lots. The claim only holds once tx commits, lots claimed by a
+// concurrent transaction are left out.
+func (r *ingredientRepository) ClaimExpiryAlerts(ctx context.Context, tx Transaction, windowDays int) ([]models.IngredientLot, error) {
+	query := `
+		WITH claimed AS (
+			INSERT INTO lot_expiry_alerts (lot_id, window_days)
+			SELECT l.id, $1
+			FROM ingredient_lots l
+			JOIN ingredients i ON i.id = l.ingredient_id
+			WHERE l.expires_at < CURRENT_TIMESTAMP + make_interval(days => $1)
+				AND l.remaining_quantity > 0 AND i.archived_at IS NULL
+			ON CONFLICT DO NOTHING
+			RETURNING lot_id
+		)
+		SELECT ` + lotColumns + `
+		FROM claimed c
+		JOIN ingredient_lots l ON l.id = c.lot_id
+		JOIN ingredients i ON i.id = l.ingredient_id
+		ORDER BY l.expires_at, l.id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, windowDays)
+	if err != nil {
+		slog.Error("failed to claim expiry alerts", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return scanLots(rows)
+}
+
+func scanLots(rows *sql.Rows) ([]models.IngredientLot, error) {
+	defer rows.Close()
+
+	lots := []models.IngredientLot{}
+	for rows.Next() {
+		var lot models.IngredientLot
+		if err := rows.Scan(
+			&lot.ID,
+			&lot.IngredientID,
+			&lot.IngredientName,
+			&lot.ReceiptID,
+			&lot.ReceiptItemID,
+			&lot.LotNumber,
+			&lot.ExpiresAt,
+			&lot.ReceivedQuantity,
+			&lot.RemainingQuantity,
+			&lot.ReceivedAt,
+		); err != nil {
+			slog.Error("failed to retrieve ingredient lot", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		lots = append(lots, lot)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to retrieve ingredient lot", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return lots, nil
+}
+
+// consumeLots takes amount out of the lots of an ingredient first expired
+// first out, undated lots last. Each lot gives what is left of amount after
+// the lots expiring before it, up to its remaining quantity.
+func (r *ingredientRepository) consumeLots(ctx context.Context, tx Transaction, ingredientID int, amount float64) error {
+	query := `
+		UPDATE ingredient_lots l
+		SET remaining_quantity = l.remaining_quantity - LEAST(l.remaining_quantity, $2 - f.earlier)
+		FROM (
+			SELECT id, SUM(remaining_quantity) OVER (ORDER BY expires_at NULLS LAST, id) - remaining_quantity AS earlier
+			FROM ingredient_lots
+			WHERE ingredient_id = $1 AND remaining_quantity > 0
+		) f
+		WHERE l.id = f.id AND f.earlier < $2
+	`
+
+	if _, err := tx.ExecContext(ctx, query, ingredientID, amount); err != nil {
+		slog.Error("failed to consume ingredient lots", "ingredientID", ingredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// addLooseStock puts stock that came in without a receipt into the undated
+// lot of an ingredient.
+func (r *ingredientRepository) addLooseStock(ctx context.Context, tx Transaction, ingredientID int, amount float64) error {
+	query := `
+		INSERT INTO ingredient_lots (ingredient_id, received_quantity, remaining_quantity)
+		VALUES ($1, $2, $2)
+		ON CONFLICT (ingredient_id) WHERE receipt_id IS NULL
+		DO UPDATE SET received_quantity = ingredient_lots.received_quantity + EXCLUDED.received_quantity,
+			remaining_quantity = ingredient_lots.remaining_quantity + EXCLUDED.remaining_quantity
+	`
+
+	if _, err := tx.ExecContext(ctx, query, ingredientID, amount); err != nil {
+		slog.Error("failed to add loose ingredient stock", "ingredientID", ingredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
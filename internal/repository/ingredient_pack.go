@@ -0,0 +1,139 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type IngredientPackRepository interface {
+	CreatePack(ctx context.Context, pack *models.IngredientPack) error
+	GetPackByID(ctx context.Context, tx Transaction, packID int) (*models.IngredientPack, error)
+	ListPacks(ctx context.Context, ingredientID int) ([]models.IngredientPack, error)
+	DeletePack(ctx context.Context, ingredientID int, packID int) error
+}
+
+type ingredientPackRepository struct {
+	db *sql.DB
+}
+
+func NewIngredientPackRepository(db *sql.DB) IngredientPackRepository {
+	return &ingredientPackRepository{db: db}
+}
+
+var _ IngredientPackRepository = (*ingredientPackRepository)(nil)
+
+// CreatePack stores a pack definition and sets its generated ID.
+func (r *ingredientPackRepository) CreatePack(ctx context.Context, pack *models.IngredientPack) error {
+	query := `
+		INSERT INTO ingredient_packs (ingredient_id, label, size)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	err := r.db.QueryRowContext(ctx, query, pack.IngredientID, pack.Label, pack.Size).Scan(&pack.ID)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case pgForeignKeyViolation:
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", pack.IngredientID))
+		case pgUniqueViolation:
+			return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Pack already exists", fmt.Sprintf("Ingredient %d already has a pack labelled %q", pack.IngredientID, pack.Label))
+		}
+		slog.Error("failed to create ingredient pack", "ingredientID", pack.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// GetPackByID fetches a pack definition, within tx when given.
+func (r *ingredientPackRepository) GetPackByID(ctx context.Context, tx Transaction, packID int) (*models.IngredientPack, error) {
+	query := `
+		SELECT id, ingredient_id, label, size
+		FROM ingredient_packs
+		WHERE id = $1
+	`
+
+	var row *sql.Row
+	if tx != nil {
+		row = tx.QueryRowContext(ctx, query, packID)
+	} else {
+		row = r.db.QueryRowContext(ctx, query, packID)
+	}
+
+	var pack models.IngredientPack
+	if err := row.Scan(&pack.ID, &pack.IngredientID, &pack.Label, &pack.Size); err != nil {
+		if err == sql.ErrNoRows {
+			return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Pack with ID %d not found", packID))
+		}
+		slog.Error("failed to retrieve ingredient pack", "packID", packID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return &pack, nil
+}
+
+// ListPacks returns the packs an ingredient is purchased in, smallest first.
+func (r *ingredientPackRepository) ListPacks(ctx context.Context, ingredientID int) ([]models.IngredientPack, error) {
+	query := `
+		SELECT id, ingredient_id, label, size
+		FROM ingredient_packs
+		WHERE ingredient_id = $1
+		ORDER BY size, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ingredientID)
+	if err != nil {
+		slog.Error("failed to list ingredient packs", "ingredientID", ingredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	packs := []models.IngredientPack{}
+	for rows.Next() {
+		var pack models.IngredientPack
+		if err := rows.Scan(&pack.ID, &pack.IngredientID, &pack.Label, &pack.Size); err != nil {
+			slog.Error("failed to list ingredient packs", "ingredientID", ingredientID, "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		packs = append(packs, pack)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to list ingredient packs", "ingredientID", ingredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return packs, nil
+}
+
+// DeletePack removes a pack definition of an ingredient. Packs already used
+// by a goods receipt are kept for its history.
+func (r *ingredientPackRepository) DeletePack(ctx context.Context, ingredientID int, packID int) error {
+	query := `DELETE FROM ingredient_packs WHERE id = $1 AND ingredient_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, packID, ingredientID)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Pack in use", fmt.Sprintf("Pack with ID %d was used by a goods receipt", packID))
+		}
+		slog.Error("failed to delete ingredient pack", "packID", packID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to delete ingredient pack", "packID", packID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Pack with ID %d not found for ingredient %d", packID, ingredientID))
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"context"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngredientPackRepository_CreatePack(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewIngredientPackRepository(db)
+
+	// Mock the insert, the second pack repeats the label of the first one
+	mock.ExpectQuery(`INSERT INTO ingredient_packs \(ingredient_id, label, size\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
+		WithArgs(1, "5 kg box", 5000.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+	mock.ExpectQuery(`INSERT INTO ingredient_packs`).
+		WithArgs(1, "5 kg box", 2500.0).
+		WillReturnError(&pgconn.PgError{Code: pgUniqueViolation})
+
+	// Call the method under test
+	pack := &models.IngredientPack{IngredientID: 1, Label: "5 kg box", Size: 5000}
+	err = repo.CreatePack(context.Background(), pack)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 7, pack.ID)
+
+	err = repo.CreatePack(context.Background(), &models.IngredientPack{IngredientID: 1, Label: "5 kg box", Size: 2500})
+	var appErr *internalErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, internalErrors.ErrCodeConflict, appErr.Code)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientPackRepository_ListPacks(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewIngredientPackRepository(db)
+
+	// Mock the listing query
+	mock.ExpectQuery(`SELECT id, ingredient_id, label, size FROM ingredient_packs WHERE ingredient_id = \$1 ORDER BY size, id`).
+		WithArgs(2).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ingredient_id", "label", "size"}).
+			AddRow(4, 2, "24 slice pack", 480.0).
+			AddRow(5, 2, "Block", 2000.0))
+
+	// Call the method under test
+	packs, err := repo.ListPacks(context.Background(), 2)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []models.IngredientPack{
+		{ID: 4, IngredientID: 2, Label: "24 slice pack", Size: 480},
+		{ID: 5, IngredientID: 2, Label: "Block", Size: 2000},
+	}, packs)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"database/sql"
 	"testing"
+	"time"
 
+	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
 
 	"github.com/DATA-DOG/go-sqlmock"
@@ -19,23 +21,29 @@ func TestIngredientRepository_GetIngredientByID(t *testing.T) {
 	}
 	defer db.Close()
 
-	repo := NewIngredientRepository(db)
+	repo := NewIngredientRepository(db, 50)
 
 	// Define the expected ingredient data
 	ingredientID := 1
 	expectedIngredient := &models.Ingredient{
 		ID:           ingredientID,
 		Name:         "Sugar",
+		Unit:         models.UnitGram,
 		TotalStock:   100,
 		CurrentStock: 40,
 		AlertSent:    false,
+		LowStockThreshold: models.LowStockThreshold{
+			Type:    models.ThresholdTypePercentage,
+			Value:   50,
+			Default: true,
+		},
 	}
 
 	// Mock the query for getting an ingredient by ID
-	mock.ExpectQuery(`SELECT id, name, total_stock, current_stock, alert_sent FROM ingredients WHERE id = \$(\d)`).
+	mock.ExpectQuery(`SELECT id, name, unit, total_stock, current_stock, alert_sent, COALESCE\(low_stock_threshold_type, 'percentage'\), COALESCE\(low_stock_threshold, 50\), low_stock_threshold IS NULL, yield_percent, archived_at FROM ingredients WHERE id = \$(\d)`).
 		WithArgs(ingredientID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "total_stock", "current_stock", "alert_sent"}).
-			AddRow(expectedIngredient.ID, expectedIngredient.Name, expectedIngredient.TotalStock, expectedIngredient.CurrentStock, expectedIngredient.AlertSent))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "unit", "total_stock", "current_stock", "alert_sent", "threshold_type", "threshold", "threshold_default", "yield_percent", "archived_at"}).
+			AddRow(expectedIngredient.ID, expectedIngredient.Name, models.UnitGram, expectedIngredient.TotalStock, expectedIngredient.CurrentStock, expectedIngredient.AlertSent, models.ThresholdTypePercentage, 50, true, nil, nil))
 
 	// Call the method under test
 	ingredient, err := repo.GetIngredientByID(context.Background(), nil, ingredientID)
@@ -58,13 +66,13 @@ func TestIngredientRepository_GetIngredientByID_NotFound(t *testing.T) {
 	}
 	defer db.Close()
 
-	repo := NewIngredientRepository(db)
+	repo := NewIngredientRepository(db, 50)
 
 	// Define the expected ingredient ID that does not exist
 	ingredientID := 999
 
 	// Mock the query for getting an ingredient by ID, returning no rows
-	mock.ExpectQuery(`SELECT id, name, total_stock, current_stock, alert_sent FROM ingredients WHERE id = \$(\d)`).
+	mock.ExpectQuery(`SELECT id, name, unit, total_stock, current_stock, alert_sent, .+, archived_at FROM ingredients WHERE id = \$(\d)`).
 		WithArgs(ingredientID).
 		WillReturnError(sql.ErrNoRows)
 
@@ -90,22 +98,35 @@ func TestIngredientRepository_UpdateStock(t *testing.T) {
 	}
 	defer db.Close()
 
-	repo := NewIngredientRepository(db)
+	repo := NewIngredientRepository(db, 50)
 
 	// Define the ingredient ID and new stock value
 	ingredientID := 1
 	newStock := 60.0
 
-	// Mock the query for updating the stock
-	mock.ExpectExec(`UPDATE ingredients SET current_stock = \$(\d+) WHERE id = \$(\d)`).
+	mock.ExpectBegin()
+
+	// Mock the query for updating the stock, returning the previous stock
+	mock.ExpectQuery(`UPDATE ingredients i SET current_stock = \$1 FROM \(SELECT id, current_stock FROM ingredients WHERE id = \$2 FOR UPDATE\) previous WHERE i.id = previous.id RETURNING previous.current_stock`).
 		WithArgs(newStock, ingredientID).
-		WillReturnResult(sqlmock.NewResult(1, 1)) // Mock success for the update
+		WillReturnRows(sqlmock.NewRows([]string{"current_stock"}).AddRow(100.0))
+
+	// Mock the decrease being taken out of the lots
+	mock.ExpectExec(`UPDATE ingredient_lots l SET remaining_quantity = .+ WHERE l.id = f.id AND f.earlier < \$2`).
+		WithArgs(ingredientID, 40.0).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
 
 	// Call the method under test
-	err = repo.UpdateStock(context.Background(), nil, ingredientID, newStock)
+	previousStock, err := repo.UpdateStock(context.Background(), tx, ingredientID, newStock)
 
 	// Assertions
 	assert.NoError(t, err)
+	assert.Equal(t, 100.0, previousStock)
 
 	// Ensure that all expectations were met
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -113,7 +134,7 @@ func TestIngredientRepository_UpdateStock(t *testing.T) {
 	}
 }
 
-func TestIngredientRepository_CheckLowStockIngredients(t *testing.T) {
+func TestIngredientRepository_UpdateStock_WithoutTransaction(t *testing.T) {
 	// Create a mock DB and mock objects
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -121,24 +142,63 @@ func TestIngredientRepository_CheckLowStockIngredients(t *testing.T) {
 	}
 	defer db.Close()
 
-	repo := NewIngredientRepository(db)
+	repo := NewIngredientRepository(db, 50)
+
+	// The stock and its lots are changed in a transaction of their own
+	mock.ExpectBegin()
+	mock.ExpectQuery(`UPDATE ingredients i SET current_stock = \$1 FROM .+ RETURNING previous.current_stock`).
+		WithArgs(60.0, 1).
+		WillReturnRows(sqlmock.NewRows([]string{"current_stock"}).AddRow(100.0))
+	mock.ExpectExec(`UPDATE ingredient_lots l SET remaining_quantity = .+`).
+		WithArgs(1, 40.0).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
 
-	// Define the expected list of low stock ingredients
-	expectedLowStock := []models.Ingredient{
-		{ID: 1, Name: "Sugar", TotalStock: 100, CurrentStock: 40},
+	// Call the method under test
+	previousStock, err := repo.UpdateStock(context.Background(), nil, 1, 60)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 100.0, previousStock)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_IncrementStock(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
 	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	mock.ExpectBegin()
+
+	// Mock the relative stock update
+	mock.ExpectExec(`UPDATE ingredients SET current_stock = current_stock \+ \$1 WHERE id = \$2`).
+		WithArgs(300.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
 
-	// Mock the query for low stock ingredients
-	mock.ExpectQuery(`SELECT id, name, total_stock, current_stock FROM ingredients WHERE \(current_stock / total_stock \* 100\) < 50 AND alert_sent = false`).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "total_stock", "current_stock"}).
-			AddRow(expectedLowStock[0].ID, expectedLowStock[0].Name, expectedLowStock[0].TotalStock, expectedLowStock[0].CurrentStock))
+	// Mock the stock being put in the undated lot
+	mock.ExpectExec(`INSERT INTO ingredient_lots \(ingredient_id, received_quantity, remaining_quantity\) VALUES \(\$1, \$2, \$2\) ON CONFLICT \(ingredient_id\) WHERE receipt_id IS NULL`).
+		WithArgs(1, 300.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
 
 	// Call the method under test
-	lowStockIngredients, err := repo.CheckLowStockIngredients(context.Background())
+	err = repo.IncrementStock(context.Background(), tx, 1, 300)
 
 	// Assertions
 	assert.NoError(t, err)
-	assert.Equal(t, expectedLowStock, lowStockIngredients)
 
 	// Ensure that all expectations were met
 	if err := mock.ExpectationsWereMet(); err != nil {
@@ -146,7 +206,7 @@ func TestIngredientRepository_CheckLowStockIngredients(t *testing.T) {
 	}
 }
 
-func TestIngredientRepository_MarkAlertSent(t *testing.T) {
+func TestIngredientRepository_LockStock(t *testing.T) {
 	// Create a mock DB and mock objects
 	db, mock, err := sqlmock.New()
 	if err != nil {
@@ -154,21 +214,650 @@ func TestIngredientRepository_MarkAlertSent(t *testing.T) {
 	}
 	defer db.Close()
 
-	repo := NewIngredientRepository(db)
+	repo := NewIngredientRepository(db, 50)
 
-	// Define the ingredient ID
-	ingredientID := 1
+	mock.ExpectBegin()
 
-	// Mock the query for marking the alert as sent
-	mock.ExpectExec(`UPDATE ingredients SET alert_sent = true WHERE id = \$(\d)`).
-		WithArgs(ingredientID).
-		WillReturnResult(sqlmock.NewResult(1, 1)) // Mock success for the update
+	// Mock the locking query, ingredient 9 does not exist
+	mock.ExpectQuery(`SELECT id, current_stock FROM ingredients WHERE id IN \(\$1, \$2, \$3\) ORDER BY id FOR UPDATE`).
+		WithArgs(1, 4, 9).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "current_stock"}).
+			AddRow(1, 1000.0).
+			AddRow(4, 300.0))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	stock, err := repo.LockStock(context.Background(), tx, []int{1, 4, 9})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, map[int]float64{1: 1000, 4: 300}, stock)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_ResetAlertIfReplenished(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	mock.ExpectBegin()
+
+	// Mock the conditional alert reset
+	mock.ExpectExec(`UPDATE ingredients SET alert_sent = false WHERE id = \$1 AND alert_sent = true AND NOT CASE WHEN low_stock_threshold_type = 'quantity' THEN current_stock < low_stock_threshold ELSE current_stock < total_stock \* COALESCE\(low_stock_threshold, 50\) / 100 END`).
+		WithArgs(1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.ResetAlertIfReplenished(context.Background(), tx, 1)
+
+	// Assertions
+	assert.NoError(t, err)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_ClaimLowStockAlerts(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	mock.ExpectBegin()
+
+	// Mock flagging the low stock ingredients among those changed as alerted
+	mock.ExpectQuery(`UPDATE ingredients SET alert_sent = true WHERE id IN \(\$1, \$2, \$3\) AND CASE WHEN low_stock_threshold_type = 'quantity' THEN current_stock < low_stock_threshold ELSE current_stock < total_stock \* COALESCE\(low_stock_threshold, 50\) / 100 END AND alert_sent = false AND archived_at IS NULL RETURNING id, name, total_stock, current_stock`).
+		WithArgs(1, 2, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "total_stock", "current_stock", "threshold_type", "threshold", "threshold_default"}).
+			AddRow(1, "Sugar", 100, 40, models.ThresholdTypeQuantity, 50, false).
+			AddRow(3, "Onion", 1000, 160, models.ThresholdTypePercentage, 50, true))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	ingredients, err := repo.ClaimLowStockAlerts(context.Background(), tx, []int{1, 2, 3})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []models.Ingredient{
+		{ID: 1, Name: "Sugar", TotalStock: 100, CurrentStock: 40, LowStockThreshold: models.LowStockThreshold{Type: models.ThresholdTypeQuantity, Value: 50}},
+		{ID: 3, Name: "Onion", TotalStock: 1000, CurrentStock: 160, LowStockThreshold: models.LowStockThreshold{Type: models.ThresholdTypePercentage, Value: 50, Default: true}},
+	}, ingredients)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_ListIngredients(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	// Define the expected list of active ingredients
+	defaultThreshold := models.LowStockThreshold{Type: models.ThresholdTypePercentage, Value: 50, Default: true}
+	beefYield := 70.0
+	expectedIngredients := []models.Ingredient{
+		{ID: 1, Name: "Beef", Unit: models.UnitGram, TotalStock: 20000, CurrentStock: 19000, LowStockThreshold: defaultThreshold, YieldPercent: &beefYield},
+		{ID: 2, Name: "Cheese", Unit: models.UnitGram, TotalStock: 5000, CurrentStock: 2000, AlertSent: true, LowStockThreshold: defaultThreshold},
+	}
+
+	// Mock the query for listing ingredients
+	mock.ExpectQuery(`SELECT id, name, unit, total_stock, current_stock, alert_sent, COALESCE\(low_stock_threshold_type, 'percentage'\), COALESCE\(low_stock_threshold, 50\), low_stock_threshold IS NULL, yield_percent FROM ingredients WHERE archived_at IS NULL ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "unit", "total_stock", "current_stock", "alert_sent", "threshold_type", "threshold", "threshold_default", "yield_percent"}).
+			AddRow(1, "Beef", models.UnitGram, 20000, 19000, false, models.ThresholdTypePercentage, 50, true, 70.0).
+			AddRow(2, "Cheese", models.UnitGram, 5000, 2000, true, models.ThresholdTypePercentage, 50, true, nil))
+
+	// Call the method under test
+	ingredients, err := repo.ListIngredients(context.Background())
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, expectedIngredients, ingredients)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_CreateIngredient(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	ingredient := &models.Ingredient{
+		Name:              "Tomato",
+		Unit:              models.UnitGram,
+		TotalStock:        3000,
+		CurrentStock:      3000,
+		LowStockThreshold: models.LowStockThreshold{Type: models.ThresholdTypeQuantity, Value: 500},
+	}
+
+	mock.ExpectBegin()
+
+	// Mock the insert returning the generated ID
+	mock.ExpectQuery(`INSERT INTO ingredients \(name, unit, total_stock, current_stock, low_stock_threshold_type, low_stock_threshold, yield_percent\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) RETURNING id`).
+		WithArgs("Tomato", models.UnitGram, 3000.0, 3000.0, models.ThresholdTypeQuantity, 500.0, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(4))
+
+	// Mock the stock being put in the undated lot
+	mock.ExpectExec(`INSERT INTO ingredient_lots \(ingredient_id, received_quantity, remaining_quantity\) VALUES \(\$1, \$2, \$2\) ON CONFLICT \(ingredient_id\) WHERE receipt_id IS NULL`).
+		WithArgs(4, 3000.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.CreateIngredient(context.Background(), tx, ingredient)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 4, ingredient.ID)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_RenameIngredient(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	// Mock the rename update
+	mock.ExpectExec(`UPDATE ingredients SET name = \$1 WHERE id = \$2 AND archived_at IS NULL`).
+		WithArgs("Minced Beef", 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Call the method under test
+	err = repo.RenameIngredient(context.Background(), 1, "Minced Beef")
+
+	// Assertions
+	assert.NoError(t, err)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_UpdateTotalStock(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	// Mock the total stock update
+	mock.ExpectExec(`UPDATE ingredients SET total_stock = \$1 WHERE id = \$2 AND archived_at IS NULL`).
+		WithArgs(25000.0, 1).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	// Call the method under test
+	err = repo.UpdateTotalStock(context.Background(), 1, 25000)
+
+	// Assertions
+	assert.NoError(t, err)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_ArchiveIngredient_NotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	// Mock the archive update affecting no rows
+	mock.ExpectExec(`UPDATE ingredients SET archived_at = NOW\(\) WHERE id = \$1 AND archived_at IS NULL`).
+		WithArgs(999).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Call the method under test
+	err = repo.ArchiveIngredient(context.Background(), 999)
+
+	// Assertions
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "Resource not found")
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_SetLowStockThreshold(t *testing.T) {
+	testCases := []struct {
+		name          string
+		threshold     *models.LowStockThreshold
+		expectedType  interface{}
+		expectedValue interface{}
+	}{
+		{
+			name:          "Custom Threshold",
+			threshold:     &models.LowStockThreshold{Type: models.ThresholdTypeQuantity, Value: 500},
+			expectedType:  models.ThresholdTypeQuantity,
+			expectedValue: 500.0,
+		},
+		{
+			name:          "Revert To Default",
+			threshold:     nil,
+			expectedType:  nil,
+			expectedValue: nil,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Create a mock DB and mock objects
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to open mock database: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewIngredientRepository(db, 50)
+
+			// Mock the threshold update
+			mock.ExpectExec(`UPDATE ingredients SET low_stock_threshold_type = \$1, low_stock_threshold = \$2 WHERE id = \$3 AND archived_at IS NULL`).
+				WithArgs(tc.expectedType, tc.expectedValue, 1).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+
+			// Call the method under test
+			err = repo.SetLowStockThreshold(context.Background(), 1, tc.threshold)
+
+			// Assertions
+			assert.NoError(t, err)
+
+			// Ensure that all expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("There were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestIngredientRepository_SetYieldPercent_NotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	// Mock the yield update matching no active ingredient
+	mock.ExpectExec(`UPDATE ingredients SET yield_percent = \$1 WHERE id = \$2 AND archived_at IS NULL`).
+		WithArgs(90.0, 99).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Call the method under test
+	yieldPercent := 90.0
+	err = repo.SetYieldPercent(context.Background(), 99, &yieldPercent)
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_DecrementStock(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	mock.ExpectBegin()
+
+	// Mock the guarded decrement succeeding
+	mock.ExpectExec(`UPDATE ingredients SET current_stock = current_stock - \$1 WHERE id = \$2 AND current_stock >= \$1 AND archived_at IS NULL`).
+		WithArgs(150.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Mock the amount being taken out of the lots
+	mock.ExpectExec(`UPDATE ingredient_lots l SET remaining_quantity = .+ WHERE l.id = f.id AND f.earlier < \$2`).
+		WithArgs(1, 150.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.DecrementStock(context.Background(), tx, 1, 150)
+
+	// Assertions
+	assert.NoError(t, err)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_DecrementStock_Insufficient(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	mock.ExpectBegin()
+
+	// Mock the guarded decrement matching no row
+	mock.ExpectExec(`UPDATE ingredients SET current_stock = current_stock - \$1 WHERE id = \$2 AND current_stock >= \$1 AND archived_at IS NULL`).
+		WithArgs(150.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Mock the lookup telling a short stock apart from an unknown ingredient
+	mock.ExpectQuery(`SELECT name, archived_at IS NOT NULL FROM ingredients WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "archived"}).AddRow("Beef", false))
+
+	mock.ExpectRollback()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.DecrementStock(context.Background(), tx, 1, 150)
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, internalErrors.ErrCodeInsufficientStock, appErr.Code)
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatalf("Failed to rollback transaction: %v", err)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_DecrementStock_Archived(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	mock.ExpectBegin()
+
+	// Mock the guarded decrement matching no row
+	mock.ExpectExec(`UPDATE ingredients SET current_stock = current_stock - \$1 WHERE id = \$2 AND current_stock >= \$1 AND archived_at IS NULL`).
+		WithArgs(150.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Mock the lookup finding the ingredient archived
+	mock.ExpectQuery(`SELECT name, archived_at IS NOT NULL FROM ingredients WHERE id = \$1`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"name", "archived"}).AddRow("Beef", true))
+
+	mock.ExpectRollback()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
 
 	// Call the method under test
-	err = repo.MarkAlertSent(context.Background(), ingredientID)
+	err = repo.DecrementStock(context.Background(), tx, 1, 150)
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, "Ingredient archived", appErr.Message)
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatalf("Failed to rollback transaction: %v", err)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_ReceiveLot(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	receiptID := 3
+	receiptItemID := 7
+	expiresAt := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	receivedAt := time.Now()
+	lot := &models.IngredientLot{
+		IngredientID:     1,
+		ReceiptID:        &receiptID,
+		ReceiptItemID:    &receiptItemID,
+		LotNumber:        "L-2291",
+		ExpiresAt:        &expiresAt,
+		ReceivedQuantity: 5000,
+	}
+
+	mock.ExpectBegin()
+
+	// Mock the relative stock update
+	mock.ExpectExec(`UPDATE ingredients SET current_stock = current_stock \+ \$1 WHERE id = \$2`).
+		WithArgs(5000.0, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Mock the lot insert
+	mock.ExpectQuery(`INSERT INTO ingredient_lots \(ingredient_id, receipt_id, receipt_item_id, lot_number, expires_at, received_quantity, remaining_quantity\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$6\) RETURNING id, remaining_quantity, received_at`).
+		WithArgs(1, &receiptID, &receiptItemID, "L-2291", &expiresAt, 5000.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "remaining_quantity", "received_at"}).AddRow(12, 5000.0, receivedAt))
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.ReceiveLot(context.Background(), tx, lot)
 
 	// Assertions
 	assert.NoError(t, err)
+	assert.Equal(t, 12, lot.ID)
+	assert.Equal(t, 5000.0, lot.RemainingQuantity)
+	assert.Equal(t, receivedAt, lot.ReceivedAt)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_ListExpiringLots(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	before := time.Date(2026, 3, 21, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	receivedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	// Mock the query for the lots expiring in the window
+	mock.ExpectQuery(`SELECT l.id, l.ingredient_id, i.name, .+ FROM ingredient_lots l JOIN ingredients i ON i.id = l.ingredient_id WHERE l.expires_at < \$1 AND l.remaining_quantity > 0 ORDER BY l.expires_at, l.id`).
+		WithArgs(before).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ingredient_id", "name", "receipt_id", "receipt_item_id", "lot_number", "expires_at", "received_quantity", "remaining_quantity", "received_at"}).
+			AddRow(12, 1, "Beef", 3, 7, "L-2291", expiresAt, 5000.0, 1200.0, receivedAt))
+
+	// Call the method under test
+	lots, err := repo.ListExpiringLots(context.Background(), before)
+
+	// Assertions
+	assert.NoError(t, err)
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, "Beef", lots[0].IngredientName)
+		assert.Equal(t, 3, *lots[0].ReceiptID)
+		assert.Equal(t, expiresAt, *lots[0].ExpiresAt)
+		assert.Equal(t, 1200.0, lots[0].RemainingQuantity)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestIngredientRepository_ClaimExpiryAlerts(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewIngredientRepository(db, 50)
+
+	expiresAt := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	receivedAt := time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)
+
+	mock.ExpectBegin()
+
+	// Mock the claim of the lots not alerted for the window yet
+	mock.ExpectQuery(`WITH claimed AS \( INSERT INTO lot_expiry_alerts \(lot_id, window_days\) SELECT l.id, \$1 .+ ON CONFLICT DO NOTHING RETURNING lot_id \) SELECT l.id, .+ FROM claimed c`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ingredient_id", "name", "receipt_id", "receipt_item_id", "lot_number", "expires_at", "received_quantity", "remaining_quantity", "received_at"}).
+			AddRow(12, 1, "Beef", 3, 7, "L-2291", expiresAt, 5000.0, 1200.0, receivedAt))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	lots, err := repo.ClaimExpiryAlerts(context.Background(), tx, 3)
+
+	// Assertions
+	assert.NoError(t, err)
+	if assert.Len(t, lots, 1) {
+		assert.Equal(t, 12, lots[0].ID)
+		assert.Equal(t, "L-2291", lots[0].LotNumber)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
 
 	// Ensure that all expectations were met
 	if err := mock.ExpectationsWereMet(); err != nil {
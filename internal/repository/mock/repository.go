@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: stockk/internal/repository (interfaces: IngredientRepository,OrderRepository,ProductRepository,TaskQueueRepository,Transaction)
+// Source: stockk/internal/repository (interfaces: IdempotencyRepository,IngredientPackRepository,IngredientRepository,OrderRepository,OutboxRepository,PrepRecipeRepository,ProductRepository,ReceiptRepository,ReportRepository,StockCountRepository,StockMovementRepository,SupplierRepository,TaskQueueRepository,Transaction,WasteRepository)
 //
 // Generated by this command:
 //
-//	mockgen -package mockrepository -destination internal/repository/mock/repository.go stockk/internal/repository IngredientRepository,OrderRepository,ProductRepository,TaskQueueRepository,Transaction
+//	mockgen -package mockrepository -destination internal/repository/mock/repository.go stockk/internal/repository IdempotencyRepository,IngredientPackRepository,IngredientRepository,OrderRepository,OutboxRepository,PrepRecipeRepository,ProductRepository,ReceiptRepository,ReportRepository,StockCountRepository,StockMovementRepository,SupplierRepository,TaskQueueRepository,Transaction,WasteRepository
 //
 
 // Package mockrepository is a generated GoMock package.
@@ -15,119 +15,1403 @@ import (
 	reflect "reflect"
 	models "stockk/internal/models"
 	repository "stockk/internal/repository"
+	time "time"
 
 	asynq "github.com/hibiken/asynq"
 	gomock "go.uber.org/mock/gomock"
 )
 
+// MockIdempotencyRepository is a mock of IdempotencyRepository interface.
+type MockIdempotencyRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIdempotencyRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIdempotencyRepositoryMockRecorder is the mock recorder for MockIdempotencyRepository.
+type MockIdempotencyRepositoryMockRecorder struct {
+	mock *MockIdempotencyRepository
+}
+
+// NewMockIdempotencyRepository creates a new mock instance.
+func NewMockIdempotencyRepository(ctrl *gomock.Controller) *MockIdempotencyRepository {
+	mock := &MockIdempotencyRepository{ctrl: ctrl}
+	mock.recorder = &MockIdempotencyRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIdempotencyRepository) EXPECT() *MockIdempotencyRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ClaimKey mocks base method.
+func (m *MockIdempotencyRepository) ClaimKey(ctx context.Context, tx repository.Transaction, key, requestHash string) (*models.IdempotencyKey, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimKey", ctx, tx, key, requestHash)
+	ret0, _ := ret[0].(*models.IdempotencyKey)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimKey indicates an expected call of ClaimKey.
+func (mr *MockIdempotencyRepositoryMockRecorder) ClaimKey(ctx, tx, key, requestHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimKey", reflect.TypeOf((*MockIdempotencyRepository)(nil).ClaimKey), ctx, tx, key, requestHash)
+}
+
+// PurgeExpiredKeys mocks base method.
+func (m *MockIdempotencyRepository) PurgeExpiredKeys(ctx context.Context) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpiredKeys", ctx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpiredKeys indicates an expected call of PurgeExpiredKeys.
+func (mr *MockIdempotencyRepositoryMockRecorder) PurgeExpiredKeys(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpiredKeys", reflect.TypeOf((*MockIdempotencyRepository)(nil).PurgeExpiredKeys), ctx)
+}
+
+// SaveResponse mocks base method.
+func (m *MockIdempotencyRepository) SaveResponse(ctx context.Context, tx repository.Transaction, key string, response []byte) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveResponse", ctx, tx, key, response)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveResponse indicates an expected call of SaveResponse.
+func (mr *MockIdempotencyRepositoryMockRecorder) SaveResponse(ctx, tx, key, response any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveResponse", reflect.TypeOf((*MockIdempotencyRepository)(nil).SaveResponse), ctx, tx, key, response)
+}
+
+// MockIngredientPackRepository is a mock of IngredientPackRepository interface.
+type MockIngredientPackRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockIngredientPackRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIngredientPackRepositoryMockRecorder is the mock recorder for MockIngredientPackRepository.
+type MockIngredientPackRepositoryMockRecorder struct {
+	mock *MockIngredientPackRepository
+}
+
+// NewMockIngredientPackRepository creates a new mock instance.
+func NewMockIngredientPackRepository(ctrl *gomock.Controller) *MockIngredientPackRepository {
+	mock := &MockIngredientPackRepository{ctrl: ctrl}
+	mock.recorder = &MockIngredientPackRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIngredientPackRepository) EXPECT() *MockIngredientPackRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreatePack mocks base method.
+func (m *MockIngredientPackRepository) CreatePack(ctx context.Context, pack *models.IngredientPack) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePack", ctx, pack)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreatePack indicates an expected call of CreatePack.
+func (mr *MockIngredientPackRepositoryMockRecorder) CreatePack(ctx, pack any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePack", reflect.TypeOf((*MockIngredientPackRepository)(nil).CreatePack), ctx, pack)
+}
+
+// DeletePack mocks base method.
+func (m *MockIngredientPackRepository) DeletePack(ctx context.Context, ingredientID, packID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePack", ctx, ingredientID, packID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePack indicates an expected call of DeletePack.
+func (mr *MockIngredientPackRepositoryMockRecorder) DeletePack(ctx, ingredientID, packID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePack", reflect.TypeOf((*MockIngredientPackRepository)(nil).DeletePack), ctx, ingredientID, packID)
+}
+
+// GetPackByID mocks base method.
+func (m *MockIngredientPackRepository) GetPackByID(ctx context.Context, tx repository.Transaction, packID int) (*models.IngredientPack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPackByID", ctx, tx, packID)
+	ret0, _ := ret[0].(*models.IngredientPack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPackByID indicates an expected call of GetPackByID.
+func (mr *MockIngredientPackRepositoryMockRecorder) GetPackByID(ctx, tx, packID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPackByID", reflect.TypeOf((*MockIngredientPackRepository)(nil).GetPackByID), ctx, tx, packID)
+}
+
+// ListPacks mocks base method.
+func (m *MockIngredientPackRepository) ListPacks(ctx context.Context, ingredientID int) ([]models.IngredientPack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPacks", ctx, ingredientID)
+	ret0, _ := ret[0].([]models.IngredientPack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPacks indicates an expected call of ListPacks.
+func (mr *MockIngredientPackRepositoryMockRecorder) ListPacks(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPacks", reflect.TypeOf((*MockIngredientPackRepository)(nil).ListPacks), ctx, ingredientID)
+}
+
 // MockIngredientRepository is a mock of IngredientRepository interface.
 type MockIngredientRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockIngredientRepositoryMockRecorder
+	recorder *MockIngredientRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockIngredientRepositoryMockRecorder is the mock recorder for MockIngredientRepository.
+type MockIngredientRepositoryMockRecorder struct {
+	mock *MockIngredientRepository
+}
+
+// NewMockIngredientRepository creates a new mock instance.
+func NewMockIngredientRepository(ctrl *gomock.Controller) *MockIngredientRepository {
+	mock := &MockIngredientRepository{ctrl: ctrl}
+	mock.recorder = &MockIngredientRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockIngredientRepository) EXPECT() *MockIngredientRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ArchiveIngredient mocks base method.
+func (m *MockIngredientRepository) ArchiveIngredient(ctx context.Context, ingredientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ArchiveIngredient", ctx, ingredientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ArchiveIngredient indicates an expected call of ArchiveIngredient.
+func (mr *MockIngredientRepositoryMockRecorder) ArchiveIngredient(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveIngredient", reflect.TypeOf((*MockIngredientRepository)(nil).ArchiveIngredient), ctx, ingredientID)
+}
+
+// BeginTransaction mocks base method.
+func (m *MockIngredientRepository) BeginTransaction() (repository.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockIngredientRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockIngredientRepository)(nil).BeginTransaction))
+}
+
+// ClaimExpiryAlerts mocks base method.
+func (m *MockIngredientRepository) ClaimExpiryAlerts(ctx context.Context, tx repository.Transaction, windowDays int) ([]models.IngredientLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimExpiryAlerts", ctx, tx, windowDays)
+	ret0, _ := ret[0].([]models.IngredientLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimExpiryAlerts indicates an expected call of ClaimExpiryAlerts.
+func (mr *MockIngredientRepositoryMockRecorder) ClaimExpiryAlerts(ctx, tx, windowDays any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimExpiryAlerts", reflect.TypeOf((*MockIngredientRepository)(nil).ClaimExpiryAlerts), ctx, tx, windowDays)
+}
+
+// ClaimLowStockAlerts mocks base method.
+func (m *MockIngredientRepository) ClaimLowStockAlerts(ctx context.Context, tx repository.Transaction, ingredientIDs []int) ([]models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimLowStockAlerts", ctx, tx, ingredientIDs)
+	ret0, _ := ret[0].([]models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimLowStockAlerts indicates an expected call of ClaimLowStockAlerts.
+func (mr *MockIngredientRepositoryMockRecorder) ClaimLowStockAlerts(ctx, tx, ingredientIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimLowStockAlerts", reflect.TypeOf((*MockIngredientRepository)(nil).ClaimLowStockAlerts), ctx, tx, ingredientIDs)
+}
+
+// CreateIngredient mocks base method.
+func (m *MockIngredientRepository) CreateIngredient(ctx context.Context, tx repository.Transaction, ingredient *models.Ingredient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngredient", ctx, tx, ingredient)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateIngredient indicates an expected call of CreateIngredient.
+func (mr *MockIngredientRepositoryMockRecorder) CreateIngredient(ctx, tx, ingredient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngredient", reflect.TypeOf((*MockIngredientRepository)(nil).CreateIngredient), ctx, tx, ingredient)
+}
+
+// DecrementStock mocks base method.
+func (m *MockIngredientRepository) DecrementStock(ctx context.Context, tx repository.Transaction, ingredientID int, amount float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DecrementStock", ctx, tx, ingredientID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DecrementStock indicates an expected call of DecrementStock.
+func (mr *MockIngredientRepositoryMockRecorder) DecrementStock(ctx, tx, ingredientID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DecrementStock", reflect.TypeOf((*MockIngredientRepository)(nil).DecrementStock), ctx, tx, ingredientID, amount)
+}
+
+// GetIngredientByID mocks base method.
+func (m *MockIngredientRepository) GetIngredientByID(ctx context.Context, tx repository.Transaction, ingredientID int) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngredientByID", ctx, tx, ingredientID)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngredientByID indicates an expected call of GetIngredientByID.
+func (mr *MockIngredientRepositoryMockRecorder) GetIngredientByID(ctx, tx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngredientByID", reflect.TypeOf((*MockIngredientRepository)(nil).GetIngredientByID), ctx, tx, ingredientID)
+}
+
+// IncrementStock mocks base method.
+func (m *MockIngredientRepository) IncrementStock(ctx context.Context, tx repository.Transaction, ingredientID int, amount float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementStock", ctx, tx, ingredientID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementStock indicates an expected call of IncrementStock.
+func (mr *MockIngredientRepositoryMockRecorder) IncrementStock(ctx, tx, ingredientID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementStock", reflect.TypeOf((*MockIngredientRepository)(nil).IncrementStock), ctx, tx, ingredientID, amount)
+}
+
+// IncrementTotalStock mocks base method.
+func (m *MockIngredientRepository) IncrementTotalStock(ctx context.Context, tx repository.Transaction, ingredientID int, amount float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IncrementTotalStock", ctx, tx, ingredientID, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// IncrementTotalStock indicates an expected call of IncrementTotalStock.
+func (mr *MockIngredientRepositoryMockRecorder) IncrementTotalStock(ctx, tx, ingredientID, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IncrementTotalStock", reflect.TypeOf((*MockIngredientRepository)(nil).IncrementTotalStock), ctx, tx, ingredientID, amount)
+}
+
+// ListExpiringLots mocks base method.
+func (m *MockIngredientRepository) ListExpiringLots(ctx context.Context, before time.Time) ([]models.IngredientLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiringLots", ctx, before)
+	ret0, _ := ret[0].([]models.IngredientLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiringLots indicates an expected call of ListExpiringLots.
+func (mr *MockIngredientRepositoryMockRecorder) ListExpiringLots(ctx, before any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiringLots", reflect.TypeOf((*MockIngredientRepository)(nil).ListExpiringLots), ctx, before)
+}
+
+// ListIngredients mocks base method.
+func (m *MockIngredientRepository) ListIngredients(ctx context.Context) ([]models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngredients", ctx)
+	ret0, _ := ret[0].([]models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIngredients indicates an expected call of ListIngredients.
+func (mr *MockIngredientRepositoryMockRecorder) ListIngredients(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngredients", reflect.TypeOf((*MockIngredientRepository)(nil).ListIngredients), ctx)
+}
+
+// ListLots mocks base method.
+func (m *MockIngredientRepository) ListLots(ctx context.Context, ingredientID int) ([]models.IngredientLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLots", ctx, ingredientID)
+	ret0, _ := ret[0].([]models.IngredientLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLots indicates an expected call of ListLots.
+func (mr *MockIngredientRepositoryMockRecorder) ListLots(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLots", reflect.TypeOf((*MockIngredientRepository)(nil).ListLots), ctx, ingredientID)
+}
+
+// LockStock mocks base method.
+func (m *MockIngredientRepository) LockStock(ctx context.Context, tx repository.Transaction, ingredientIDs []int) (map[int]float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockStock", ctx, tx, ingredientIDs)
+	ret0, _ := ret[0].(map[int]float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// LockStock indicates an expected call of LockStock.
+func (mr *MockIngredientRepositoryMockRecorder) LockStock(ctx, tx, ingredientIDs any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockStock", reflect.TypeOf((*MockIngredientRepository)(nil).LockStock), ctx, tx, ingredientIDs)
+}
+
+// ReceiveLot mocks base method.
+func (m *MockIngredientRepository) ReceiveLot(ctx context.Context, tx repository.Transaction, lot *models.IngredientLot) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveLot", ctx, tx, lot)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReceiveLot indicates an expected call of ReceiveLot.
+func (mr *MockIngredientRepositoryMockRecorder) ReceiveLot(ctx, tx, lot any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveLot", reflect.TypeOf((*MockIngredientRepository)(nil).ReceiveLot), ctx, tx, lot)
+}
+
+// RenameIngredient mocks base method.
+func (m *MockIngredientRepository) RenameIngredient(ctx context.Context, ingredientID int, name string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameIngredient", ctx, ingredientID, name)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RenameIngredient indicates an expected call of RenameIngredient.
+func (mr *MockIngredientRepositoryMockRecorder) RenameIngredient(ctx, ingredientID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameIngredient", reflect.TypeOf((*MockIngredientRepository)(nil).RenameIngredient), ctx, ingredientID, name)
+}
+
+// ResetAlertIfReplenished mocks base method.
+func (m *MockIngredientRepository) ResetAlertIfReplenished(ctx context.Context, tx repository.Transaction, ingredientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetAlertIfReplenished", ctx, tx, ingredientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetAlertIfReplenished indicates an expected call of ResetAlertIfReplenished.
+func (mr *MockIngredientRepositoryMockRecorder) ResetAlertIfReplenished(ctx, tx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetAlertIfReplenished", reflect.TypeOf((*MockIngredientRepository)(nil).ResetAlertIfReplenished), ctx, tx, ingredientID)
+}
+
+// SetLowStockThreshold mocks base method.
+func (m *MockIngredientRepository) SetLowStockThreshold(ctx context.Context, ingredientID int, threshold *models.LowStockThreshold) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLowStockThreshold", ctx, ingredientID, threshold)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetLowStockThreshold indicates an expected call of SetLowStockThreshold.
+func (mr *MockIngredientRepositoryMockRecorder) SetLowStockThreshold(ctx, ingredientID, threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLowStockThreshold", reflect.TypeOf((*MockIngredientRepository)(nil).SetLowStockThreshold), ctx, ingredientID, threshold)
+}
+
+// SetYieldPercent mocks base method.
+func (m *MockIngredientRepository) SetYieldPercent(ctx context.Context, ingredientID int, yieldPercent *float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetYieldPercent", ctx, ingredientID, yieldPercent)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetYieldPercent indicates an expected call of SetYieldPercent.
+func (mr *MockIngredientRepositoryMockRecorder) SetYieldPercent(ctx, ingredientID, yieldPercent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetYieldPercent", reflect.TypeOf((*MockIngredientRepository)(nil).SetYieldPercent), ctx, ingredientID, yieldPercent)
+}
+
+// UpdateStock mocks base method.
+func (m *MockIngredientRepository) UpdateStock(ctx context.Context, tx repository.Transaction, ingredientID int, newStock float64) (float64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStock", ctx, tx, ingredientID, newStock)
+	ret0, _ := ret[0].(float64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStock indicates an expected call of UpdateStock.
+func (mr *MockIngredientRepositoryMockRecorder) UpdateStock(ctx, tx, ingredientID, newStock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStock", reflect.TypeOf((*MockIngredientRepository)(nil).UpdateStock), ctx, tx, ingredientID, newStock)
+}
+
+// UpdateTotalStock mocks base method.
+func (m *MockIngredientRepository) UpdateTotalStock(ctx context.Context, ingredientID int, totalStock float64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTotalStock", ctx, ingredientID, totalStock)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTotalStock indicates an expected call of UpdateTotalStock.
+func (mr *MockIngredientRepositoryMockRecorder) UpdateTotalStock(ctx, ingredientID, totalStock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTotalStock", reflect.TypeOf((*MockIngredientRepository)(nil).UpdateTotalStock), ctx, ingredientID, totalStock)
+}
+
+// MockOrderRepository is a mock of OrderRepository interface.
+type MockOrderRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOrderRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOrderRepositoryMockRecorder is the mock recorder for MockOrderRepository.
+type MockOrderRepositoryMockRecorder struct {
+	mock *MockOrderRepository
+}
+
+// NewMockOrderRepository creates a new mock instance.
+func NewMockOrderRepository(ctrl *gomock.Controller) *MockOrderRepository {
+	mock := &MockOrderRepository{ctrl: ctrl}
+	mock.recorder = &MockOrderRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddOrderConsumption mocks base method.
+func (m *MockOrderRepository) AddOrderConsumption(ctx context.Context, tx repository.Transaction, consumption models.OrderConsumption) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddOrderConsumption", ctx, tx, consumption)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddOrderConsumption indicates an expected call of AddOrderConsumption.
+func (mr *MockOrderRepositoryMockRecorder) AddOrderConsumption(ctx, tx, consumption any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddOrderConsumption", reflect.TypeOf((*MockOrderRepository)(nil).AddOrderConsumption), ctx, tx, consumption)
+}
+
+// BeginTransaction mocks base method.
+func (m *MockOrderRepository) BeginTransaction() (repository.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockOrderRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockOrderRepository)(nil).BeginTransaction))
+}
+
+// CancelOrder mocks base method.
+func (m *MockOrderRepository) CancelOrder(ctx context.Context, tx repository.Transaction, orderID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, tx, orderID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderRepositoryMockRecorder) CancelOrder(ctx, tx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderRepository)(nil).CancelOrder), ctx, tx, orderID)
+}
+
+// CreateOrder mocks base method.
+func (m *MockOrderRepository) CreateOrder(ctx context.Context, tx repository.Transaction, order *models.Order) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrder", ctx, tx, order)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateOrder indicates an expected call of CreateOrder.
+func (mr *MockOrderRepositoryMockRecorder) CreateOrder(ctx, tx, order any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrder), ctx, tx, order)
+}
+
+// GetOrderByID mocks base method.
+func (m *MockOrderRepository) GetOrderByID(ctx context.Context, orderId int) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderByID", ctx, orderId)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderByID indicates an expected call of GetOrderByID.
+func (mr *MockOrderRepositoryMockRecorder) GetOrderByID(ctx, orderId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockOrderRepository)(nil).GetOrderByID), ctx, orderId)
+}
+
+// GetOrderConsumptions mocks base method.
+func (m *MockOrderRepository) GetOrderConsumptions(ctx context.Context, tx repository.Transaction, orderID int) ([]models.OrderConsumption, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrderConsumptions", ctx, tx, orderID)
+	ret0, _ := ret[0].([]models.OrderConsumption)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrderConsumptions indicates an expected call of GetOrderConsumptions.
+func (mr *MockOrderRepositoryMockRecorder) GetOrderConsumptions(ctx, tx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderConsumptions", reflect.TypeOf((*MockOrderRepository)(nil).GetOrderConsumptions), ctx, tx, orderID)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrderRepository) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].([]models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderRepositoryMockRecorder) ListOrders(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderRepository)(nil).ListOrders), ctx, filter)
+}
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// AddMessage mocks base method.
+func (m *MockOutboxRepository) AddMessage(ctx context.Context, tx repository.Transaction, message *models.OutboxMessage) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddMessage", ctx, tx, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddMessage indicates an expected call of AddMessage.
+func (mr *MockOutboxRepositoryMockRecorder) AddMessage(ctx, tx, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddMessage", reflect.TypeOf((*MockOutboxRepository)(nil).AddMessage), ctx, tx, message)
+}
+
+// BeginTransaction mocks base method.
+func (m *MockOutboxRepository) BeginTransaction() (repository.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockOutboxRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockOutboxRepository)(nil).BeginTransaction))
+}
+
+// ListPendingMessages mocks base method.
+func (m *MockOutboxRepository) ListPendingMessages(ctx context.Context, tx repository.Transaction, limit int) ([]models.OutboxMessage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPendingMessages", ctx, tx, limit)
+	ret0, _ := ret[0].([]models.OutboxMessage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPendingMessages indicates an expected call of ListPendingMessages.
+func (mr *MockOutboxRepositoryMockRecorder) ListPendingMessages(ctx, tx, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPendingMessages", reflect.TypeOf((*MockOutboxRepository)(nil).ListPendingMessages), ctx, tx, limit)
+}
+
+// MarkDispatched mocks base method.
+func (m *MockOutboxRepository) MarkDispatched(ctx context.Context, tx repository.Transaction, messageID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDispatched", ctx, tx, messageID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDispatched indicates an expected call of MarkDispatched.
+func (mr *MockOutboxRepositoryMockRecorder) MarkDispatched(ctx, tx, messageID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDispatched", reflect.TypeOf((*MockOutboxRepository)(nil).MarkDispatched), ctx, tx, messageID)
+}
+
+// RecordFailure mocks base method.
+func (m *MockOutboxRepository) RecordFailure(ctx context.Context, tx repository.Transaction, messageID int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailure", ctx, tx, messageID, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailure indicates an expected call of RecordFailure.
+func (mr *MockOutboxRepositoryMockRecorder) RecordFailure(ctx, tx, messageID, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailure", reflect.TypeOf((*MockOutboxRepository)(nil).RecordFailure), ctx, tx, messageID, reason)
+}
+
+// MockPrepRecipeRepository is a mock of PrepRecipeRepository interface.
+type MockPrepRecipeRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrepRecipeRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockPrepRecipeRepositoryMockRecorder is the mock recorder for MockPrepRecipeRepository.
+type MockPrepRecipeRepositoryMockRecorder struct {
+	mock *MockPrepRecipeRepository
+}
+
+// NewMockPrepRecipeRepository creates a new mock instance.
+func NewMockPrepRecipeRepository(ctrl *gomock.Controller) *MockPrepRecipeRepository {
+	mock := &MockPrepRecipeRepository{ctrl: ctrl}
+	mock.recorder = &MockPrepRecipeRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrepRecipeRepository) EXPECT() *MockPrepRecipeRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BeginTransaction mocks base method.
+func (m *MockPrepRecipeRepository) BeginTransaction() (repository.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockPrepRecipeRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockPrepRecipeRepository)(nil).BeginTransaction))
+}
+
+// DeletePrepRecipe mocks base method.
+func (m *MockPrepRecipeRepository) DeletePrepRecipe(ctx context.Context, ingredientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePrepRecipe", ctx, ingredientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePrepRecipe indicates an expected call of DeletePrepRecipe.
+func (mr *MockPrepRecipeRepositoryMockRecorder) DeletePrepRecipe(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrepRecipe", reflect.TypeOf((*MockPrepRecipeRepository)(nil).DeletePrepRecipe), ctx, ingredientID)
+}
+
+// GetPrepRecipe mocks base method.
+func (m *MockPrepRecipeRepository) GetPrepRecipe(ctx context.Context, ingredientID int) (*models.PrepRecipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrepRecipe", ctx, ingredientID)
+	ret0, _ := ret[0].(*models.PrepRecipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrepRecipe indicates an expected call of GetPrepRecipe.
+func (mr *MockPrepRecipeRepositoryMockRecorder) GetPrepRecipe(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrepRecipe", reflect.TypeOf((*MockPrepRecipeRepository)(nil).GetPrepRecipe), ctx, ingredientID)
+}
+
+// ListPrepRecipes mocks base method.
+func (m *MockPrepRecipeRepository) ListPrepRecipes(ctx context.Context, tx repository.Transaction) ([]models.PrepRecipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPrepRecipes", ctx, tx)
+	ret0, _ := ret[0].([]models.PrepRecipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPrepRecipes indicates an expected call of ListPrepRecipes.
+func (mr *MockPrepRecipeRepositoryMockRecorder) ListPrepRecipes(ctx, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPrepRecipes", reflect.TypeOf((*MockPrepRecipeRepository)(nil).ListPrepRecipes), ctx, tx)
+}
+
+// LockPrepRecipes mocks base method.
+func (m *MockPrepRecipeRepository) LockPrepRecipes(ctx context.Context, tx repository.Transaction) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockPrepRecipes", ctx, tx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockPrepRecipes indicates an expected call of LockPrepRecipes.
+func (mr *MockPrepRecipeRepositoryMockRecorder) LockPrepRecipes(ctx, tx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockPrepRecipes", reflect.TypeOf((*MockPrepRecipeRepository)(nil).LockPrepRecipes), ctx, tx)
+}
+
+// SavePrepRecipe mocks base method.
+func (m *MockPrepRecipeRepository) SavePrepRecipe(ctx context.Context, tx repository.Transaction, recipe *models.PrepRecipe) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePrepRecipe", ctx, tx, recipe)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePrepRecipe indicates an expected call of SavePrepRecipe.
+func (mr *MockPrepRecipeRepositoryMockRecorder) SavePrepRecipe(ctx, tx, recipe any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePrepRecipe", reflect.TypeOf((*MockPrepRecipeRepository)(nil).SavePrepRecipe), ctx, tx, recipe)
+}
+
+// MockProductRepository is a mock of ProductRepository interface.
+type MockProductRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockProductRepositoryMockRecorder is the mock recorder for MockProductRepository.
+type MockProductRepositoryMockRecorder struct {
+	mock *MockProductRepository
+}
+
+// NewMockProductRepository creates a new mock instance.
+func NewMockProductRepository(ctrl *gomock.Controller) *MockProductRepository {
+	mock := &MockProductRepository{ctrl: ctrl}
+	mock.recorder = &MockProductRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BeginTransaction mocks base method.
+func (m *MockProductRepository) BeginTransaction() (repository.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockProductRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockProductRepository)(nil).BeginTransaction))
+}
+
+// ClearProductIngredients mocks base method.
+func (m *MockProductRepository) ClearProductIngredients(ctx context.Context, tx repository.Transaction, productID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearProductIngredients", ctx, tx, productID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearProductIngredients indicates an expected call of ClearProductIngredients.
+func (mr *MockProductRepositoryMockRecorder) ClearProductIngredients(ctx, tx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearProductIngredients", reflect.TypeOf((*MockProductRepository)(nil).ClearProductIngredients), ctx, tx, productID)
+}
+
+// CreateModifier mocks base method.
+func (m *MockProductRepository) CreateModifier(ctx context.Context, modifier *models.ProductModifier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModifier", ctx, modifier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateModifier indicates an expected call of CreateModifier.
+func (mr *MockProductRepositoryMockRecorder) CreateModifier(ctx, modifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModifier", reflect.TypeOf((*MockProductRepository)(nil).CreateModifier), ctx, modifier)
+}
+
+// CreateProduct mocks base method.
+func (m *MockProductRepository) CreateProduct(ctx context.Context, tx repository.Transaction, product *models.Product) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", ctx, tx, product)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockProductRepositoryMockRecorder) CreateProduct(ctx, tx, product any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductRepository)(nil).CreateProduct), ctx, tx, product)
+}
+
+// CreateVariant mocks base method.
+func (m *MockProductRepository) CreateVariant(ctx context.Context, tx repository.Transaction, variant *models.ProductVariant) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVariant", ctx, tx, variant)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateVariant indicates an expected call of CreateVariant.
+func (mr *MockProductRepositoryMockRecorder) CreateVariant(ctx, tx, variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVariant", reflect.TypeOf((*MockProductRepository)(nil).CreateVariant), ctx, tx, variant)
+}
+
+// DeleteModifier mocks base method.
+func (m *MockProductRepository) DeleteModifier(ctx context.Context, productID, modifierID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModifier", ctx, productID, modifierID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModifier indicates an expected call of DeleteModifier.
+func (mr *MockProductRepositoryMockRecorder) DeleteModifier(ctx, productID, modifierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModifier", reflect.TypeOf((*MockProductRepository)(nil).DeleteModifier), ctx, productID, modifierID)
+}
+
+// DeleteVariant mocks base method.
+func (m *MockProductRepository) DeleteVariant(ctx context.Context, productID, variantID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVariant", ctx, productID, variantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVariant indicates an expected call of DeleteVariant.
+func (mr *MockProductRepositoryMockRecorder) DeleteVariant(ctx, productID, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVariant", reflect.TypeOf((*MockProductRepository)(nil).DeleteVariant), ctx, productID, variantID)
+}
+
+// GetProductById mocks base method.
+func (m *MockProductRepository) GetProductById(ctx context.Context, tx repository.Transaction, productId int) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductById", ctx, tx, productId)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductById indicates an expected call of GetProductById.
+func (mr *MockProductRepositoryMockRecorder) GetProductById(ctx, tx, productId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductById", reflect.TypeOf((*MockProductRepository)(nil).GetProductById), ctx, tx, productId)
+}
+
+// GetVariant mocks base method.
+func (m *MockProductRepository) GetVariant(ctx context.Context, tx repository.Transaction, variantID int) (*models.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVariant", ctx, tx, variantID)
+	ret0, _ := ret[0].(*models.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVariant indicates an expected call of GetVariant.
+func (mr *MockProductRepositoryMockRecorder) GetVariant(ctx, tx, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVariant", reflect.TypeOf((*MockProductRepository)(nil).GetVariant), ctx, tx, variantID)
+}
+
+// ListAllVariants mocks base method.
+func (m *MockProductRepository) ListAllVariants(ctx context.Context) ([]models.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListAllVariants", ctx)
+	ret0, _ := ret[0].([]models.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListAllVariants indicates an expected call of ListAllVariants.
+func (mr *MockProductRepositoryMockRecorder) ListAllVariants(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllVariants", reflect.TypeOf((*MockProductRepository)(nil).ListAllVariants), ctx)
+}
+
+// ListModifiers mocks base method.
+func (m *MockProductRepository) ListModifiers(ctx context.Context, tx repository.Transaction, productID int) ([]models.ProductModifier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModifiers", ctx, tx, productID)
+	ret0, _ := ret[0].([]models.ProductModifier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModifiers indicates an expected call of ListModifiers.
+func (mr *MockProductRepositoryMockRecorder) ListModifiers(ctx, tx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModifiers", reflect.TypeOf((*MockProductRepository)(nil).ListModifiers), ctx, tx, productID)
+}
+
+// ListProducts mocks base method.
+func (m *MockProductRepository) ListProducts(ctx context.Context) ([]models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProducts", ctx)
+	ret0, _ := ret[0].([]models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProducts indicates an expected call of ListProducts.
+func (mr *MockProductRepositoryMockRecorder) ListProducts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockProductRepository)(nil).ListProducts), ctx)
+}
+
+// ListVariants mocks base method.
+func (m *MockProductRepository) ListVariants(ctx context.Context, productID int) ([]models.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVariants", ctx, productID)
+	ret0, _ := ret[0].([]models.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVariants indicates an expected call of ListVariants.
+func (mr *MockProductRepositoryMockRecorder) ListVariants(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVariants", reflect.TypeOf((*MockProductRepository)(nil).ListVariants), ctx, productID)
+}
+
+// RemoveProductIngredient mocks base method.
+func (m *MockProductRepository) RemoveProductIngredient(ctx context.Context, tx repository.Transaction, productID, ingredientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveProductIngredient", ctx, tx, productID, ingredientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveProductIngredient indicates an expected call of RemoveProductIngredient.
+func (mr *MockProductRepositoryMockRecorder) RemoveProductIngredient(ctx, tx, productID, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveProductIngredient", reflect.TypeOf((*MockProductRepository)(nil).RemoveProductIngredient), ctx, tx, productID, ingredientID)
+}
+
+// SetMarkedUnavailable mocks base method.
+func (m *MockProductRepository) SetMarkedUnavailable(ctx context.Context, productID int, unavailable bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetMarkedUnavailable", ctx, productID, unavailable)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetMarkedUnavailable indicates an expected call of SetMarkedUnavailable.
+func (mr *MockProductRepositoryMockRecorder) SetMarkedUnavailable(ctx, productID, unavailable any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetMarkedUnavailable", reflect.TypeOf((*MockProductRepository)(nil).SetMarkedUnavailable), ctx, productID, unavailable)
+}
+
+// SetProductIngredient mocks base method.
+func (m *MockProductRepository) SetProductIngredient(ctx context.Context, tx repository.Transaction, productIngredient models.ProductIngredient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProductIngredient", ctx, tx, productIngredient)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetProductIngredient indicates an expected call of SetProductIngredient.
+func (mr *MockProductRepositoryMockRecorder) SetProductIngredient(ctx, tx, productIngredient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProductIngredient", reflect.TypeOf((*MockProductRepository)(nil).SetProductIngredient), ctx, tx, productIngredient)
+}
+
+// MockReceiptRepository is a mock of ReceiptRepository interface.
+type MockReceiptRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceiptRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockReceiptRepositoryMockRecorder is the mock recorder for MockReceiptRepository.
+type MockReceiptRepositoryMockRecorder struct {
+	mock *MockReceiptRepository
+}
+
+// NewMockReceiptRepository creates a new mock instance.
+func NewMockReceiptRepository(ctrl *gomock.Controller) *MockReceiptRepository {
+	mock := &MockReceiptRepository{ctrl: ctrl}
+	mock.recorder = &MockReceiptRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceiptRepository) EXPECT() *MockReceiptRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BeginTransaction mocks base method.
+func (m *MockReceiptRepository) BeginTransaction() (repository.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockReceiptRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockReceiptRepository)(nil).BeginTransaction))
+}
+
+// CreateReceipt mocks base method.
+func (m *MockReceiptRepository) CreateReceipt(ctx context.Context, tx repository.Transaction, receipt *models.StockReceipt) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReceipt", ctx, tx, receipt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReceipt indicates an expected call of CreateReceipt.
+func (mr *MockReceiptRepositoryMockRecorder) CreateReceipt(ctx, tx, receipt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReceipt", reflect.TypeOf((*MockReceiptRepository)(nil).CreateReceipt), ctx, tx, receipt)
+}
+
+// GetReceiptByID mocks base method.
+func (m *MockReceiptRepository) GetReceiptByID(ctx context.Context, receiptID int) (*models.StockReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReceiptByID", ctx, receiptID)
+	ret0, _ := ret[0].(*models.StockReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceiptByID indicates an expected call of GetReceiptByID.
+func (mr *MockReceiptRepositoryMockRecorder) GetReceiptByID(ctx, receiptID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceiptByID", reflect.TypeOf((*MockReceiptRepository)(nil).GetReceiptByID), ctx, receiptID)
+}
+
+// MockReportRepository is a mock of ReportRepository interface.
+type MockReportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportRepositoryMockRecorder
 	isgomock struct{}
 }
 
-// MockIngredientRepositoryMockRecorder is the mock recorder for MockIngredientRepository.
-type MockIngredientRepositoryMockRecorder struct {
-	mock *MockIngredientRepository
+// MockReportRepositoryMockRecorder is the mock recorder for MockReportRepository.
+type MockReportRepositoryMockRecorder struct {
+	mock *MockReportRepository
 }
 
-// NewMockIngredientRepository creates a new mock instance.
-func NewMockIngredientRepository(ctrl *gomock.Controller) *MockIngredientRepository {
-	mock := &MockIngredientRepository{ctrl: ctrl}
-	mock.recorder = &MockIngredientRepositoryMockRecorder{mock}
+// NewMockReportRepository creates a new mock instance.
+func NewMockReportRepository(ctrl *gomock.Controller) *MockReportRepository {
+	mock := &MockReportRepository{ctrl: ctrl}
+	mock.recorder = &MockReportRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockIngredientRepository) EXPECT() *MockIngredientRepositoryMockRecorder {
+func (m *MockReportRepository) EXPECT() *MockReportRepositoryMockRecorder {
 	return m.recorder
 }
 
-// CheckLowStockIngredients mocks base method.
-func (m *MockIngredientRepository) CheckLowStockIngredients(ctx context.Context) ([]models.Ingredient, error) {
+// SumUsage mocks base method.
+func (m *MockReportRepository) SumUsage(ctx context.Context, filter models.UsageReportFilter) ([]models.UsageVariance, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckLowStockIngredients", ctx)
-	ret0, _ := ret[0].([]models.Ingredient)
+	ret := m.ctrl.Call(m, "SumUsage", ctx, filter)
+	ret0, _ := ret[0].([]models.UsageVariance)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// CheckLowStockIngredients indicates an expected call of CheckLowStockIngredients.
-func (mr *MockIngredientRepositoryMockRecorder) CheckLowStockIngredients(ctx any) *gomock.Call {
+// SumUsage indicates an expected call of SumUsage.
+func (mr *MockReportRepositoryMockRecorder) SumUsage(ctx, filter any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckLowStockIngredients", reflect.TypeOf((*MockIngredientRepository)(nil).CheckLowStockIngredients), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumUsage", reflect.TypeOf((*MockReportRepository)(nil).SumUsage), ctx, filter)
 }
 
-// GetIngredientByID mocks base method.
-func (m *MockIngredientRepository) GetIngredientByID(ctx context.Context, tx repository.Transaction, ingredientID int) (*models.Ingredient, error) {
+// MockStockCountRepository is a mock of StockCountRepository interface.
+type MockStockCountRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockCountRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockStockCountRepositoryMockRecorder is the mock recorder for MockStockCountRepository.
+type MockStockCountRepositoryMockRecorder struct {
+	mock *MockStockCountRepository
+}
+
+// NewMockStockCountRepository creates a new mock instance.
+func NewMockStockCountRepository(ctrl *gomock.Controller) *MockStockCountRepository {
+	mock := &MockStockCountRepository{ctrl: ctrl}
+	mock.recorder = &MockStockCountRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockCountRepository) EXPECT() *MockStockCountRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BeginTransaction mocks base method.
+func (m *MockStockCountRepository) BeginTransaction() (repository.Transaction, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetIngredientByID", ctx, tx, ingredientID)
-	ret0, _ := ret[0].(*models.Ingredient)
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetIngredientByID indicates an expected call of GetIngredientByID.
-func (mr *MockIngredientRepositoryMockRecorder) GetIngredientByID(ctx, tx, ingredientID any) *gomock.Call {
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockStockCountRepositoryMockRecorder) BeginTransaction() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngredientByID", reflect.TypeOf((*MockIngredientRepository)(nil).GetIngredientByID), ctx, tx, ingredientID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockStockCountRepository)(nil).BeginTransaction))
 }
 
-// MarkAlertSent mocks base method.
-func (m *MockIngredientRepository) MarkAlertSent(ctx context.Context, ingredientID int) error {
+// CloseSession mocks base method.
+func (m *MockStockCountRepository) CloseSession(ctx context.Context, tx repository.Transaction, sessionID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "MarkAlertSent", ctx, ingredientID)
+	ret := m.ctrl.Call(m, "CloseSession", ctx, tx, sessionID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// MarkAlertSent indicates an expected call of MarkAlertSent.
-func (mr *MockIngredientRepositoryMockRecorder) MarkAlertSent(ctx, ingredientID any) *gomock.Call {
+// CloseSession indicates an expected call of CloseSession.
+func (mr *MockStockCountRepositoryMockRecorder) CloseSession(ctx, tx, sessionID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAlertSent", reflect.TypeOf((*MockIngredientRepository)(nil).MarkAlertSent), ctx, ingredientID)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSession", reflect.TypeOf((*MockStockCountRepository)(nil).CloseSession), ctx, tx, sessionID)
 }
 
-// UpdateStock mocks base method.
-func (m *MockIngredientRepository) UpdateStock(ctx context.Context, tx repository.Transaction, ingredientID int, newStock float64) error {
+// CreateSession mocks base method.
+func (m *MockStockCountRepository) CreateSession(ctx context.Context, session *models.StockCountSession) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "UpdateStock", ctx, tx, ingredientID, newStock)
+	ret := m.ctrl.Call(m, "CreateSession", ctx, session)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// UpdateStock indicates an expected call of UpdateStock.
-func (mr *MockIngredientRepositoryMockRecorder) UpdateStock(ctx, tx, ingredientID, newStock any) *gomock.Call {
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockStockCountRepositoryMockRecorder) CreateSession(ctx, session any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStock", reflect.TypeOf((*MockIngredientRepository)(nil).UpdateStock), ctx, tx, ingredientID, newStock)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockStockCountRepository)(nil).CreateSession), ctx, session)
 }
 
-// MockOrderRepository is a mock of OrderRepository interface.
-type MockOrderRepository struct {
+// GetSession mocks base method.
+func (m *MockStockCountRepository) GetSession(ctx context.Context, sessionID int) (*models.StockCountSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, sessionID)
+	ret0, _ := ret[0].(*models.StockCountSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStockCountRepositoryMockRecorder) GetSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStockCountRepository)(nil).GetSession), ctx, sessionID)
+}
+
+// ListCountResults mocks base method.
+func (m *MockStockCountRepository) ListCountResults(ctx context.Context, sessionID int) ([]models.StockCountVariance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCountResults", ctx, sessionID)
+	ret0, _ := ret[0].([]models.StockCountVariance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCountResults indicates an expected call of ListCountResults.
+func (mr *MockStockCountRepositoryMockRecorder) ListCountResults(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCountResults", reflect.TypeOf((*MockStockCountRepository)(nil).ListCountResults), ctx, sessionID)
+}
+
+// LockOpenSession mocks base method.
+func (m *MockStockCountRepository) LockOpenSession(ctx context.Context, tx repository.Transaction, sessionID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockOpenSession", ctx, tx, sessionID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockOpenSession indicates an expected call of LockOpenSession.
+func (mr *MockStockCountRepositoryMockRecorder) LockOpenSession(ctx, tx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockOpenSession", reflect.TypeOf((*MockStockCountRepository)(nil).LockOpenSession), ctx, tx, sessionID)
+}
+
+// SaveCount mocks base method.
+func (m *MockStockCountRepository) SaveCount(ctx context.Context, tx repository.Transaction, count models.StockCount) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCount", ctx, tx, count)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCount indicates an expected call of SaveCount.
+func (mr *MockStockCountRepositoryMockRecorder) SaveCount(ctx, tx, count any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCount", reflect.TypeOf((*MockStockCountRepository)(nil).SaveCount), ctx, tx, count)
+}
+
+// SaveCountResult mocks base method.
+func (m *MockStockCountRepository) SaveCountResult(ctx context.Context, tx repository.Transaction, sessionID int, variance models.StockCountVariance) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveCountResult", ctx, tx, sessionID, variance)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveCountResult indicates an expected call of SaveCountResult.
+func (mr *MockStockCountRepositoryMockRecorder) SaveCountResult(ctx, tx, sessionID, variance any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveCountResult", reflect.TypeOf((*MockStockCountRepository)(nil).SaveCountResult), ctx, tx, sessionID, variance)
+}
+
+// SumCounts mocks base method.
+func (m *MockStockCountRepository) SumCounts(ctx context.Context, tx repository.Transaction, sessionID int) ([]models.StockCountVariance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumCounts", ctx, tx, sessionID)
+	ret0, _ := ret[0].([]models.StockCountVariance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumCounts indicates an expected call of SumCounts.
+func (mr *MockStockCountRepositoryMockRecorder) SumCounts(ctx, tx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumCounts", reflect.TypeOf((*MockStockCountRepository)(nil).SumCounts), ctx, tx, sessionID)
+}
+
+// MockStockMovementRepository is a mock of StockMovementRepository interface.
+type MockStockMovementRepository struct {
 	ctrl     *gomock.Controller
-	recorder *MockOrderRepositoryMockRecorder
+	recorder *MockStockMovementRepositoryMockRecorder
 	isgomock struct{}
 }
 
-// MockOrderRepositoryMockRecorder is the mock recorder for MockOrderRepository.
-type MockOrderRepositoryMockRecorder struct {
-	mock *MockOrderRepository
+// MockStockMovementRepositoryMockRecorder is the mock recorder for MockStockMovementRepository.
+type MockStockMovementRepositoryMockRecorder struct {
+	mock *MockStockMovementRepository
 }
 
-// NewMockOrderRepository creates a new mock instance.
-func NewMockOrderRepository(ctrl *gomock.Controller) *MockOrderRepository {
-	mock := &MockOrderRepository{ctrl: ctrl}
-	mock.recorder = &MockOrderRepositoryMockRecorder{mock}
+// NewMockStockMovementRepository creates a new mock instance.
+func NewMockStockMovementRepository(ctrl *gomock.Controller) *MockStockMovementRepository {
+	mock := &MockStockMovementRepository{ctrl: ctrl}
+	mock.recorder = &MockStockMovementRepositoryMockRecorder{mock}
 	return mock
 }
 
 // EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockOrderRepository) EXPECT() *MockOrderRepositoryMockRecorder {
+func (m *MockStockMovementRepository) EXPECT() *MockStockMovementRepositoryMockRecorder {
+	return m.recorder
+}
+
+// ListMovements mocks base method.
+func (m *MockStockMovementRepository) ListMovements(ctx context.Context, filter models.StockMovementFilter) ([]models.StockMovement, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMovements", ctx, filter)
+	ret0, _ := ret[0].([]models.StockMovement)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMovements indicates an expected call of ListMovements.
+func (mr *MockStockMovementRepositoryMockRecorder) ListMovements(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMovements", reflect.TypeOf((*MockStockMovementRepository)(nil).ListMovements), ctx, filter)
+}
+
+// RecordMovement mocks base method.
+func (m *MockStockMovementRepository) RecordMovement(ctx context.Context, tx repository.Transaction, movement *models.StockMovement) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordMovement", ctx, tx, movement)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordMovement indicates an expected call of RecordMovement.
+func (mr *MockStockMovementRepositoryMockRecorder) RecordMovement(ctx, tx, movement any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordMovement", reflect.TypeOf((*MockStockMovementRepository)(nil).RecordMovement), ctx, tx, movement)
+}
+
+// MockSupplierRepository is a mock of SupplierRepository interface.
+type MockSupplierRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSupplierRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockSupplierRepositoryMockRecorder is the mock recorder for MockSupplierRepository.
+type MockSupplierRepositoryMockRecorder struct {
+	mock *MockSupplierRepository
+}
+
+// NewMockSupplierRepository creates a new mock instance.
+func NewMockSupplierRepository(ctrl *gomock.Controller) *MockSupplierRepository {
+	mock := &MockSupplierRepository{ctrl: ctrl}
+	mock.recorder = &MockSupplierRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSupplierRepository) EXPECT() *MockSupplierRepositoryMockRecorder {
 	return m.recorder
 }
 
 // BeginTransaction mocks base method.
-func (m *MockOrderRepository) BeginTransaction() (repository.Transaction, error) {
+func (m *MockSupplierRepository) BeginTransaction() (repository.Transaction, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "BeginTransaction")
 	ret0, _ := ret[0].(repository.Transaction)
@@ -136,77 +1420,139 @@ func (m *MockOrderRepository) BeginTransaction() (repository.Transaction, error)
 }
 
 // BeginTransaction indicates an expected call of BeginTransaction.
-func (mr *MockOrderRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+func (mr *MockSupplierRepositoryMockRecorder) BeginTransaction() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockOrderRepository)(nil).BeginTransaction))
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockSupplierRepository)(nil).BeginTransaction))
 }
 
-// CreateOrder mocks base method.
-func (m *MockOrderRepository) CreateOrder(ctx context.Context, tx repository.Transaction, order *models.Order) error {
+// CreateSupplier mocks base method.
+func (m *MockSupplierRepository) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CreateOrder", ctx, tx, order)
+	ret := m.ctrl.Call(m, "CreateSupplier", ctx, supplier)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CreateOrder indicates an expected call of CreateOrder.
-func (mr *MockOrderRepositoryMockRecorder) CreateOrder(ctx, tx, order any) *gomock.Call {
+// CreateSupplier indicates an expected call of CreateSupplier.
+func (mr *MockSupplierRepositoryMockRecorder) CreateSupplier(ctx, supplier any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderRepository)(nil).CreateOrder), ctx, tx, order)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSupplier", reflect.TypeOf((*MockSupplierRepository)(nil).CreateSupplier), ctx, supplier)
 }
 
-// GetOrderByID mocks base method.
-func (m *MockOrderRepository) GetOrderByID(ctx context.Context, orderId int) (*models.Order, error) {
+// DeleteSupplier mocks base method.
+func (m *MockSupplierRepository) DeleteSupplier(ctx context.Context, supplierID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOrderByID", ctx, orderId)
-	ret0, _ := ret[0].(*models.Order)
+	ret := m.ctrl.Call(m, "DeleteSupplier", ctx, supplierID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSupplier indicates an expected call of DeleteSupplier.
+func (mr *MockSupplierRepositoryMockRecorder) DeleteSupplier(ctx, supplierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSupplier", reflect.TypeOf((*MockSupplierRepository)(nil).DeleteSupplier), ctx, supplierID)
+}
+
+// GetSupplierByID mocks base method.
+func (m *MockSupplierRepository) GetSupplierByID(ctx context.Context, supplierID int) (*models.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupplierByID", ctx, supplierID)
+	ret0, _ := ret[0].(*models.Supplier)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetOrderByID indicates an expected call of GetOrderByID.
-func (mr *MockOrderRepositoryMockRecorder) GetOrderByID(ctx, orderId any) *gomock.Call {
+// GetSupplierByID indicates an expected call of GetSupplierByID.
+func (mr *MockSupplierRepositoryMockRecorder) GetSupplierByID(ctx, supplierID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrderByID", reflect.TypeOf((*MockOrderRepository)(nil).GetOrderByID), ctx, orderId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupplierByID", reflect.TypeOf((*MockSupplierRepository)(nil).GetSupplierByID), ctx, supplierID)
 }
 
-// MockProductRepository is a mock of ProductRepository interface.
-type MockProductRepository struct {
-	ctrl     *gomock.Controller
-	recorder *MockProductRepositoryMockRecorder
-	isgomock struct{}
+// ListIngredientSuppliers mocks base method.
+func (m *MockSupplierRepository) ListIngredientSuppliers(ctx context.Context, ingredientID int) ([]models.SupplierIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngredientSuppliers", ctx, ingredientID)
+	ret0, _ := ret[0].([]models.SupplierIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// MockProductRepositoryMockRecorder is the mock recorder for MockProductRepository.
-type MockProductRepositoryMockRecorder struct {
-	mock *MockProductRepository
+// ListIngredientSuppliers indicates an expected call of ListIngredientSuppliers.
+func (mr *MockSupplierRepositoryMockRecorder) ListIngredientSuppliers(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngredientSuppliers", reflect.TypeOf((*MockSupplierRepository)(nil).ListIngredientSuppliers), ctx, ingredientID)
 }
 
-// NewMockProductRepository creates a new mock instance.
-func NewMockProductRepository(ctrl *gomock.Controller) *MockProductRepository {
-	mock := &MockProductRepository{ctrl: ctrl}
-	mock.recorder = &MockProductRepositoryMockRecorder{mock}
-	return mock
+// ListSupplierIngredients mocks base method.
+func (m *MockSupplierRepository) ListSupplierIngredients(ctx context.Context, tx repository.Transaction, supplierID int) ([]models.SupplierIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSupplierIngredients", ctx, tx, supplierID)
+	ret0, _ := ret[0].([]models.SupplierIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use.
-func (m *MockProductRepository) EXPECT() *MockProductRepositoryMockRecorder {
-	return m.recorder
+// ListSupplierIngredients indicates an expected call of ListSupplierIngredients.
+func (mr *MockSupplierRepositoryMockRecorder) ListSupplierIngredients(ctx, tx, supplierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSupplierIngredients", reflect.TypeOf((*MockSupplierRepository)(nil).ListSupplierIngredients), ctx, tx, supplierID)
 }
 
-// GetProductById mocks base method.
-func (m *MockProductRepository) GetProductById(ctx context.Context, tx repository.Transaction, productId int) (*models.Product, error) {
+// ListSuppliers mocks base method.
+func (m *MockSupplierRepository) ListSuppliers(ctx context.Context) ([]models.Supplier, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetProductById", ctx, tx, productId)
-	ret0, _ := ret[0].(*models.Product)
+	ret := m.ctrl.Call(m, "ListSuppliers", ctx)
+	ret0, _ := ret[0].([]models.Supplier)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
-// GetProductById indicates an expected call of GetProductById.
-func (mr *MockProductRepositoryMockRecorder) GetProductById(ctx, tx, productId any) *gomock.Call {
+// ListSuppliers indicates an expected call of ListSuppliers.
+func (mr *MockSupplierRepositoryMockRecorder) ListSuppliers(ctx any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductById", reflect.TypeOf((*MockProductRepository)(nil).GetProductById), ctx, tx, productId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSuppliers", reflect.TypeOf((*MockSupplierRepository)(nil).ListSuppliers), ctx)
+}
+
+// RemoveSupplierIngredient mocks base method.
+func (m *MockSupplierRepository) RemoveSupplierIngredient(ctx context.Context, supplierID, ingredientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSupplierIngredient", ctx, supplierID, ingredientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSupplierIngredient indicates an expected call of RemoveSupplierIngredient.
+func (mr *MockSupplierRepositoryMockRecorder) RemoveSupplierIngredient(ctx, supplierID, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSupplierIngredient", reflect.TypeOf((*MockSupplierRepository)(nil).RemoveSupplierIngredient), ctx, supplierID, ingredientID)
+}
+
+// SetSupplierIngredient mocks base method.
+func (m *MockSupplierRepository) SetSupplierIngredient(ctx context.Context, tx repository.Transaction, item models.SupplierIngredient) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSupplierIngredient", ctx, tx, item)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetSupplierIngredient indicates an expected call of SetSupplierIngredient.
+func (mr *MockSupplierRepositoryMockRecorder) SetSupplierIngredient(ctx, tx, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSupplierIngredient", reflect.TypeOf((*MockSupplierRepository)(nil).SetSupplierIngredient), ctx, tx, item)
+}
+
+// UpdateSupplier mocks base method.
+func (m *MockSupplierRepository) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSupplier", ctx, supplier)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateSupplier indicates an expected call of UpdateSupplier.
+func (mr *MockSupplierRepositoryMockRecorder) UpdateSupplier(ctx, supplier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSupplier", reflect.TypeOf((*MockSupplierRepository)(nil).UpdateSupplier), ctx, supplier)
 }
 
 // MockTaskQueueRepository is a mock of TaskQueueRepository interface.
@@ -233,23 +1579,23 @@ func (m *MockTaskQueueRepository) EXPECT() *MockTaskQueueRepositoryMockRecorder
 	return m.recorder
 }
 
-// EnqueueAlertEmailTask mocks base method.
-func (m *MockTaskQueueRepository) EnqueueAlertEmailTask(ctx context.Context, payload *repository.PayloadSendAlertEmail, opts ...asynq.Option) error {
+// EnqueueTask mocks base method.
+func (m *MockTaskQueueRepository) EnqueueTask(ctx context.Context, taskType string, payload []byte, opts ...asynq.Option) error {
 	m.ctrl.T.Helper()
-	varargs := []any{ctx, payload}
+	varargs := []any{ctx, taskType, payload}
 	for _, a := range opts {
 		varargs = append(varargs, a)
 	}
-	ret := m.ctrl.Call(m, "EnqueueAlertEmailTask", varargs...)
+	ret := m.ctrl.Call(m, "EnqueueTask", varargs...)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// EnqueueAlertEmailTask indicates an expected call of EnqueueAlertEmailTask.
-func (mr *MockTaskQueueRepositoryMockRecorder) EnqueueAlertEmailTask(ctx, payload any, opts ...any) *gomock.Call {
+// EnqueueTask indicates an expected call of EnqueueTask.
+func (mr *MockTaskQueueRepositoryMockRecorder) EnqueueTask(ctx, taskType, payload any, opts ...any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	varargs := append([]any{ctx, payload}, opts...)
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueAlertEmailTask", reflect.TypeOf((*MockTaskQueueRepository)(nil).EnqueueAlertEmailTask), varargs...)
+	varargs := append([]any{ctx, taskType, payload}, opts...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnqueueTask", reflect.TypeOf((*MockTaskQueueRepository)(nil).EnqueueTask), varargs...)
 }
 
 // MockTransaction is a mock of Transaction interface.
@@ -451,3 +1797,86 @@ func (mr *MockTransactionMockRecorder) Rollback() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Rollback", reflect.TypeOf((*MockTransaction)(nil).Rollback))
 }
+
+// MockWasteRepository is a mock of WasteRepository interface.
+type MockWasteRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockWasteRepositoryMockRecorder
+	isgomock struct{}
+}
+
+// MockWasteRepositoryMockRecorder is the mock recorder for MockWasteRepository.
+type MockWasteRepositoryMockRecorder struct {
+	mock *MockWasteRepository
+}
+
+// NewMockWasteRepository creates a new mock instance.
+func NewMockWasteRepository(ctrl *gomock.Controller) *MockWasteRepository {
+	mock := &MockWasteRepository{ctrl: ctrl}
+	mock.recorder = &MockWasteRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWasteRepository) EXPECT() *MockWasteRepositoryMockRecorder {
+	return m.recorder
+}
+
+// BeginTransaction mocks base method.
+func (m *MockWasteRepository) BeginTransaction() (repository.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BeginTransaction")
+	ret0, _ := ret[0].(repository.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BeginTransaction indicates an expected call of BeginTransaction.
+func (mr *MockWasteRepositoryMockRecorder) BeginTransaction() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BeginTransaction", reflect.TypeOf((*MockWasteRepository)(nil).BeginTransaction))
+}
+
+// CreateWasteEntry mocks base method.
+func (m *MockWasteRepository) CreateWasteEntry(ctx context.Context, tx repository.Transaction, entry *models.WasteEntry) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateWasteEntry", ctx, tx, entry)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateWasteEntry indicates an expected call of CreateWasteEntry.
+func (mr *MockWasteRepositoryMockRecorder) CreateWasteEntry(ctx, tx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWasteEntry", reflect.TypeOf((*MockWasteRepository)(nil).CreateWasteEntry), ctx, tx, entry)
+}
+
+// GetWasteEntryByID mocks base method.
+func (m *MockWasteRepository) GetWasteEntryByID(ctx context.Context, entryID int) (*models.WasteEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWasteEntryByID", ctx, entryID)
+	ret0, _ := ret[0].(*models.WasteEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWasteEntryByID indicates an expected call of GetWasteEntryByID.
+func (mr *MockWasteRepositoryMockRecorder) GetWasteEntryByID(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWasteEntryByID", reflect.TypeOf((*MockWasteRepository)(nil).GetWasteEntryByID), ctx, entryID)
+}
+
+// SumWaste mocks base method.
+func (m *MockWasteRepository) SumWaste(ctx context.Context, filter models.WasteReportFilter) ([]models.WasteReasonTotal, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SumWaste", ctx, filter)
+	ret0, _ := ret[0].([]models.WasteReasonTotal)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SumWaste indicates an expected call of SumWaste.
+func (mr *MockWasteRepositoryMockRecorder) SumWaste(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SumWaste", reflect.TypeOf((*MockWasteRepository)(nil).SumWaste), ctx, filter)
+}
@@ -3,21 +3,24 @@ package repository
 import (
 	"context"
 	"database/sql"
-	"errors"
+	"encoding/json"
 	"fmt"
 	"log/slog"
+	"strings"
 	"time"
 
 	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
-
-	"github.com/jackc/pgconn"
 )
 
 type OrderRepository interface {
 	BeginTransaction() (Transaction, error)
 	CreateOrder(ctx context.Context, tx Transaction, order *models.Order) error
 	GetOrderByID(ctx context.Context, orderId int) (*models.Order, error)
+	ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error)
+	AddOrderConsumption(ctx context.Context, tx Transaction, consumption models.OrderConsumption) error
+	GetOrderConsumptions(ctx context.Context, tx Transaction, orderID int) ([]models.OrderConsumption, error)
+	CancelOrder(ctx context.Context, tx Transaction, orderID int) error
 }
 
 type orderRepository struct {
@@ -31,12 +34,7 @@ func NewOrderRepository(db *sql.DB) OrderRepository {
 var _ OrderRepository = (*orderRepository)(nil)
 
 func (r *orderRepository) BeginTransaction() (Transaction, error) {
-	tx, err := r.db.Begin()
-	if err != nil {
-		slog.Error("error begin transation", "error", err)
-		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "transaction failed")
-	}
-	return tx, nil
+	return beginTransaction(r.db)
 }
 
 func (r *orderRepository) CreateOrder(ctx context.Context, tx Transaction, order *models.Order) error {
@@ -55,21 +53,22 @@ func (r *orderRepository) CreateOrder(ctx context.Context, tx Transaction, order
 
 	// Insert order items
 	itemQuery := `
-		INSERT INTO order_items (order_id, product_id, quantity)
-		VALUES ($1, $2, $3)
+		INSERT INTO order_items (order_id, product_id, variant_id, quantity, modifiers)
+		VALUES ($1, $2, $3, $4, $5)
 	`
 
 	for _, item := range order.Items {
-		_, err := tx.ExecContext(ctx, itemQuery, orderID, item.ProductID, item.Quantity)
+		modifiers, err := encodeModifiers(item.Modifiers)
 		if err != nil {
-			// Check if the error is a PgError
-			var pgErr *pgconn.PgError
-			if errors.As(err, &pgErr) {
-				// Check for foreign key violation (SQLSTATE 23503)
-				if pgErr.Code == "23503" {
-					slog.Error("foreign key constraint violation", "detail", pgErr.Detail, "constraint", pgErr.ConstraintName)
-					return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Product with ID %d not found", item.ProductID))
+			return err
+		}
+		_, err = tx.ExecContext(ctx, itemQuery, orderID, item.ProductID, item.VariantID, item.Quantity, modifiers)
+		if err != nil {
+			if pgErrorCode(err) == pgForeignKeyViolation {
+				if pgConstraintName(err) == "order_items_variant_id_fkey" {
+					return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Variant with ID %d not found", *item.VariantID))
 				}
+				return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Product with ID %d not found", item.ProductID))
 			}
 			slog.Error("failed to insert order item", "error", err)
 			return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
@@ -83,22 +82,25 @@ func (r *orderRepository) CreateOrder(ctx context.Context, tx Transaction, order
 func (r *orderRepository) GetOrderByID(ctx context.Context, orderID int) (*models.Order, error) {
 	// Main order query
 	orderQuery := `
-		SELECT id, created_at
+		SELECT id, status, created_at, cancelled_at
 		FROM orders
 		WHERE id = $1
 	`
 
 	// Order items query
 	itemsQuery := `
-		SELECT product_id, quantity
+		SELECT product_id, variant_id, quantity, modifiers
 		FROM order_items
 		WHERE order_id = $1
+		ORDER BY id
 	`
 
 	var order models.Order
 	err := r.db.QueryRowContext(ctx, orderQuery, orderID).Scan(
 		&order.ID,
+		&order.Status,
 		&order.CreatedAt,
+		&order.CancelledAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -118,10 +120,16 @@ func (r *orderRepository) GetOrderByID(ctx context.Context, orderID int) (*model
 
 	for rows.Next() {
 		var item models.OrderItem
-		if err := rows.Scan(&item.ProductID, &item.Quantity); err != nil {
+		var variantID sql.NullInt64
+		var modifiers []byte
+		if err := rows.Scan(&item.ProductID, &variantID, &item.Quantity, &modifiers); err != nil {
 			slog.Error("failed to retrieve order item", "error", err)
 			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
 		}
+		item.VariantID = nullableID(variantID)
+		if item.Modifiers, err = decodeModifiers(modifiers); err != nil {
+			return nil, err
+		}
 		order.Items = append(order.Items, item)
 	}
 
@@ -132,3 +140,216 @@ func (r *orderRepository) GetOrderByID(ctx context.Context, orderID int) (*model
 
 	return &order, nil
 }
+
+// ListOrders returns the orders matching filter, newest first, including their items.
+func (r *orderRepository) ListOrders(ctx context.Context, filter models.OrderFilter) ([]models.Order, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Cursor > 0 {
+		addCondition("id < $%d", filter.Cursor)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+	if filter.ProductID > 0 {
+		addCondition("EXISTS (SELECT 1 FROM order_items f WHERE f.order_id = orders.id AND f.product_id = $%d)", filter.ProductID)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT o.id, o.status, o.created_at, o.cancelled_at, oi.product_id, oi.variant_id, oi.quantity, oi.modifiers
+		FROM (
+			SELECT id, status, created_at, cancelled_at
+			FROM orders
+			%s
+			ORDER BY id DESC
+			LIMIT $%d
+		) o
+		LEFT JOIN order_items oi ON oi.order_id = o.id
+		ORDER BY o.id DESC, oi.id
+	`, where, len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to list orders", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	orders := []models.Order{}
+	for rows.Next() {
+		var order models.Order
+		var productID, variantID, quantity sql.NullInt64
+		var modifiers []byte
+		if err := rows.Scan(&order.ID, &order.Status, &order.CreatedAt, &order.CancelledAt, &productID, &variantID, &quantity, &modifiers); err != nil {
+			slog.Error("failed to list orders", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+
+		// Rows are grouped by order, start a new one whenever the ID changes
+		if len(orders) == 0 || orders[len(orders)-1].ID != order.ID {
+			orders = append(orders, order)
+		}
+		if productID.Valid {
+			itemModifiers, err := decodeModifiers(modifiers)
+			if err != nil {
+				return nil, err
+			}
+			order := &orders[len(orders)-1]
+			order.Items = append(order.Items, models.OrderItem{
+				ProductID: int(productID.Int64),
+				VariantID: nullableID(variantID),
+				Quantity:  int(quantity.Int64),
+				Modifiers: itemModifiers,
+			})
+		}
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.Error("failed to list orders", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return orders, nil
+}
+
+// AddOrderConsumption records an amount of an ingredient deducted by an order,
+// accumulating amounts consumed by several items of the same order.
+func (r *orderRepository) AddOrderConsumption(ctx context.Context, tx Transaction, consumption models.OrderConsumption) error {
+	query := `
+		INSERT INTO order_consumptions (order_id, ingredient_id, amount, theoretical_amount)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (order_id, ingredient_id) DO UPDATE
+		SET amount = order_consumptions.amount + EXCLUDED.amount,
+			theoretical_amount = order_consumptions.theoretical_amount + EXCLUDED.theoretical_amount
+	`
+
+	_, err := tx.ExecContext(ctx, query, consumption.OrderID, consumption.IngredientID, consumption.Amount, consumption.Theoretical)
+	if err != nil {
+		slog.Error("failed to record order consumption", "orderID", consumption.OrderID, "ingredientID", consumption.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// GetOrderConsumptions returns the ingredient amounts deducted when the order was placed.
+func (r *orderRepository) GetOrderConsumptions(ctx context.Context, tx Transaction, orderID int) ([]models.OrderConsumption, error) {
+	query := `
+		SELECT order_id, ingredient_id, amount, theoretical_amount
+		FROM order_consumptions
+		WHERE order_id = $1
+		ORDER BY ingredient_id
+	`
+
+	rows, err := tx.QueryContext(ctx, query, orderID)
+	if err != nil {
+		slog.Error("failed to retrieve order consumptions", "orderID", orderID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	var consumptions []models.OrderConsumption
+	for rows.Next() {
+		var consumption models.OrderConsumption
+		if err := rows.Scan(&consumption.OrderID, &consumption.IngredientID, &consumption.Amount, &consumption.Theoretical); err != nil {
+			slog.Error("failed to retrieve order consumptions", "orderID", orderID, "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		consumptions = append(consumptions, consumption)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.Error("failed to retrieve order consumptions", "orderID", orderID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return consumptions, nil
+}
+
+// CancelOrder marks a placed order as cancelled. The update locks the order row,
+// so concurrent cancellations of the same order cannot both succeed.
+func (r *orderRepository) CancelOrder(ctx context.Context, tx Transaction, orderID int) error {
+	query := `
+		UPDATE orders
+		SET status = $1, cancelled_at = NOW()
+		WHERE id = $2 AND status = $3
+	`
+
+	result, err := tx.ExecContext(ctx, query, models.OrderStatusCancelled, orderID, models.OrderStatusPlaced)
+	if err != nil {
+		slog.Error("failed to cancel order", "orderID", orderID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to cancel order", "orderID", orderID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	// Nothing was updated, either the order does not exist or it is not placed anymore
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1`, orderID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Order with ID %d not found", orderID))
+		}
+		slog.Error("failed to retrieve order status", "orderID", orderID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Order cannot be cancelled", fmt.Sprintf("Order with ID %d is already %s", orderID, status))
+}
+
+// nullableID converts a nullable ID column, nil when it is NULL.
+func nullableID(id sql.NullInt64) *int {
+	if !id.Valid {
+		return nil
+	}
+	value := int(id.Int64)
+	return &value
+}
+
+// encodeModifiers encodes the modifier IDs of an order item for its JSONB column.
+func encodeModifiers(modifiers []int) ([]byte, error) {
+	if modifiers == nil {
+		modifiers = []int{}
+	}
+	encoded, err := json.Marshal(modifiers)
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to encode order item modifiers")
+	}
+	return encoded, nil
+}
+
+// decodeModifiers decodes the modifier IDs of an order item, nil when it has none.
+func decodeModifiers(encoded []byte) ([]int, error) {
+	var modifiers []int
+	if err := json.Unmarshal(encoded, &modifiers); err != nil {
+		slog.Error("failed to decode order item modifiers", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	if len(modifiers) == 0 {
+		return nil, nil
+	}
+	return modifiers, nil
+}
@@ -2,11 +2,13 @@ package repository
 
 import (
 	"context"
+	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
 	"testing"
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -22,10 +24,11 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 	repo := NewOrderRepository(db)
 
 	// Create an order to insert
+	variantID := 4
 	order := &models.Order{
 		Items: []models.OrderItem{
-			{ProductID: 1, Quantity: 2},
-			{ProductID: 2, Quantity: 1},
+			{ProductID: 1, Quantity: 2, Modifiers: []int{3, 5}},
+			{ProductID: 2, VariantID: &variantID, Quantity: 1},
 		},
 	}
 
@@ -38,12 +41,12 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1)) // Mock return of order ID = 1
 
 	// Mock the insertion of order items
-	mock.ExpectExec(`^INSERT INTO order_items \(order_id, product_id, quantity\) VALUES \(\$1, \$2, \$3\)$`).
-		WithArgs(1, 1, 2).
+	mock.ExpectExec(`^INSERT INTO order_items \(order_id, product_id, variant_id, quantity, modifiers\) VALUES \(\$1, \$2, \$3, \$4, \$5\)$`).
+		WithArgs(1, 1, nil, 2, []byte(`[3,5]`)).
 		WillReturnResult(sqlmock.NewResult(1, 1)) // Mock success for this insert
 
-	mock.ExpectExec(`^INSERT INTO order_items \(order_id, product_id, quantity\) VALUES \(\$1, \$2, \$3\)$`).
-		WithArgs(1, 2, 1).
+	mock.ExpectExec(`^INSERT INTO order_items \(order_id, product_id, variant_id, quantity, modifiers\) VALUES \(\$1, \$2, \$3, \$4, \$5\)$`).
+		WithArgs(1, 2, 4, 1, []byte(`[]`)).
 		WillReturnResult(sqlmock.NewResult(1, 1)) // Mock success for this insert
 
 	// Expect the transaction to commit at the end
@@ -74,6 +77,62 @@ func TestOrderRepository_CreateOrder(t *testing.T) {
 	}
 }
 
+func TestOrderRepository_CreateOrder_VariantNotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewOrderRepository(db)
+
+	variantID := 99
+	order := &models.Order{
+		Items: []models.OrderItem{{ProductID: 2, VariantID: &variantID, Quantity: 1}},
+	}
+
+	mock.ExpectBegin()
+
+	// Mock the order insert
+	mock.ExpectQuery(`INSERT INTO orders \(created_at\) VALUES \(\$1\) RETURNING id`).
+		WithArgs(sqlmock.AnyArg()).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+	// Mock the order item insert failing on the unknown variant
+	mock.ExpectExec(`INSERT INTO order_items`).
+		WithArgs(1, 2, 99, 1, []byte(`[]`)).
+		WillReturnError(&pgconn.PgError{Code: pgForeignKeyViolation, ConstraintName: "order_items_variant_id_fkey"})
+
+	mock.ExpectRollback()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.CreateOrder(context.Background(), tx, order)
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+		assert.Equal(t, "Variant with ID 99 not found", appErr.Details)
+	}
+
+	err = tx.Rollback()
+	if err != nil {
+		t.Fatalf("Failed to rollback transaction: %v", err)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
 func TestOrderRepository_GetOrderByID(t *testing.T) {
 	// Create a mock DB and mock objects
 	db, mock, err := sqlmock.New()
@@ -89,26 +148,28 @@ func TestOrderRepository_GetOrderByID(t *testing.T) {
 	orderID := 1
 
 	// Expected order and order items
+	variantID := 4
 	expectedOrder := &models.Order{
 		ID:        orderID,
+		Status:    models.OrderStatusPlaced,
 		CreatedAt: time.Now(),
 		Items: []models.OrderItem{
-			{ProductID: 1, Quantity: 2},
-			{ProductID: 2, Quantity: 1},
+			{ProductID: 1, Quantity: 2, Modifiers: []int{3}},
+			{ProductID: 2, VariantID: &variantID, Quantity: 1},
 		},
 	}
 
 	// Mock the query for the order
-	mock.ExpectQuery(`SELECT id, created_at FROM orders WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, status, created_at, cancelled_at FROM orders WHERE id = \$1`).
 		WithArgs(orderID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(orderID, expectedOrder.CreatedAt))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "cancelled_at"}).AddRow(orderID, models.OrderStatusPlaced, expectedOrder.CreatedAt, nil))
 
 	// Mock the query for order items
-	mock.ExpectQuery(`SELECT product_id, quantity FROM order_items WHERE order_id = \$1`).
+	mock.ExpectQuery(`SELECT product_id, variant_id, quantity, modifiers FROM order_items WHERE order_id = \$1 ORDER BY id`).
 		WithArgs(orderID).
-		WillReturnRows(sqlmock.NewRows([]string{"product_id", "quantity"}).
-			AddRow(1, 2).
-			AddRow(2, 1))
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "variant_id", "quantity", "modifiers"}).
+			AddRow(1, nil, 2, []byte(`[3]`)).
+			AddRow(2, 4, 1, []byte(`[]`)))
 
 	// Call the method under test
 	order, err := repo.GetOrderByID(context.Background(), orderID)
@@ -134,9 +195,9 @@ func TestOrderRepository_GetOrderByID_NotFound(t *testing.T) {
 	orderID := 999
 
 	// Mock the query for the order
-	mock.ExpectQuery(`SELECT id, created_at FROM orders WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, status, created_at, cancelled_at FROM orders WHERE id = \$1`).
 		WithArgs(orderID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "cancelled_at"}))
 
 	// Call the method under test
 	order, err := repo.GetOrderByID(context.Background(), orderID)
@@ -145,3 +206,165 @@ func TestOrderRepository_GetOrderByID_NotFound(t *testing.T) {
 	assert.Error(t, err, "Expected error when order is not found")
 	assert.Nil(t, order, "Expected no order to be returned")
 }
+
+func TestOrderRepository_ListOrders(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewOrderRepository(db)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := from.Add(time.Hour)
+
+	// Expected orders, newest first
+	expectedOrders := []models.Order{
+		{ID: 9, Status: models.OrderStatusPlaced, CreatedAt: createdAt, Items: []models.OrderItem{{ProductID: 1, Quantity: 2}, {ProductID: 2, Quantity: 1}}},
+		{ID: 7, Status: models.OrderStatusCancelled, CreatedAt: createdAt, CancelledAt: &createdAt, Items: []models.OrderItem{{ProductID: 1, Quantity: 1, Modifiers: []int{4}}}},
+	}
+
+	// Mock the filtered listing query
+	mock.ExpectQuery(`SELECT o.id, o.status, o.created_at, o.cancelled_at, oi.product_id, oi.variant_id, oi.quantity, oi.modifiers FROM \( SELECT id, status, created_at, cancelled_at FROM orders WHERE id < \$1 AND created_at >= \$2 AND EXISTS \(.+product_id = \$3\) ORDER BY id DESC LIMIT \$4 \) o`).
+		WithArgs(10, from, 1, 3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "status", "created_at", "cancelled_at", "product_id", "variant_id", "quantity", "modifiers"}).
+			AddRow(9, models.OrderStatusPlaced, createdAt, nil, 1, nil, 2, []byte(`[]`)).
+			AddRow(9, models.OrderStatusPlaced, createdAt, nil, 2, nil, 1, []byte(`[]`)).
+			AddRow(7, models.OrderStatusCancelled, createdAt, createdAt, 1, nil, 1, []byte(`[4]`)))
+
+	// Call the method under test
+	orders, err := repo.ListOrders(context.Background(), models.OrderFilter{
+		From:      &from,
+		ProductID: 1,
+		Cursor:    10,
+		Limit:     3,
+	})
+
+	// Assertions
+	assert.NoError(t, err, "Expected no error, got %v", err)
+	assert.Equal(t, expectedOrders, orders, "Expected and actual orders do not match")
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestOrderRepository_CancelOrder(t *testing.T) {
+	testCases := []struct {
+		name        string
+		buildStubs  func(mock sqlmock.Sqlmock)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "Cancelled",
+			buildStubs: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE orders SET status = \$1, cancelled_at = NOW\(\) WHERE id = \$2 AND status = \$3`).
+					WithArgs(models.OrderStatusCancelled, 1, models.OrderStatusPlaced).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+			checkResult: func(t *testing.T, err error) {
+				assert.NoError(t, err)
+			},
+		},
+		{
+			name: "Already Cancelled",
+			buildStubs: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE orders SET status`).
+					WithArgs(models.OrderStatusCancelled, 1, models.OrderStatusPlaced).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT status FROM orders WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.OrderStatusCancelled))
+			},
+			checkResult: func(t *testing.T, err error) {
+				var appErr *internalErrors.AppError
+				assert.ErrorAs(t, err, &appErr)
+				assert.Equal(t, internalErrors.ErrCodeConflict, appErr.Code)
+			},
+		},
+		{
+			name: "Not Found",
+			buildStubs: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`UPDATE orders SET status`).
+					WithArgs(models.OrderStatusCancelled, 1, models.OrderStatusPlaced).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectQuery(`SELECT status FROM orders WHERE id = \$1`).
+					WithArgs(1).
+					WillReturnRows(sqlmock.NewRows([]string{"status"}))
+			},
+			checkResult: func(t *testing.T, err error) {
+				var appErr *internalErrors.AppError
+				assert.ErrorAs(t, err, &appErr)
+				assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// Create a mock DB and mock objects
+			db, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("Failed to open mock database: %v", err)
+			}
+			defer db.Close()
+
+			repo := NewOrderRepository(db)
+
+			mock.ExpectBegin()
+			tc.buildStubs(mock)
+
+			tx, err := repo.BeginTransaction()
+			if err != nil {
+				t.Fatalf("Failed to begin transaction: %v", err)
+			}
+
+			// Call the method under test
+			err = repo.CancelOrder(context.Background(), tx, 1)
+			tc.checkResult(t, err)
+
+			// Ensure that the mock expectations were met
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("There were unfulfilled expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestOrderRepository_AddOrderConsumption(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	repo := NewOrderRepository(db)
+
+	mock.ExpectBegin()
+
+	// Mock the accumulating insert
+	mock.ExpectExec(`INSERT INTO order_consumptions \(order_id, ingredient_id, amount, theoretical_amount\) VALUES \(\$1, \$2, \$3, \$4\) ON CONFLICT \(order_id, ingredient_id\) DO UPDATE SET amount = order_consumptions.amount \+ EXCLUDED.amount, theoretical_amount = order_consumptions.theoretical_amount \+ EXCLUDED.theoretical_amount`).
+		WithArgs(1, 2, 60.0, 54.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.AddOrderConsumption(context.Background(), tx, models.OrderConsumption{OrderID: 1, IngredientID: 2, Amount: 60, Theoretical: 54})
+
+	// Assertions
+	assert.NoError(t, err)
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,118 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type OutboxRepository interface {
+	BeginTransaction() (Transaction, error)
+	AddMessage(ctx context.Context, tx Transaction, message *models.OutboxMessage) error
+	ListPendingMessages(ctx context.Context, tx Transaction, limit int) ([]models.OutboxMessage, error)
+	MarkDispatched(ctx context.Context, tx Transaction, messageID int) error
+	RecordFailure(ctx context.Context, tx Transaction, messageID int, reason string) error
+}
+
+type outboxRepository struct {
+	db *sql.DB
+}
+
+func NewOutboxRepository(db *sql.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+var _ OutboxRepository = (*outboxRepository)(nil)
+
+func (r *outboxRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
+// AddMessage stores a task in the outbox as part of tx, setting its generated ID.
+func (r *outboxRepository) AddMessage(ctx context.Context, tx Transaction, message *models.OutboxMessage) error {
+	query := `
+		INSERT INTO outbox (task_type, payload)
+		VALUES ($1, $2)
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRowContext(ctx, query, message.TaskType, message.Payload).Scan(&message.ID, &message.CreatedAt)
+	if err != nil {
+		slog.Error("failed to add outbox message", "taskType", message.TaskType, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// ListPendingMessages locks up to limit undispatched messages, oldest first.
+// Messages locked by another relay are skipped so several instances can run side by side.
+func (r *outboxRepository) ListPendingMessages(ctx context.Context, tx Transaction, limit int) ([]models.OutboxMessage, error) {
+	query := `
+		SELECT id, task_type, payload, attempts, created_at
+		FROM outbox
+		WHERE dispatched_at IS NULL
+		ORDER BY id
+		LIMIT $1
+		FOR UPDATE SKIP LOCKED
+	`
+
+	rows, err := tx.QueryContext(ctx, query, limit)
+	if err != nil {
+		slog.Error("failed to list pending outbox messages", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	messages := []models.OutboxMessage{}
+	for rows.Next() {
+		var message models.OutboxMessage
+		if err := rows.Scan(&message.ID, &message.TaskType, &message.Payload, &message.Attempts, &message.CreatedAt); err != nil {
+			slog.Error("failed to list pending outbox messages", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		messages = append(messages, message)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to list pending outbox messages", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return messages, nil
+}
+
+// MarkDispatched flags a message as published to the task queue.
+func (r *outboxRepository) MarkDispatched(ctx context.Context, tx Transaction, messageID int) error {
+	query := `
+		UPDATE outbox
+		SET dispatched_at = CURRENT_TIMESTAMP, attempts = attempts + 1, last_error = NULL
+		WHERE id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, query, messageID); err != nil {
+		slog.Error("failed to mark outbox message dispatched", "messageID", messageID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// RecordFailure keeps a message pending and remembers why publishing it failed.
+func (r *outboxRepository) RecordFailure(ctx context.Context, tx Transaction, messageID int, reason string) error {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $1
+		WHERE id = $2
+	`
+
+	if _, err := tx.ExecContext(ctx, query, reason, messageID); err != nil {
+		slog.Error("failed to record outbox failure", "messageID", messageID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
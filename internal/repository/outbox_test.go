@@ -0,0 +1,112 @@
+package repository
+
+import (
+	"context"
+	"stockk/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRepository_AddMessage(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewOutboxRepository(db)
+
+	createdAt := time.Now()
+	message := &models.OutboxMessage{TaskType: TaskSendAlertEmail, Payload: []byte(`{"ingredients":[]}`)}
+
+	mock.ExpectBegin()
+
+	// Mock the outbox insert
+	mock.ExpectQuery(`INSERT INTO outbox \(task_type, payload\) VALUES \(\$1, \$2\) RETURNING id, created_at`).
+		WithArgs(TaskSendAlertEmail, message.Payload).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, createdAt))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.AddMessage(context.Background(), tx, message)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 5, message.ID)
+	assert.Equal(t, createdAt, message.CreatedAt)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestOutboxRepository_ListPendingMessages(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewOutboxRepository(db)
+
+	createdAt := time.Now()
+
+	mock.ExpectBegin()
+
+	// Mock locking the pending messages
+	mock.ExpectQuery(`SELECT id, task_type, payload, attempts, created_at FROM outbox WHERE dispatched_at IS NULL ORDER BY id LIMIT \$1 FOR UPDATE SKIP LOCKED`).
+		WithArgs(100).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "task_type", "payload", "attempts", "created_at"}).
+			AddRow(5, TaskSendAlertEmail, []byte(`{}`), 2, createdAt))
+
+	// Mock marking it dispatched
+	mock.ExpectExec(`UPDATE outbox SET dispatched_at = CURRENT_TIMESTAMP, attempts = attempts \+ 1, last_error = NULL WHERE id = \$1`).
+		WithArgs(5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the methods under test
+	messages, err := repo.ListPendingMessages(context.Background(), tx, 100)
+	assert.NoError(t, err)
+	assert.Equal(t, []models.OutboxMessage{
+		{ID: 5, TaskType: TaskSendAlertEmail, Payload: []byte(`{}`), Attempts: 2, CreatedAt: createdAt},
+	}, messages)
+
+	err = repo.MarkDispatched(context.Background(), tx, 5)
+	assert.NoError(t, err)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
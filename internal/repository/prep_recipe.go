@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type PrepRecipeRepository interface {
+	BeginTransaction() (Transaction, error)
+	LockPrepRecipes(ctx context.Context, tx Transaction) error
+	ListPrepRecipes(ctx context.Context, tx Transaction) ([]models.PrepRecipe, error)
+	GetPrepRecipe(ctx context.Context, ingredientID int) (*models.PrepRecipe, error)
+	SavePrepRecipe(ctx context.Context, tx Transaction, recipe *models.PrepRecipe) error
+	DeletePrepRecipe(ctx context.Context, ingredientID int) error
+}
+
+type prepRecipeRepository struct {
+	db *sql.DB
+}
+
+func NewPrepRecipeRepository(db *sql.DB) PrepRecipeRepository {
+	return &prepRecipeRepository{db: db}
+}
+
+var _ PrepRecipeRepository = (*prepRecipeRepository)(nil)
+
+func (r *prepRecipeRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
+// prepRecipesQuery selects the prep recipes with their ingredients, a recipe
+// without ingredients comes with a single row of NULL ingredient columns.
+const prepRecipesQuery = `
+	SELECT pr.ingredient_id, pr.yield, pi.ingredient_id, i.name, pi.amount, pi.unit, i.unit, pi.yield_percent, i.yield_percent
+	FROM prep_recipes pr
+	LEFT JOIN prep_ingredients pi ON pi.prep_id = pr.ingredient_id
+	LEFT JOIN ingredients i ON i.id = pi.ingredient_id
+`
+
+// LockPrepRecipes serializes changes to prep recipes until tx ends, so two
+// recipes saved at the same time cannot form a cycle unnoticed. Readers are
+// not blocked.
+func (r *prepRecipeRepository) LockPrepRecipes(ctx context.Context, tx Transaction) error {
+	if _, err := tx.ExecContext(ctx, `LOCK TABLE prep_recipes IN SHARE ROW EXCLUSIVE MODE`); err != nil {
+		slog.Error("failed to lock prep recipes", "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	return nil
+}
+
+// ListPrepRecipes returns every prep recipe, within tx when given.
+func (r *prepRecipeRepository) ListPrepRecipes(ctx context.Context, tx Transaction) ([]models.PrepRecipe, error) {
+	query := prepRecipesQuery + ` ORDER BY pr.ingredient_id, pi.ingredient_id`
+
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, query)
+	} else {
+		rows, err = r.db.QueryContext(ctx, query)
+	}
+	if err != nil {
+		slog.Error("failed to list prep recipes", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	recipes, err := scanPrepRecipes(rows)
+	if err != nil {
+		slog.Error("failed to list prep recipes", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return recipes, nil
+}
+
+// GetPrepRecipe fetches the recipe of a prep item.
+func (r *prepRecipeRepository) GetPrepRecipe(ctx context.Context, ingredientID int) (*models.PrepRecipe, error) {
+	query := prepRecipesQuery + ` WHERE pr.ingredient_id = $1 ORDER BY pi.ingredient_id`
+
+	rows, err := r.db.QueryContext(ctx, query, ingredientID)
+	if err != nil {
+		slog.Error("failed to retrieve prep recipe", "ingredientID", ingredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	recipes, err := scanPrepRecipes(rows)
+	if err != nil {
+		slog.Error("failed to retrieve prep recipe", "ingredientID", ingredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if len(recipes) == 0 {
+		return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d has no prep recipe", ingredientID))
+	}
+
+	return &recipes[0], nil
+}
+
+// scanPrepRecipes groups the rows of prepRecipesQuery by recipe.
+func scanPrepRecipes(rows *sql.Rows) ([]models.PrepRecipe, error) {
+	recipes := []models.PrepRecipe{}
+	for rows.Next() {
+		var recipe models.PrepRecipe
+		var ingredientID sql.NullInt64
+		var name, unit, ingredientUnit sql.NullString
+		var amount sql.NullFloat64
+		var yieldPercent, ingredientYieldPercent *float64
+		if err := rows.Scan(&recipe.IngredientID, &recipe.Yield, &ingredientID, &name, &amount, &unit, &ingredientUnit, &yieldPercent, &ingredientYieldPercent); err != nil {
+			return nil, err
+		}
+
+		if len(recipes) == 0 || recipes[len(recipes)-1].IngredientID != recipe.IngredientID {
+			recipe.Ingredients = []models.PrepIngredient{}
+			recipes = append(recipes, recipe)
+		}
+		if ingredientID.Valid {
+			last := &recipes[len(recipes)-1]
+			last.Ingredients = append(last.Ingredients, models.PrepIngredient{
+				PrepID:                 recipe.IngredientID,
+				IngredientID:           int(ingredientID.Int64),
+				IngredientName:         name.String,
+				Amount:                 amount.Float64,
+				Unit:                   unit.String,
+				IngredientUnit:         ingredientUnit.String,
+				YieldPercent:           yieldPercent,
+				IngredientYieldPercent: ingredientYieldPercent,
+			})
+		}
+	}
+
+	return recipes, rows.Err()
+}
+
+// SavePrepRecipe creates the recipe of a prep item or replaces its yield and ingredients.
+func (r *prepRecipeRepository) SavePrepRecipe(ctx context.Context, tx Transaction, recipe *models.PrepRecipe) error {
+	query := `
+		INSERT INTO prep_recipes (ingredient_id, yield)
+		VALUES ($1, $2)
+		ON CONFLICT (ingredient_id) DO UPDATE SET yield = EXCLUDED.yield
+	`
+
+	if _, err := tx.ExecContext(ctx, query, recipe.IngredientID, recipe.Yield); err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", recipe.IngredientID))
+		}
+		slog.Error("failed to save prep recipe", "ingredientID", recipe.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM prep_ingredients WHERE prep_id = $1`, recipe.IngredientID); err != nil {
+		slog.Error("failed to save prep recipe", "ingredientID", recipe.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	lineQuery := `
+		INSERT INTO prep_ingredients (prep_id, ingredient_id, amount, unit, yield_percent)
+		VALUES ($1, $2, $3, $4, $5)
+	`
+	for _, line := range recipe.Ingredients {
+		if _, err := tx.ExecContext(ctx, lineQuery, recipe.IngredientID, line.IngredientID, line.Amount, line.Unit, line.YieldPercent); err != nil {
+			switch pgErrorCode(err) {
+			case pgForeignKeyViolation:
+				return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", line.IngredientID))
+			case pgUniqueViolation:
+				return internalErrors.NewAppError(internalErrors.ErrCodeValidation, "Invalid prep recipe", fmt.Sprintf("Ingredient %d is listed more than once", line.IngredientID))
+			}
+			slog.Error("failed to save prep ingredient", "ingredientID", recipe.IngredientID, "lineIngredientID", line.IngredientID, "error", err)
+			return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+	}
+
+	return nil
+}
+
+// DeletePrepRecipe turns a prep item back into a plain ingredient, its prepared stock is kept.
+func (r *prepRecipeRepository) DeletePrepRecipe(ctx context.Context, ingredientID int) error {
+	result, err := r.db.ExecContext(ctx, `DELETE FROM prep_recipes WHERE ingredient_id = $1`, ingredientID)
+	if err != nil {
+		slog.Error("failed to delete prep recipe", "ingredientID", ingredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to delete prep recipe", "ingredientID", ingredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d has no prep recipe", ingredientID))
+	}
+
+	return nil
+}
@@ -0,0 +1,125 @@
+package repository
+
+import (
+	"context"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrepRecipeRepository_ListPrepRecipes(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewPrepRecipeRepository(db)
+
+	// Mock the recipes query, the second recipe has no ingredients yet
+	mock.ExpectQuery(`SELECT pr.ingredient_id, pr.yield, pi.ingredient_id, i.name, pi.amount, pi.unit, i.unit, pi.yield_percent, i.yield_percent FROM prep_recipes pr LEFT JOIN prep_ingredients pi .+ ORDER BY pr.ingredient_id, pi.ingredient_id`).
+		WillReturnRows(sqlmock.NewRows([]string{"prep_id", "yield", "ingredient_id", "name", "amount", "unit", "ingredient_unit", "yield_percent", "ingredient_yield_percent"}).
+			AddRow(4, 500.0, 1, "Tomato", 0.4, "kg", models.UnitGram, nil, nil).
+			AddRow(4, 500.0, 2, "Onion", 100.0, models.UnitGram, models.UnitGram, nil, 90.0).
+			AddRow(6, 10.0, nil, nil, nil, nil, nil, nil, nil))
+
+	// Call the method under test
+	recipes, err := repo.ListPrepRecipes(context.Background(), nil)
+
+	// Assertions
+	onionYield := 90.0
+	assert.NoError(t, err)
+	assert.Equal(t, []models.PrepRecipe{
+		{IngredientID: 4, Yield: 500, Ingredients: []models.PrepIngredient{
+			{PrepID: 4, IngredientID: 1, IngredientName: "Tomato", Amount: 0.4, Unit: "kg", IngredientUnit: models.UnitGram},
+			{PrepID: 4, IngredientID: 2, IngredientName: "Onion", Amount: 100, Unit: models.UnitGram, IngredientUnit: models.UnitGram, IngredientYieldPercent: &onionYield},
+		}},
+		{IngredientID: 6, Yield: 10, Ingredients: []models.PrepIngredient{}},
+	}, recipes)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPrepRecipeRepository_SavePrepRecipe(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewPrepRecipeRepository(db)
+
+	mock.ExpectBegin()
+
+	// Mock the recipe upsert, the replacement of its lines and an unknown line ingredient
+	mock.ExpectExec(`INSERT INTO prep_recipes \(ingredient_id, yield\) VALUES \(\$1, \$2\) ON CONFLICT \(ingredient_id\) DO UPDATE SET yield = EXCLUDED.yield`).
+		WithArgs(4, 500.0).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`DELETE FROM prep_ingredients WHERE prep_id = \$1`).
+		WithArgs(4).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectExec(`INSERT INTO prep_ingredients \(prep_id, ingredient_id, amount, unit, yield_percent\) VALUES \(\$1, \$2, \$3, \$4, \$5\)`).
+		WithArgs(4, 1, 0.4, "kg", nil).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`INSERT INTO prep_ingredients`).
+		WithArgs(4, 99, 100.0, models.UnitGram, nil).
+		WillReturnError(&pgconn.PgError{Code: pgForeignKeyViolation})
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.SavePrepRecipe(context.Background(), tx, &models.PrepRecipe{IngredientID: 4, Yield: 500, Ingredients: []models.PrepIngredient{
+		{IngredientID: 1, Amount: 0.4, Unit: "kg"},
+		{IngredientID: 99, Amount: 100, Unit: models.UnitGram},
+	}})
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestPrepRecipeRepository_GetPrepRecipe_NotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewPrepRecipeRepository(db)
+
+	// Mock the recipe query returning no rows
+	mock.ExpectQuery(`SELECT pr.ingredient_id, .+ WHERE pr.ingredient_id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"prep_id", "yield", "ingredient_id", "name", "amount", "unit", "ingredient_unit"}))
+
+	// Call the method under test
+	recipe, err := repo.GetPrepRecipe(context.Background(), 3)
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+	assert.Nil(t, recipe)
+}
@@ -11,7 +11,22 @@ import (
 )
 
 type ProductRepository interface {
+	BeginTransaction() (Transaction, error)
 	GetProductById(ctx context.Context, tx Transaction, productId int) (*models.Product, error)
+	ListProducts(ctx context.Context) ([]models.Product, error)
+	CreateProduct(ctx context.Context, tx Transaction, product *models.Product) error
+	SetProductIngredient(ctx context.Context, tx Transaction, productIngredient models.ProductIngredient) error
+	RemoveProductIngredient(ctx context.Context, tx Transaction, productID int, ingredientID int) error
+	ClearProductIngredients(ctx context.Context, tx Transaction, productID int) error
+	SetMarkedUnavailable(ctx context.Context, productID int, unavailable bool) error
+	CreateModifier(ctx context.Context, modifier *models.ProductModifier) error
+	ListModifiers(ctx context.Context, tx Transaction, productID int) ([]models.ProductModifier, error)
+	DeleteModifier(ctx context.Context, productID int, modifierID int) error
+	CreateVariant(ctx context.Context, tx Transaction, variant *models.ProductVariant) error
+	GetVariant(ctx context.Context, tx Transaction, variantID int) (*models.ProductVariant, error)
+	ListVariants(ctx context.Context, productID int) ([]models.ProductVariant, error)
+	ListAllVariants(ctx context.Context) ([]models.ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID int, variantID int) error
 }
 
 type productRepository struct {
@@ -24,21 +39,27 @@ func NewProductRepository(db *sql.DB) ProductRepository {
 
 var _ ProductRepository = (*productRepository)(nil)
 
+func (r *productRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
 // GetProductById fetches a product by its ID, including its ingredients and amounts
 func (r *productRepository) GetProductById(ctx context.Context, tx Transaction, productID int) (*models.Product, error) {
 	// Fetch the basic product details
-	productQuery := `SELECT id, name FROM products WHERE id = $1`
+	productQuery := `SELECT id, name, marked_unavailable FROM products WHERE id = $1`
 	var product models.Product
 	var err error
 	if tx != nil {
 		err = tx.QueryRowContext(ctx, productQuery, productID).Scan(
 			&product.ID,
 			&product.Name,
+			&product.MarkedUnavailable,
 		)
 	} else {
 		err = r.db.QueryRowContext(ctx, productQuery, productID).Scan(
 			&product.ID,
 			&product.Name,
+			&product.MarkedUnavailable,
 		)
 	}
 
@@ -52,10 +73,11 @@ func (r *productRepository) GetProductById(ctx context.Context, tx Transaction,
 
 	// Fetch the ingredients for the product
 	ingredientsQuery := `
-		SELECT pi.product_id, pi.ingredient_id, pi.amount
+		SELECT pi.product_id, pi.ingredient_id, i.name, pi.amount, pi.unit, i.unit, pi.yield_percent, i.yield_percent
 		FROM product_ingredients pi
 		JOIN ingredients i ON pi.ingredient_id = i.id
 		WHERE pi.product_id = $1
+		ORDER BY pi.ingredient_id
 	`
 	var rows *sql.Rows
 	if tx != nil {
@@ -74,7 +96,7 @@ func (r *productRepository) GetProductById(ctx context.Context, tx Transaction,
 	// Populate the ingredients slice
 	for rows.Next() {
 		var productIngredient models.ProductIngredient
-		if err := rows.Scan(&productIngredient.ProductID, &productIngredient.IngredientID, &productIngredient.Amount); err != nil {
+		if err := rows.Scan(&productIngredient.ProductID, &productIngredient.IngredientID, &productIngredient.IngredientName, &productIngredient.Amount, &productIngredient.Unit, &productIngredient.IngredientUnit, &productIngredient.YieldPercent, &productIngredient.IngredientYieldPercent); err != nil {
 			slog.Error("failed to retrieve order ingredients", "error", err)
 			return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
 		}
@@ -88,3 +110,458 @@ func (r *productRepository) GetProductById(ctx context.Context, tx Transaction,
 
 	return &product, nil
 }
+
+// ListProducts fetches every product together with its recipe.
+func (r *productRepository) ListProducts(ctx context.Context) ([]models.Product, error) {
+	productsQuery := `SELECT id, name, marked_unavailable FROM products ORDER BY id`
+
+	rows, err := r.db.QueryContext(ctx, productsQuery)
+	if err != nil {
+		slog.Error("failed to list products", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	products := []models.Product{}
+	productIndex := make(map[int]int)
+	for rows.Next() {
+		var product models.Product
+		if err := rows.Scan(&product.ID, &product.Name, &product.MarkedUnavailable); err != nil {
+			slog.Error("failed to list products", "error", err)
+			return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+		}
+		productIndex[product.ID] = len(products)
+		products = append(products, product)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to list products", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	// Fetch the recipes of all products in a single query
+	ingredientsQuery := `
+		SELECT pi.product_id, pi.ingredient_id, i.name, pi.amount, pi.unit, i.unit, pi.yield_percent, i.yield_percent
+		FROM product_ingredients pi
+		JOIN ingredients i ON pi.ingredient_id = i.id
+		ORDER BY pi.product_id, pi.ingredient_id
+	`
+
+	ingredientRows, err := r.db.QueryContext(ctx, ingredientsQuery)
+	if err != nil {
+		slog.Error("failed to list product ingredients", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer ingredientRows.Close()
+
+	for ingredientRows.Next() {
+		var productIngredient models.ProductIngredient
+		if err := ingredientRows.Scan(&productIngredient.ProductID, &productIngredient.IngredientID, &productIngredient.IngredientName, &productIngredient.Amount, &productIngredient.Unit, &productIngredient.IngredientUnit, &productIngredient.YieldPercent, &productIngredient.IngredientYieldPercent); err != nil {
+			slog.Error("failed to list product ingredients", "error", err)
+			return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+		}
+		if i, ok := productIndex[productIngredient.ProductID]; ok {
+			products[i].Ingredients = append(products[i].Ingredients, productIngredient)
+		}
+	}
+
+	if err := ingredientRows.Err(); err != nil {
+		slog.Error("failed to list product ingredients", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return products, nil
+}
+
+// CreateProduct inserts a new product and sets its generated ID.
+// The recipe is stored separately through SetProductIngredient.
+func (r *productRepository) CreateProduct(ctx context.Context, tx Transaction, product *models.Product) error {
+	query := `
+		INSERT INTO products (name)
+		VALUES ($1)
+		RETURNING id
+	`
+
+	err := tx.QueryRowContext(ctx, query, product.Name).Scan(&product.ID)
+	if err != nil {
+		slog.Error("failed to create product", "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// SetProductIngredient adds an ingredient to a product recipe or updates its amount and unit.
+func (r *productRepository) SetProductIngredient(ctx context.Context, tx Transaction, productIngredient models.ProductIngredient) error {
+	query := `
+		INSERT INTO product_ingredients (product_id, ingredient_id, amount, unit, yield_percent)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (product_id, ingredient_id) DO UPDATE
+		SET amount = EXCLUDED.amount, unit = EXCLUDED.unit, yield_percent = EXCLUDED.yield_percent
+	`
+
+	_, err := tx.ExecContext(ctx, query, productIngredient.ProductID, productIngredient.IngredientID, productIngredient.Amount, productIngredient.Unit, productIngredient.YieldPercent)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", productIngredient.IngredientID))
+		}
+		slog.Error("failed to set product ingredient", "productID", productIngredient.ProductID, "ingredientID", productIngredient.IngredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// RemoveProductIngredient detaches an ingredient from a product recipe.
+func (r *productRepository) RemoveProductIngredient(ctx context.Context, tx Transaction, productID int, ingredientID int) error {
+	query := `
+		DELETE FROM product_ingredients
+		WHERE product_id = $1 AND ingredient_id = $2
+	`
+
+	result, err := tx.ExecContext(ctx, query, productID, ingredientID)
+	if err != nil {
+		slog.Error("failed to remove product ingredient", "productID", productID, "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to remove product ingredient", "productID", productID, "ingredientID", ingredientID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d is not part of product %d", ingredientID, productID))
+	}
+
+	return nil
+}
+
+// ClearProductIngredients removes the whole recipe of a product.
+func (r *productRepository) ClearProductIngredients(ctx context.Context, tx Transaction, productID int) error {
+	query := `DELETE FROM product_ingredients WHERE product_id = $1`
+
+	if _, err := tx.ExecContext(ctx, query, productID); err != nil {
+		slog.Error("failed to clear product ingredients", "productID", productID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// SetMarkedUnavailable takes a product off the menu or puts it back, regardless of its stock.
+func (r *productRepository) SetMarkedUnavailable(ctx context.Context, productID int, unavailable bool) error {
+	query := `UPDATE products SET marked_unavailable = $1 WHERE id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, unavailable, productID)
+	if err != nil {
+		slog.Error("failed to mark product availability", "productID", productID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to mark product availability", "productID", productID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Product with ID %d not found", productID))
+	}
+
+	return nil
+}
+
+// CreateModifier stores a modifier of a product and sets its generated ID.
+func (r *productRepository) CreateModifier(ctx context.Context, modifier *models.ProductModifier) error {
+	query := `
+		INSERT INTO product_modifiers (product_id, name, action, ingredient_id, replacement_ingredient_id, amount, unit)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id
+	`
+
+	// A remove takes nothing, it stores neither an amount nor a unit
+	var amount, unit interface{}
+	if modifier.Action != models.ModifierActionRemove {
+		amount, unit = modifier.Amount, modifier.Unit
+	}
+
+	err := r.db.QueryRowContext(ctx, query,
+		modifier.ProductID,
+		modifier.Name,
+		modifier.Action,
+		modifier.IngredientID,
+		modifier.ReplacementIngredientID,
+		amount,
+		unit,
+	).Scan(&modifier.ID)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case pgForeignKeyViolation:
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Product %d or one of its modifier ingredients not found", modifier.ProductID))
+		case pgUniqueViolation:
+			return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Modifier already exists", fmt.Sprintf("Product %d already has a modifier named %q", modifier.ProductID, modifier.Name))
+		}
+		slog.Error("failed to create product modifier", "productID", modifier.ProductID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// ListModifiers returns the modifiers allowed for a product, within tx when given.
+func (r *productRepository) ListModifiers(ctx context.Context, tx Transaction, productID int) ([]models.ProductModifier, error) {
+	query := `
+		SELECT m.id, m.product_id, m.name, m.action, m.ingredient_id, m.replacement_ingredient_id,
+			COALESCE(m.amount, 0), COALESCE(m.unit, ''), i.unit, i.yield_percent
+		FROM product_modifiers m
+		JOIN ingredients i ON i.id = COALESCE(m.replacement_ingredient_id, m.ingredient_id)
+		WHERE m.product_id = $1
+		ORDER BY m.id
+	`
+
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, query, productID)
+	} else {
+		rows, err = r.db.QueryContext(ctx, query, productID)
+	}
+	if err != nil {
+		slog.Error("failed to list product modifiers", "productID", productID, "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	modifiers := []models.ProductModifier{}
+	for rows.Next() {
+		var modifier models.ProductModifier
+		var replacementID sql.NullInt64
+		if err := rows.Scan(
+			&modifier.ID,
+			&modifier.ProductID,
+			&modifier.Name,
+			&modifier.Action,
+			&modifier.IngredientID,
+			&replacementID,
+			&modifier.Amount,
+			&modifier.Unit,
+			&modifier.IngredientUnit,
+			&modifier.IngredientYieldPercent,
+		); err != nil {
+			slog.Error("failed to list product modifiers", "productID", productID, "error", err)
+			return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+		}
+		if replacementID.Valid {
+			id := int(replacementID.Int64)
+			modifier.ReplacementIngredientID = &id
+		}
+		modifiers = append(modifiers, modifier)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to list product modifiers", "productID", productID, "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return modifiers, nil
+}
+
+// DeleteModifier removes a modifier of a product. Orders that used it keep its ID.
+func (r *productRepository) DeleteModifier(ctx context.Context, productID int, modifierID int) error {
+	query := `DELETE FROM product_modifiers WHERE id = $1 AND product_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, modifierID, productID)
+	if err != nil {
+		slog.Error("failed to delete product modifier", "modifierID", modifierID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to delete product modifier", "modifierID", modifierID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Modifier with ID %d not found for product %d", modifierID, productID))
+	}
+
+	return nil
+}
+
+// CreateVariant stores a variant of a product with its overrides and sets its generated ID.
+func (r *productRepository) CreateVariant(ctx context.Context, tx Transaction, variant *models.ProductVariant) error {
+	query := `
+		INSERT INTO product_variants (product_id, name, multiplier)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	if err := tx.QueryRowContext(ctx, query, variant.ProductID, variant.Name, variant.Multiplier).Scan(&variant.ID); err != nil {
+		switch pgErrorCode(err) {
+		case pgForeignKeyViolation:
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Product with ID %d not found", variant.ProductID))
+		case pgUniqueViolation:
+			return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Variant already exists", fmt.Sprintf("Product %d already has a variant named %q", variant.ProductID, variant.Name))
+		}
+		slog.Error("failed to create product variant", "productID", variant.ProductID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	overrideQuery := `
+		INSERT INTO product_variant_ingredients (variant_id, ingredient_id, amount, unit)
+		VALUES ($1, $2, $3, $4)
+	`
+	for i := range variant.Overrides {
+		override := &variant.Overrides[i]
+		override.VariantID = variant.ID
+		if _, err := tx.ExecContext(ctx, overrideQuery, variant.ID, override.IngredientID, override.Amount, override.Unit); err != nil {
+			switch pgErrorCode(err) {
+			case pgForeignKeyViolation:
+				return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", override.IngredientID))
+			case pgUniqueViolation:
+				return internalErrors.NewAppError(internalErrors.ErrCodeValidation, "Invalid variant", fmt.Sprintf("Ingredient %d is overridden more than once", override.IngredientID))
+			}
+			slog.Error("failed to create product variant override", "variantID", variant.ID, "ingredientID", override.IngredientID, "error", err)
+			return errors.Wrap(errors.ErrInternalServer, "query failed")
+		}
+	}
+
+	return nil
+}
+
+// variantsQuery selects product variants with their overrides, a variant
+// without overrides comes with a single row of NULL override columns.
+const variantsQuery = `
+	SELECT v.id, v.product_id, v.name, v.multiplier, o.ingredient_id, o.amount, o.unit
+	FROM product_variants v
+	LEFT JOIN product_variant_ingredients o ON o.variant_id = v.id
+`
+
+// GetVariant fetches a product variant with its overrides, within tx when given.
+func (r *productRepository) GetVariant(ctx context.Context, tx Transaction, variantID int) (*models.ProductVariant, error) {
+	query := variantsQuery + ` WHERE v.id = $1 ORDER BY o.ingredient_id`
+
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, query, variantID)
+	} else {
+		rows, err = r.db.QueryContext(ctx, query, variantID)
+	}
+	if err != nil {
+		slog.Error("failed to retrieve product variant", "variantID", variantID, "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	variants, err := scanVariants(rows)
+	if err != nil {
+		slog.Error("failed to retrieve product variant", "variantID", variantID, "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if len(variants) == 0 {
+		return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Variant with ID %d not found", variantID))
+	}
+
+	return &variants[0], nil
+}
+
+// ListVariants returns the variants of a product.
+func (r *productRepository) ListVariants(ctx context.Context, productID int) ([]models.ProductVariant, error) {
+	query := variantsQuery + ` WHERE v.product_id = $1 ORDER BY v.id, o.ingredient_id`
+
+	rows, err := r.db.QueryContext(ctx, query, productID)
+	if err != nil {
+		slog.Error("failed to list product variants", "productID", productID, "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	variants, err := scanVariants(rows)
+	if err != nil {
+		slog.Error("failed to list product variants", "productID", productID, "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return variants, nil
+}
+
+// ListAllVariants returns the variants of every product, ordered by product.
+func (r *productRepository) ListAllVariants(ctx context.Context) ([]models.ProductVariant, error) {
+	query := variantsQuery + ` ORDER BY v.product_id, v.id, o.ingredient_id`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		slog.Error("failed to list product variants", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	variants, err := scanVariants(rows)
+	if err != nil {
+		slog.Error("failed to list product variants", "error", err)
+		return nil, errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	return variants, nil
+}
+
+// scanVariants groups the rows of variantsQuery by variant.
+func scanVariants(rows *sql.Rows) ([]models.ProductVariant, error) {
+	variants := []models.ProductVariant{}
+	for rows.Next() {
+		var variant models.ProductVariant
+		var ingredientID sql.NullInt64
+		var amount sql.NullFloat64
+		var unit sql.NullString
+		if err := rows.Scan(&variant.ID, &variant.ProductID, &variant.Name, &variant.Multiplier, &ingredientID, &amount, &unit); err != nil {
+			return nil, err
+		}
+
+		if len(variants) == 0 || variants[len(variants)-1].ID != variant.ID {
+			variant.Overrides = []models.VariantIngredient{}
+			variants = append(variants, variant)
+		}
+		if ingredientID.Valid {
+			last := &variants[len(variants)-1]
+			last.Overrides = append(last.Overrides, models.VariantIngredient{
+				VariantID:    variant.ID,
+				IngredientID: int(ingredientID.Int64),
+				Amount:       amount.Float64,
+				Unit:         unit.String,
+			})
+		}
+	}
+
+	return variants, rows.Err()
+}
+
+// DeleteVariant removes a variant of a product. Variants already ordered are kept for the order history.
+func (r *productRepository) DeleteVariant(ctx context.Context, productID int, variantID int) error {
+	query := `DELETE FROM product_variants WHERE id = $1 AND product_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, variantID, productID)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Variant in use", fmt.Sprintf("Variant with ID %d has been ordered", variantID))
+		}
+		slog.Error("failed to delete product variant", "variantID", variantID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to delete product variant", "variantID", variantID, "error", err)
+		return errors.Wrap(errors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Variant with ID %d not found for product %d", variantID, productID))
+	}
+
+	return nil
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
 	"testing"
 
@@ -23,30 +24,31 @@ func TestProductRepository_GetByID(t *testing.T) {
 	// Define the product ID to test
 	productID := 1
 
-	// Expected product and ingredients
+	// Expected product and ingredients, onion is peeled and the burger trims it harder
+	onionYield, burgerOnionYield := 90.0, 80.0
 	expectedProduct := models.Product{
 		ID:   productID,
 		Name: "Burger",
 		Ingredients: []models.ProductIngredient{
-			{ProductID: productID, IngredientID: 1, Amount: 150},
-			{ProductID: productID, IngredientID: 2, Amount: 30},
-			{ProductID: productID, IngredientID: 3, Amount: 20},
+			{ProductID: productID, IngredientID: 1, IngredientName: "Beef", Amount: 0.15, Unit: "kg", IngredientUnit: models.UnitGram},
+			{ProductID: productID, IngredientID: 2, IngredientName: "Cheese", Amount: 30, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+			{ProductID: productID, IngredientID: 3, IngredientName: "Onion", Amount: 20, Unit: models.UnitGram, IngredientUnit: models.UnitGram, YieldPercent: &burgerOnionYield, IngredientYieldPercent: &onionYield},
 		},
 	}
 
 	// Mock the product query
-	mock.ExpectQuery(`SELECT id, name FROM products WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, marked_unavailable FROM products WHERE id = \$1`).
 		WithArgs(productID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}).
-			AddRow(productID, "Burger"))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "marked_unavailable"}).
+			AddRow(productID, "Burger", false))
 
 	// Mock the product ingredients query
-	mock.ExpectQuery(`SELECT pi.product_id, pi.ingredient_id, pi.amount`).
+	mock.ExpectQuery(`SELECT pi.product_id, pi.ingredient_id, i.name, pi.amount, pi.unit, i.unit, pi.yield_percent, i.yield_percent`).
 		WithArgs(productID).
-		WillReturnRows(sqlmock.NewRows([]string{"product_id", "ingredient_id", "amount"}).
-			AddRow(productID, 1, 150).
-			AddRow(productID, 2, 30).
-			AddRow(productID, 3, 20))
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "ingredient_id", "name", "amount", "unit", "ingredient_unit", "yield_percent", "ingredient_yield_percent"}).
+			AddRow(productID, 1, "Beef", 0.15, "kg", models.UnitGram, nil, nil).
+			AddRow(productID, 2, "Cheese", 30, models.UnitGram, models.UnitGram, nil, nil).
+			AddRow(productID, 3, "Onion", 20, models.UnitGram, models.UnitGram, 80.0, 90.0))
 
 	// Call the method under test
 	product, err := repo.GetProductById(context.Background(), nil, productID)
@@ -72,9 +74,9 @@ func TestProductRepository_GetByID_NotFound(t *testing.T) {
 	productID := 999
 
 	// Mock the product query to return no rows
-	mock.ExpectQuery(`SELECT id, name FROM products WHERE id = \$1`).
+	mock.ExpectQuery(`SELECT id, name, .+ FROM products WHERE id = \$1`).
 		WithArgs(productID).
-		WillReturnRows(sqlmock.NewRows([]string{"id", "name"}))
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "marked_unavailable"}))
 
 	// Call the method under test
 	product, err := repo.GetProductById(context.Background(), nil, productID)
@@ -83,3 +85,361 @@ func TestProductRepository_GetByID_NotFound(t *testing.T) {
 	assert.Error(t, err, "Expected error when product not found")
 	assert.Nil(t, product, "Expected no product to be returned")
 }
+
+func TestProductRepository_ListProducts(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	// Expected products with their recipes
+	expectedProducts := []models.Product{
+		{
+			ID:   1,
+			Name: "Burger",
+			Ingredients: []models.ProductIngredient{
+				{ProductID: 1, IngredientID: 1, IngredientName: "Beef", Amount: 150, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+			},
+		},
+		{ID: 2, Name: "Water", MarkedUnavailable: true},
+	}
+
+	// Mock the products query
+	mock.ExpectQuery(`SELECT id, name, marked_unavailable FROM products ORDER BY id`).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "marked_unavailable"}).
+			AddRow(1, "Burger", false).
+			AddRow(2, "Water", true))
+
+	// Mock the recipes query
+	mock.ExpectQuery(`SELECT pi.product_id, pi.ingredient_id, i.name, pi.amount, pi.unit, i.unit, pi.yield_percent, i.yield_percent FROM product_ingredients pi`).
+		WillReturnRows(sqlmock.NewRows([]string{"product_id", "ingredient_id", "name", "amount", "unit", "ingredient_unit", "yield_percent", "ingredient_yield_percent"}).
+			AddRow(1, 1, "Beef", 150, models.UnitGram, models.UnitGram, nil, nil))
+
+	// Call the method under test
+	products, err := repo.ListProducts(context.Background())
+
+	// Assertions
+	assert.NoError(t, err, "Expected no error, got %v", err)
+	assert.Equal(t, expectedProducts, products, "Expected and actual products do not match")
+}
+
+func TestProductRepository_CreateProduct(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	product := &models.Product{Name: "Cheeseburger"}
+
+	mock.ExpectBegin()
+
+	// Mock the insert returning the generated ID
+	mock.ExpectQuery(`INSERT INTO products \(name\) VALUES \(\$1\) RETURNING id`).
+		WithArgs("Cheeseburger").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.CreateProduct(context.Background(), tx, product)
+
+	// Assertions
+	assert.NoError(t, err, "Expected no error, got %v", err)
+	assert.Equal(t, 2, product.ID, "Expected product ID to be 2")
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestProductRepository_SetProductIngredient(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	mock.ExpectBegin()
+
+	// Mock the upsert of the recipe line
+	mock.ExpectExec(`INSERT INTO product_ingredients \(product_id, ingredient_id, amount, unit, yield_percent\) VALUES \(\$1, \$2, \$3, \$4, \$5\) ON CONFLICT \(product_id, ingredient_id\) DO UPDATE SET amount = EXCLUDED.amount, unit = EXCLUDED.unit, yield_percent = EXCLUDED.yield_percent`).
+		WithArgs(1, 2, 45.0, models.UnitGram, 95.0).
+		WillReturnResult(sqlmock.NewResult(1, 1))
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	yieldPercent := 95.0
+	err = repo.SetProductIngredient(context.Background(), tx, models.ProductIngredient{ProductID: 1, IngredientID: 2, Amount: 45, Unit: models.UnitGram, YieldPercent: &yieldPercent})
+
+	// Assertions
+	assert.NoError(t, err, "Expected no error, got %v", err)
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestProductRepository_RemoveProductIngredient_NotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	mock.ExpectBegin()
+
+	// Mock the delete affecting no rows
+	mock.ExpectExec(`DELETE FROM product_ingredients WHERE product_id = \$1 AND ingredient_id = \$2`).
+		WithArgs(1, 9).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.RemoveProductIngredient(context.Background(), tx, 1, 9)
+
+	// Assertions
+	assert.Error(t, err, "Expected error when ingredient is not part of the recipe")
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestProductRepository_SetMarkedUnavailable(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	// Mock the flag update, the second product does not exist
+	mock.ExpectExec(`UPDATE products SET marked_unavailable = \$1 WHERE id = \$2`).
+		WithArgs(true, 1).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(`UPDATE products SET marked_unavailable = \$1 WHERE id = \$2`).
+		WithArgs(true, 999).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	// Call the method under test
+	err = repo.SetMarkedUnavailable(context.Background(), 1, true)
+	assert.NoError(t, err)
+
+	err = repo.SetMarkedUnavailable(context.Background(), 999, true)
+	var appErr *internalErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestProductRepository_ListModifiers(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	// Mock the modifiers query
+	mock.ExpectQuery(`SELECT m.id, m.product_id, m.name, m.action, m.ingredient_id, m.replacement_ingredient_id, COALESCE\(m.amount, 0\), COALESCE\(m.unit, ''\), i.unit, i.yield_percent FROM product_modifiers m`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "action", "ingredient_id", "replacement_ingredient_id", "amount", "unit", "ingredient_unit", "ingredient_yield_percent"}).
+			AddRow(7, 1, "No onion", models.ModifierActionRemove, 3, nil, 0.0, "", models.UnitGram, 90.0).
+			AddRow(8, 1, "Cheddar", models.ModifierActionReplace, 2, 4, 40.0, models.UnitGram, models.UnitGram, nil))
+
+	// Call the method under test
+	modifiers, err := repo.ListModifiers(context.Background(), nil, 1)
+
+	// Assertions
+	replacementID, onionYield := 4, 90.0
+	assert.NoError(t, err)
+	assert.Equal(t, []models.ProductModifier{
+		{ID: 7, ProductID: 1, Name: "No onion", Action: models.ModifierActionRemove, IngredientID: 3, IngredientUnit: models.UnitGram, IngredientYieldPercent: &onionYield},
+		{ID: 8, ProductID: 1, Name: "Cheddar", Action: models.ModifierActionReplace, IngredientID: 2, ReplacementIngredientID: &replacementID, Amount: 40, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+	}, modifiers)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestProductRepository_CreateModifier(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	// Mock the insert, a remove stores neither amount nor unit
+	mock.ExpectQuery(`INSERT INTO product_modifiers \(product_id, name, action, ingredient_id, replacement_ingredient_id, amount, unit\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6, \$7\) RETURNING id`).
+		WithArgs(1, "No onion", models.ModifierActionRemove, 3, nil, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(7))
+
+	// Call the method under test
+	modifier := &models.ProductModifier{ProductID: 1, Name: "No onion", Action: models.ModifierActionRemove, IngredientID: 3}
+	err = repo.CreateModifier(context.Background(), modifier)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 7, modifier.ID)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestProductRepository_GetVariant(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	// Mock the variant query, one row per override
+	mock.ExpectQuery(`SELECT v.id, v.product_id, v.name, v.multiplier, o.ingredient_id, o.amount, o.unit FROM product_variants v LEFT JOIN product_variant_ingredients o ON o.variant_id = v.id WHERE v.id = \$1 ORDER BY o.ingredient_id`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "multiplier", "ingredient_id", "amount", "unit"}).
+			AddRow(5, 1, "Double", 2.0, 2, 45.0, models.UnitGram).
+			AddRow(5, 1, "Double", 2.0, 3, 1.0, models.UnitPiece))
+
+	// Call the method under test
+	variant, err := repo.GetVariant(context.Background(), nil, 5)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, &models.ProductVariant{ID: 5, ProductID: 1, Name: "Double", Multiplier: 2, Overrides: []models.VariantIngredient{
+		{VariantID: 5, IngredientID: 2, Amount: 45, Unit: models.UnitGram},
+		{VariantID: 5, IngredientID: 3, Amount: 1, Unit: models.UnitPiece},
+	}}, variant)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestProductRepository_GetVariant_NotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	mock.ExpectQuery(`SELECT v.id, v.product_id, v.name, v.multiplier`).
+		WithArgs(5).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "product_id", "name", "multiplier", "ingredient_id", "amount", "unit"}))
+
+	// Call the method under test
+	variant, err := repo.GetVariant(context.Background(), nil, 5)
+
+	// Assertions
+	assert.Nil(t, variant)
+	var appErr *internalErrors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+	}
+}
+
+func TestProductRepository_CreateVariant(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewProductRepository(db)
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(`INSERT INTO product_variants \(product_id, name, multiplier\) VALUES \(\$1, \$2, \$3\) RETURNING id`).
+		WithArgs(1, "Double", 2.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(5))
+	mock.ExpectExec(`INSERT INTO product_variant_ingredients \(variant_id, ingredient_id, amount, unit\) VALUES \(\$1, \$2, \$3, \$4\)`).
+		WithArgs(5, 2, 45.0, models.UnitGram).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	variant := &models.ProductVariant{ProductID: 1, Name: "Double", Multiplier: 2, Overrides: []models.VariantIngredient{
+		{IngredientID: 2, Amount: 45, Unit: models.UnitGram},
+	}}
+	err = repo.CreateVariant(context.Background(), tx, variant)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 5, variant.ID)
+	assert.Equal(t, 5, variant.Overrides[0].VariantID)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type ReceiptRepository interface {
+	BeginTransaction() (Transaction, error)
+	CreateReceipt(ctx context.Context, tx Transaction, receipt *models.StockReceipt) error
+	GetReceiptByID(ctx context.Context, receiptID int) (*models.StockReceipt, error)
+}
+
+type receiptRepository struct {
+	db *sql.DB
+}
+
+func NewReceiptRepository(db *sql.DB) ReceiptRepository {
+	return &receiptRepository{db: db}
+}
+
+var _ ReceiptRepository = (*receiptRepository)(nil)
+
+func (r *receiptRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
+// CreateReceipt stores a stock receipt and its items, setting the generated IDs.
+func (r *receiptRepository) CreateReceipt(ctx context.Context, tx Transaction, receipt *models.StockReceipt) error {
+	query := `
+		INSERT INTO stock_receipts (received_by, notes)
+		VALUES ($1, $2)
+		RETURNING id, received_at
+	`
+
+	err := tx.QueryRowContext(ctx, query, receipt.ReceivedBy, receipt.Notes).Scan(&receipt.ID, &receipt.ReceivedAt)
+	if err != nil {
+		slog.Error("failed to create stock receipt", "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	itemQuery := `
+		INSERT INTO stock_receipt_items (receipt_id, ingredient_id, quantity, increase_total_stock, pack_id, pack_count)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	for i := range receipt.Items {
+		item := &receipt.Items[i]
+		var packCount *float64
+		if item.PackID != nil {
+			packCount = &item.Packs
+		}
+		err := tx.QueryRowContext(ctx, itemQuery, receipt.ID, item.IngredientID, item.Quantity, item.IncreaseTotalStock, item.PackID, packCount).Scan(&item.ID)
+		if err != nil {
+			if pgErrorCode(err) == pgForeignKeyViolation {
+				return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", item.IngredientID))
+			}
+			slog.Error("failed to insert stock receipt item", "error", err)
+			return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+	}
+
+	return nil
+}
+
+// GetReceiptByID fetches a stock receipt with its items.
+func (r *receiptRepository) GetReceiptByID(ctx context.Context, receiptID int) (*models.StockReceipt, error) {
+	receiptQuery := `
+		SELECT id, received_by, notes, received_at
+		FROM stock_receipts
+		WHERE id = $1
+	`
+
+	itemsQuery := `
+		SELECT ri.id, ri.ingredient_id, ri.quantity, ri.increase_total_stock, ri.pack_id, COALESCE(ri.pack_count, 0),
+			COALESCE(l.lot_number, ''), l.expires_at
+		FROM stock_receipt_items ri
+		LEFT JOIN ingredient_lots l ON l.receipt_item_id = ri.id
+		WHERE ri.receipt_id = $1
+		ORDER BY ri.ingredient_id, ri.id
+	`
+
+	var receipt models.StockReceipt
+	err := r.db.QueryRowContext(ctx, receiptQuery, receiptID).Scan(
+		&receipt.ID,
+		&receipt.ReceivedBy,
+		&receipt.Notes,
+		&receipt.ReceivedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Stock receipt with ID %d not found", receiptID))
+		}
+		slog.Error("failed to retrieve stock receipt", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rows, err := r.db.QueryContext(ctx, itemsQuery, receiptID)
+	if err != nil {
+		slog.Error("failed to retrieve stock receipt items", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item models.StockReceiptItem
+		if err := rows.Scan(&item.ID, &item.IngredientID, &item.Quantity, &item.IncreaseTotalStock, &item.PackID, &item.Packs, &item.LotNumber, &item.ExpiresAt); err != nil {
+			slog.Error("failed to retrieve stock receipt item", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		receipt.Items = append(receipt.Items, item)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.Error("failed to retrieve stock receipt item", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return &receipt, nil
+}
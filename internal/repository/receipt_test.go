@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"stockk/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReceiptRepository_CreateReceipt(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewReceiptRepository(db)
+
+	receivedAt := time.Now()
+	packID := 7
+	receipt := &models.StockReceipt{
+		ReceivedBy: "Sam",
+		Items: []models.StockReceiptItem{
+			{IngredientID: 1, Quantity: 5000, IncreaseTotalStock: true},
+			{IngredientID: 2, Quantity: 1000, PackID: &packID, Packs: 2},
+		},
+	}
+
+	mock.ExpectBegin()
+
+	// Mock the receipt header insert
+	mock.ExpectQuery(`INSERT INTO stock_receipts \(received_by, notes\) VALUES \(\$1, \$2\) RETURNING id, received_at`).
+		WithArgs("Sam", "").
+		WillReturnRows(sqlmock.NewRows([]string{"id", "received_at"}).AddRow(3, receivedAt))
+
+	// Mock the receipt items inserts
+	mock.ExpectQuery(`INSERT INTO stock_receipt_items \(receipt_id, ingredient_id, quantity, increase_total_stock, pack_id, pack_count\) VALUES \(\$1, \$2, \$3, \$4, \$5, \$6\) RETURNING id`).
+		WithArgs(3, 1, 5000.0, true, nil, nil).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(11))
+	mock.ExpectQuery(`INSERT INTO stock_receipt_items`).
+		WithArgs(3, 2, 1000.0, false, 7, 2.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(12))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.CreateReceipt(context.Background(), tx, receipt)
+
+	// Assertions
+	assert.NoError(t, err, "Expected no error, got %v", err)
+	assert.Equal(t, 3, receipt.ID)
+	assert.Equal(t, receivedAt, receipt.ReceivedAt)
+	assert.Equal(t, 11, receipt.Items[0].ID)
+	assert.Equal(t, 12, receipt.Items[1].ID)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestReceiptRepository_GetReceiptByID(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewReceiptRepository(db)
+
+	receivedAt := time.Now()
+	firstExpiry := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	secondExpiry := time.Date(2026, 3, 28, 0, 0, 0, 0, time.UTC)
+
+	// Mock the receipt header query
+	mock.ExpectQuery(`SELECT id, received_by, notes, received_at FROM stock_receipts WHERE id = \$1`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "received_by", "notes", "received_at"}).AddRow(3, "Sam", "", receivedAt))
+
+	// Mock the items query, each item joined to the lot it came in with
+	mock.ExpectQuery(`SELECT ri.id, ri.ingredient_id, .+ FROM stock_receipt_items ri LEFT JOIN ingredient_lots l ON l.receipt_item_id = ri.id WHERE ri.receipt_id = \$1 ORDER BY ri.ingredient_id, ri.id`).
+		WithArgs(3).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "ingredient_id", "quantity", "increase_total_stock", "pack_id", "pack_count", "lot_number", "expires_at"}).
+			AddRow(11, 1, 3000.0, false, nil, 0.0, "L-2291", firstExpiry).
+			AddRow(12, 1, 2000.0, false, nil, 0.0, "L-2304", secondExpiry))
+
+	// Call the method under test
+	receipt, err := repo.GetReceiptByID(context.Background(), 3)
+
+	// Assertions
+	assert.NoError(t, err)
+	if assert.Len(t, receipt.Items, 2) {
+		assert.Equal(t, 11, receipt.Items[0].ID)
+		assert.Equal(t, "L-2291", receipt.Items[0].LotNumber)
+		assert.Equal(t, firstExpiry, *receipt.Items[0].ExpiresAt)
+		assert.Equal(t, 12, receipt.Items[1].ID)
+		assert.Equal(t, "L-2304", receipt.Items[1].LotNumber)
+		assert.Equal(t, secondExpiry, *receipt.Items[1].ExpiresAt)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestReceiptRepository_GetReceiptByID_NotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewReceiptRepository(db)
+
+	// Mock the receipt query returning no rows
+	mock.ExpectQuery(`SELECT id, received_by, notes, received_at FROM stock_receipts WHERE id = \$1`).
+		WithArgs(999).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "received_by", "notes", "received_at"}))
+
+	// Call the method under test
+	receipt, err := repo.GetReceiptByID(context.Background(), 999)
+
+	// Assertions
+	assert.Error(t, err, "Expected error when receipt is not found")
+	assert.Nil(t, receipt, "Expected no receipt to be returned")
+}
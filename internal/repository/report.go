@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type ReportRepository interface {
+	SumUsage(ctx context.Context, filter models.UsageReportFilter) ([]models.UsageVariance, error)
+}
+
+type reportRepository struct {
+	db *sql.DB
+}
+
+func NewReportRepository(db *sql.DB) ReportRepository {
+	return &reportRepository{db: db}
+}
+
+var _ ReportRepository = (*reportRepository)(nil)
+
+// SumUsage adds up per ingredient what the orders placed within filter
+// should have used and what orders, waste and count corrections took out of
+// stock within filter, sorted by ingredient ID. Cancelled orders use nothing,
+// restocks and manual adjustments are not usage.
+func (r *reportRepository) SumUsage(ctx context.Context, filter models.UsageReportFilter) ([]models.UsageVariance, error) {
+	var args []interface{}
+	orderConditions := []string{"o.status = 'placed'"}
+	movementConditions := []string{"m.reason IN ('order', 'cancellation', 'waste', 'count')"}
+
+	if filter.From != nil {
+		args = append(args, *filter.From)
+		orderConditions = append(orderConditions, fmt.Sprintf("o.created_at >= $%d", len(args)))
+		movementConditions = append(movementConditions, fmt.Sprintf("m.created_at >= $%d", len(args)))
+	}
+	if filter.To != nil {
+		args = append(args, *filter.To)
+		orderConditions = append(orderConditions, fmt.Sprintf("o.created_at < $%d", len(args)))
+		movementConditions = append(movementConditions, fmt.Sprintf("m.created_at < $%d", len(args)))
+	}
+
+	query := fmt.Sprintf(`
+		WITH theoretical AS (
+			SELECT c.ingredient_id, SUM(c.amount) AS amount, SUM(c.amount - c.theoretical_amount) AS yield_loss
+			FROM order_consumptions c
+			JOIN orders o ON o.id = c.order_id
+			WHERE %s
+			GROUP BY c.ingredient_id
+		), actual AS (
+			SELECT m.ingredient_id, -SUM(m.delta) AS amount,
+				-COALESCE(SUM(m.delta) FILTER (WHERE m.reason = 'waste'), 0) AS waste
+			FROM stock_movements m
+			WHERE %s
+			GROUP BY m.ingredient_id
+		)
+		SELECT i.id, i.name, i.unit, COALESCE(t.amount, 0), COALESCE(t.yield_loss, 0), COALESCE(a.amount, 0), COALESCE(a.waste, 0)
+		FROM ingredients i
+		LEFT JOIN theoretical t ON t.ingredient_id = i.id
+		LEFT JOIN actual a ON a.ingredient_id = i.id
+		WHERE t.ingredient_id IS NOT NULL OR a.ingredient_id IS NOT NULL
+		ORDER BY i.id
+	`, strings.Join(orderConditions, " AND "), strings.Join(movementConditions, " AND "))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to sum ingredient usage", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	usages := []models.UsageVariance{}
+	for rows.Next() {
+		var usage models.UsageVariance
+		if err := rows.Scan(&usage.IngredientID, &usage.IngredientName, &usage.Unit, &usage.Theoretical, &usage.YieldLoss, &usage.Actual, &usage.Waste); err != nil {
+			slog.Error("failed to sum ingredient usage", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		usages = append(usages, usage)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to sum ingredient usage", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return usages, nil
+}
@@ -0,0 +1,48 @@
+package repository
+
+import (
+	"context"
+	"stockk/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReportRepository_SumUsage(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewReportRepository(db)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Mock the usage sums, both the orders and the ledger are bounded by the period
+	mock.ExpectQuery(`WITH theoretical AS \(.+ WHERE o.status = 'placed' AND o.created_at >= \$1 AND o.created_at < \$2 .+\), actual AS \(.+ WHERE m.reason IN \('order', 'cancellation', 'waste', 'count'\) AND m.created_at >= \$1 AND m.created_at < \$2 .+\) SELECT i.id, i.name, i.unit, .+ ORDER BY i.id`).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "name", "unit", "theoretical", "yield_loss", "actual", "waste"}).
+			AddRow(1, "Beef", models.UnitGram, 4000.0, 1000.0, 4500.0, 200.0).
+			AddRow(5, "Salt", models.UnitGram, 0.0, 0.0, 50.0, 50.0))
+
+	// Call the method under test
+	usages, err := repo.SumUsage(context.Background(), models.UsageReportFilter{From: &from, To: &to})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []models.UsageVariance{
+		{IngredientID: 1, IngredientName: "Beef", Unit: models.UnitGram, Theoretical: 4000, YieldLoss: 1000, Actual: 4500, Waste: 200},
+		{IngredientID: 5, IngredientName: "Salt", Unit: models.UnitGram, Actual: 50, Waste: 50},
+	}, usages)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,294 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type StockCountRepository interface {
+	BeginTransaction() (Transaction, error)
+	CreateSession(ctx context.Context, session *models.StockCountSession) error
+	GetSession(ctx context.Context, sessionID int) (*models.StockCountSession, error)
+	LockOpenSession(ctx context.Context, tx Transaction, sessionID int) error
+	SaveCount(ctx context.Context, tx Transaction, count models.StockCount) error
+	SumCounts(ctx context.Context, tx Transaction, sessionID int) ([]models.StockCountVariance, error)
+	CloseSession(ctx context.Context, tx Transaction, sessionID int) error
+	SaveCountResult(ctx context.Context, tx Transaction, sessionID int, variance models.StockCountVariance) error
+	ListCountResults(ctx context.Context, sessionID int) ([]models.StockCountVariance, error)
+}
+
+type stockCountRepository struct {
+	db *sql.DB
+}
+
+func NewStockCountRepository(db *sql.DB) StockCountRepository {
+	return &stockCountRepository{db: db}
+}
+
+var _ StockCountRepository = (*stockCountRepository)(nil)
+
+func (r *stockCountRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
+// CreateSession opens a count session, setting its generated ID, status and timestamp.
+func (r *stockCountRepository) CreateSession(ctx context.Context, session *models.StockCountSession) error {
+	query := `
+		INSERT INTO stock_count_sessions (opened_by, notes)
+		VALUES ($1, $2)
+		RETURNING id, status, opened_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, session.OpenedBy, session.Notes).Scan(&session.ID, &session.Status, &session.OpenedAt)
+	if err != nil {
+		slog.Error("failed to create stock count session", "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// GetSession fetches a count session with the counts submitted so far,
+// sorted by ingredient ID.
+func (r *stockCountRepository) GetSession(ctx context.Context, sessionID int) (*models.StockCountSession, error) {
+	sessionQuery := `
+		SELECT id, opened_by, notes, status, opened_at, closed_at
+		FROM stock_count_sessions
+		WHERE id = $1
+	`
+
+	countsQuery := `
+		SELECT ingredient_id, counted_by, quantity, counted_at
+		FROM stock_count_entries
+		WHERE session_id = $1
+		ORDER BY ingredient_id, counted_by
+	`
+
+	var session models.StockCountSession
+	err := r.db.QueryRowContext(ctx, sessionQuery, sessionID).Scan(
+		&session.ID,
+		&session.OpenedBy,
+		&session.Notes,
+		&session.Status,
+		&session.OpenedAt,
+		&session.ClosedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Stock count session with ID %d not found", sessionID))
+		}
+		slog.Error("failed to retrieve stock count session", "sessionID", sessionID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rows, err := r.db.QueryContext(ctx, countsQuery, sessionID)
+	if err != nil {
+		slog.Error("failed to retrieve stock counts", "sessionID", sessionID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	session.Counts = []models.StockCount{}
+	for rows.Next() {
+		count := models.StockCount{SessionID: sessionID}
+		if err := rows.Scan(&count.IngredientID, &count.CountedBy, &count.Quantity, &count.CountedAt); err != nil {
+			slog.Error("failed to retrieve stock counts", "sessionID", sessionID, "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		session.Counts = append(session.Counts, count)
+	}
+
+	if err = rows.Err(); err != nil {
+		slog.Error("failed to retrieve stock counts", "sessionID", sessionID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return &session, nil
+}
+
+// LockOpenSession holds a count session open until tx ends, so it cannot be
+// closed while counts are being submitted.
+func (r *stockCountRepository) LockOpenSession(ctx context.Context, tx Transaction, sessionID int) error {
+	query := `
+		SELECT status
+		FROM stock_count_sessions
+		WHERE id = $1
+		FOR SHARE
+	`
+
+	var status string
+	err := tx.QueryRowContext(ctx, query, sessionID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Stock count session with ID %d not found", sessionID))
+		}
+		slog.Error("failed to lock stock count session", "sessionID", sessionID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if status != models.CountStatusOpen {
+		return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Stock count session closed", fmt.Sprintf("Stock count session with ID %d is already %s", sessionID, status))
+	}
+
+	return nil
+}
+
+// SaveCount stores the count of an ingredient by a member of staff, replacing
+// their previous count of it in the session.
+func (r *stockCountRepository) SaveCount(ctx context.Context, tx Transaction, count models.StockCount) error {
+	query := `
+		INSERT INTO stock_count_entries (session_id, ingredient_id, counted_by, quantity)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (session_id, ingredient_id, counted_by)
+		DO UPDATE SET quantity = EXCLUDED.quantity, counted_at = NOW()
+	`
+
+	_, err := tx.ExecContext(ctx, query, count.SessionID, count.IngredientID, count.CountedBy, count.Quantity)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", count.IngredientID))
+		}
+		slog.Error("failed to save stock count", "sessionID", count.SessionID, "ingredientID", count.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// SumCounts adds up the counts of every ingredient counted in a session and
+// compares them with the stock the system had when it was last counted, the
+// current stock less the movements recorded since, sorted by ingredient ID.
+// The stock and its movements change in the same transaction, so a single
+// statement always sees them agree.
+func (r *stockCountRepository) SumCounts(ctx context.Context, tx Transaction, sessionID int) ([]models.StockCountVariance, error) {
+	query := `
+		WITH counted AS (
+			SELECT ingredient_id, SUM(quantity) AS quantity, MAX(counted_at) AS counted_at
+			FROM stock_count_entries
+			WHERE session_id = $1
+			GROUP BY ingredient_id
+		)
+		SELECT c.ingredient_id, i.name, i.unit, i.current_stock - COALESCE(SUM(m.delta), 0), c.quantity
+		FROM counted c
+		JOIN ingredients i ON i.id = c.ingredient_id
+		LEFT JOIN stock_movements m ON m.ingredient_id = c.ingredient_id AND m.created_at > c.counted_at
+		GROUP BY c.ingredient_id, i.name, i.unit, i.current_stock, c.quantity
+		ORDER BY c.ingredient_id
+	`
+
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, query, sessionID)
+	} else {
+		rows, err = r.db.QueryContext(ctx, query, sessionID)
+	}
+	if err != nil {
+		slog.Error("failed to sum stock counts", "sessionID", sessionID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return scanCountVariances(rows, sessionID)
+}
+
+// CloseSession marks an open count session closed.
+func (r *stockCountRepository) CloseSession(ctx context.Context, tx Transaction, sessionID int) error {
+	query := `
+		UPDATE stock_count_sessions
+		SET status = $1, closed_at = NOW()
+		WHERE id = $2 AND status = $3
+	`
+
+	result, err := tx.ExecContext(ctx, query, models.CountStatusClosed, sessionID, models.CountStatusOpen)
+	if err != nil {
+		slog.Error("failed to close stock count session", "sessionID", sessionID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to close stock count session", "sessionID", sessionID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected > 0 {
+		return nil
+	}
+
+	// Nothing was updated, either the session does not exist or it is closed already
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM stock_count_sessions WHERE id = $1`, sessionID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Stock count session with ID %d not found", sessionID))
+		}
+		slog.Error("failed to retrieve stock count session status", "sessionID", sessionID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Stock count session closed", fmt.Sprintf("Stock count session with ID %d is already %s", sessionID, status))
+}
+
+// SaveCountResult stores the outcome of a closed count for an ingredient.
+func (r *stockCountRepository) SaveCountResult(ctx context.Context, tx Transaction, sessionID int, variance models.StockCountVariance) error {
+	query := `
+		INSERT INTO stock_count_results (session_id, ingredient_id, system_stock, counted)
+		VALUES ($1, $2, $3, $4)
+	`
+
+	_, err := tx.ExecContext(ctx, query, sessionID, variance.IngredientID, variance.SystemStock, variance.Counted)
+	if err != nil {
+		slog.Error("failed to save stock count result", "sessionID", sessionID, "ingredientID", variance.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// ListCountResults returns the outcome of a closed count, sorted by ingredient ID.
+func (r *stockCountRepository) ListCountResults(ctx context.Context, sessionID int) ([]models.StockCountVariance, error) {
+	query := `
+		SELECT r.ingredient_id, i.name, i.unit, r.system_stock, r.counted
+		FROM stock_count_results r
+		JOIN ingredients i ON i.id = r.ingredient_id
+		WHERE r.session_id = $1
+		ORDER BY r.ingredient_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, sessionID)
+	if err != nil {
+		slog.Error("failed to list stock count results", "sessionID", sessionID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return scanCountVariances(rows, sessionID)
+}
+
+// scanCountVariances reads rows of ingredient, system stock and counted
+// quantity, computing the variance of each.
+func scanCountVariances(rows *sql.Rows, sessionID int) ([]models.StockCountVariance, error) {
+	defer rows.Close()
+
+	variances := []models.StockCountVariance{}
+	for rows.Next() {
+		var variance models.StockCountVariance
+		if err := rows.Scan(&variance.IngredientID, &variance.IngredientName, &variance.Unit, &variance.SystemStock, &variance.Counted); err != nil {
+			slog.Error("failed to retrieve stock count variances", "sessionID", sessionID, "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		variance.Variance = variance.Counted - variance.SystemStock
+		variances = append(variances, variance)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to retrieve stock count variances", "sessionID", sessionID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return variances, nil
+}
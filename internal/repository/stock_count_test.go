@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockCountRepository_SumCounts(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewStockCountRepository(db)
+
+	// Mock the sums of the counts by several staff against the stock at the last count
+	mock.ExpectQuery(`WITH counted AS \( SELECT ingredient_id, SUM\(quantity\) AS quantity, MAX\(counted_at\) AS counted_at FROM stock_count_entries WHERE session_id = \$1 GROUP BY ingredient_id \) SELECT c.ingredient_id, i.name, i.unit, i.current_stock - COALESCE\(SUM\(m.delta\), 0\), c.quantity FROM counted c JOIN ingredients i ON i.id = c.ingredient_id LEFT JOIN stock_movements m ON m.ingredient_id = c.ingredient_id AND m.created_at > c.counted_at GROUP BY .+ ORDER BY c.ingredient_id`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"ingredient_id", "name", "unit", "system_stock", "counted"}).
+			AddRow(1, "Beef", models.UnitGram, 4800.0, 4500.0).
+			AddRow(3, "Onion", models.UnitGram, 800.0, 900.0))
+
+	// Call the method under test
+	variances, err := repo.SumCounts(context.Background(), nil, 4)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []models.StockCountVariance{
+		{IngredientID: 1, IngredientName: "Beef", Unit: models.UnitGram, SystemStock: 4800, Counted: 4500, Variance: -300},
+		{IngredientID: 3, IngredientName: "Onion", Unit: models.UnitGram, SystemStock: 800, Counted: 900, Variance: 100},
+	}, variances)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStockCountRepository_CloseSession_AlreadyClosed(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewStockCountRepository(db)
+
+	mock.ExpectBegin()
+
+	// Mock the update matching no open session and the status lookup
+	mock.ExpectExec(`UPDATE stock_count_sessions SET status = \$1, closed_at = NOW\(\) WHERE id = \$2 AND status = \$3`).
+		WithArgs(models.CountStatusClosed, 4, models.CountStatusOpen).
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery(`SELECT status FROM stock_count_sessions WHERE id = \$1`).
+		WithArgs(4).
+		WillReturnRows(sqlmock.NewRows([]string{"status"}).AddRow(models.CountStatusClosed))
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.CloseSession(context.Background(), tx, 4)
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, internalErrors.ErrCodeConflict, appErr.Code)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type StockMovementRepository interface {
+	RecordMovement(ctx context.Context, tx Transaction, movement *models.StockMovement) error
+	ListMovements(ctx context.Context, filter models.StockMovementFilter) ([]models.StockMovement, error)
+}
+
+type stockMovementRepository struct {
+	db *sql.DB
+}
+
+func NewStockMovementRepository(db *sql.DB) StockMovementRepository {
+	return &stockMovementRepository{db: db}
+}
+
+var _ StockMovementRepository = (*stockMovementRepository)(nil)
+
+// RecordMovement appends a movement to the stock ledger, setting its generated
+// ID and timestamp. It must run in the transaction that changes the stock.
+func (r *stockMovementRepository) RecordMovement(ctx context.Context, tx Transaction, movement *models.StockMovement) error {
+	query := `
+		INSERT INTO stock_movements (ingredient_id, delta, reason, reference_id)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := tx.QueryRowContext(ctx, query, movement.IngredientID, movement.Delta, movement.Reason, movement.ReferenceID).Scan(&movement.ID, &movement.CreatedAt)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", movement.IngredientID))
+		}
+		slog.Error("failed to record stock movement", "ingredientID", movement.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// ListMovements returns the movements of an ingredient matching filter, newest first.
+func (r *stockMovementRepository) ListMovements(ctx context.Context, filter models.StockMovementFilter) ([]models.StockMovement, error) {
+	conditions := []string{"ingredient_id = $1"}
+	args := []interface{}{filter.IngredientID}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.Cursor > 0 {
+		addCondition("id < $%d", filter.Cursor)
+	}
+	if filter.From != nil {
+		addCondition("created_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("created_at < $%d", *filter.To)
+	}
+	args = append(args, filter.Limit)
+
+	query := fmt.Sprintf(`
+		SELECT id, ingredient_id, delta, reason, reference_id, created_at
+		FROM stock_movements
+		WHERE %s
+		ORDER BY id DESC
+		LIMIT $%d
+	`, strings.Join(conditions, " AND "), len(args))
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to list stock movements", "ingredientID", filter.IngredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	movements := []models.StockMovement{}
+	for rows.Next() {
+		var movement models.StockMovement
+		if err := rows.Scan(&movement.ID, &movement.IngredientID, &movement.Delta, &movement.Reason, &movement.ReferenceID, &movement.CreatedAt); err != nil {
+			slog.Error("failed to list stock movements", "ingredientID", filter.IngredientID, "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		movements = append(movements, movement)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to list stock movements", "ingredientID", filter.IngredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return movements, nil
+}
@@ -0,0 +1,109 @@
+package repository
+
+import (
+	"context"
+	"stockk/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStockMovementRepository_RecordMovement(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewStockMovementRepository(db)
+
+	orderID := 7
+	createdAt := time.Now()
+	movement := &models.StockMovement{
+		IngredientID: 1,
+		Delta:        -150,
+		Reason:       models.MovementReasonOrder,
+		ReferenceID:  &orderID,
+	}
+
+	mock.ExpectBegin()
+
+	// Mock the ledger insert
+	mock.ExpectQuery(`INSERT INTO stock_movements \(ingredient_id, delta, reason, reference_id\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id, created_at`).
+		WithArgs(1, -150.0, models.MovementReasonOrder, &orderID).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(12, createdAt))
+
+	mock.ExpectCommit()
+
+	tx, err := db.Begin()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.RecordMovement(context.Background(), tx, movement)
+
+	// Assertions
+	assert.NoError(t, err, "Expected no error, got %v", err)
+	assert.Equal(t, 12, movement.ID)
+	assert.Equal(t, createdAt, movement.CreatedAt)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that the mock expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestStockMovementRepository_ListMovements(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewStockMovementRepository(db)
+
+	from := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	createdAt := time.Now()
+
+	// Mock the listing query with cursor and date filters
+	rows := sqlmock.NewRows([]string{"id", "ingredient_id", "delta", "reason", "reference_id", "created_at"}).
+		AddRow(9, 1, -150.0, models.MovementReasonOrder, 7, createdAt).
+		AddRow(8, 1, 5000.0, models.MovementReasonRestock, 3, createdAt).
+		AddRow(1, 1, 20000.0, models.MovementReasonAdjustment, nil, createdAt)
+	mock.ExpectQuery(`SELECT id, ingredient_id, delta, reason, reference_id, created_at FROM stock_movements WHERE ingredient_id = \$1 AND id < \$2 AND created_at >= \$3 ORDER BY id DESC LIMIT \$4`).
+		WithArgs(1, 10, from, 3).
+		WillReturnRows(rows)
+
+	// Call the method under test
+	movements, err := repo.ListMovements(context.Background(), models.StockMovementFilter{
+		IngredientID: 1,
+		From:         &from,
+		Cursor:       10,
+		Limit:        3,
+	})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Len(t, movements, 3)
+	assert.Equal(t, -150.0, movements[0].Delta)
+	assert.Equal(t, 7, *movements[0].ReferenceID)
+	assert.Equal(t, models.MovementReasonRestock, movements[1].Reason)
+	assert.Nil(t, movements[2].ReferenceID)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
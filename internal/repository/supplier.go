@@ -0,0 +1,322 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type SupplierRepository interface {
+	BeginTransaction() (Transaction, error)
+	CreateSupplier(ctx context.Context, supplier *models.Supplier) error
+	GetSupplierByID(ctx context.Context, supplierID int) (*models.Supplier, error)
+	ListSuppliers(ctx context.Context) ([]models.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *models.Supplier) error
+	DeleteSupplier(ctx context.Context, supplierID int) error
+	SetSupplierIngredient(ctx context.Context, tx Transaction, item models.SupplierIngredient) error
+	RemoveSupplierIngredient(ctx context.Context, supplierID int, ingredientID int) error
+	ListSupplierIngredients(ctx context.Context, tx Transaction, supplierID int) ([]models.SupplierIngredient, error)
+	ListIngredientSuppliers(ctx context.Context, ingredientID int) ([]models.SupplierIngredient, error)
+}
+
+type supplierRepository struct {
+	db *sql.DB
+}
+
+func NewSupplierRepository(db *sql.DB) SupplierRepository {
+	return &supplierRepository{db: db}
+}
+
+var _ SupplierRepository = (*supplierRepository)(nil)
+
+func (r *supplierRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
+// supplierNameTaken reports a supplier name already in use, names are unique
+// regardless of case.
+func supplierNameTaken(name string) error {
+	return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Supplier already exists", fmt.Sprintf("A supplier named %q already exists", name))
+}
+
+// CreateSupplier stores a supplier, setting its generated ID and timestamp.
+func (r *supplierRepository) CreateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	query := `
+		INSERT INTO suppliers (name, contact_email, lead_time_days, minimum_order)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, supplier.Name, supplier.ContactEmail, supplier.LeadTimeDays, supplier.MinimumOrder).
+		Scan(&supplier.ID, &supplier.CreatedAt)
+	if err != nil {
+		if pgErrorCode(err) == pgUniqueViolation {
+			return supplierNameTaken(supplier.Name)
+		}
+		slog.Error("failed to create supplier", "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// GetSupplierByID fetches a supplier.
+func (r *supplierRepository) GetSupplierByID(ctx context.Context, supplierID int) (*models.Supplier, error) {
+	query := `
+		SELECT id, name, contact_email, lead_time_days, minimum_order, created_at
+		FROM suppliers
+		WHERE id = $1
+	`
+
+	var supplier models.Supplier
+	err := r.db.QueryRowContext(ctx, query, supplierID).Scan(
+		&supplier.ID,
+		&supplier.Name,
+		&supplier.ContactEmail,
+		&supplier.LeadTimeDays,
+		&supplier.MinimumOrder,
+		&supplier.CreatedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Supplier with ID %d not found", supplierID))
+		}
+		slog.Error("failed to retrieve supplier", "supplierID", supplierID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return &supplier, nil
+}
+
+// ListSuppliers returns every supplier by name.
+func (r *supplierRepository) ListSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	query := `
+		SELECT id, name, contact_email, lead_time_days, minimum_order, created_at
+		FROM suppliers
+		ORDER BY name, id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		slog.Error("failed to list suppliers", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	suppliers := []models.Supplier{}
+	for rows.Next() {
+		var supplier models.Supplier
+		if err := rows.Scan(
+			&supplier.ID,
+			&supplier.Name,
+			&supplier.ContactEmail,
+			&supplier.LeadTimeDays,
+			&supplier.MinimumOrder,
+			&supplier.CreatedAt,
+		); err != nil {
+			slog.Error("failed to list suppliers", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		suppliers = append(suppliers, supplier)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to list suppliers", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return suppliers, nil
+}
+
+// UpdateSupplier replaces the details of a supplier, setting its timestamp.
+func (r *supplierRepository) UpdateSupplier(ctx context.Context, supplier *models.Supplier) error {
+	query := `
+		UPDATE suppliers
+		SET name = $1, contact_email = $2, lead_time_days = $3, minimum_order = $4
+		WHERE id = $5
+		RETURNING created_at
+	`
+
+	err := r.db.QueryRowContext(ctx, query, supplier.Name, supplier.ContactEmail, supplier.LeadTimeDays, supplier.MinimumOrder, supplier.ID).
+		Scan(&supplier.CreatedAt)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Supplier with ID %d not found", supplier.ID))
+		}
+		if pgErrorCode(err) == pgUniqueViolation {
+			return supplierNameTaken(supplier.Name)
+		}
+		slog.Error("failed to update supplier", "supplierID", supplier.ID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// DeleteSupplier removes a supplier together with its price list.
+func (r *supplierRepository) DeleteSupplier(ctx context.Context, supplierID int) error {
+	query := `DELETE FROM suppliers WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, supplierID)
+	if err != nil {
+		slog.Error("failed to delete supplier", "supplierID", supplierID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to delete supplier", "supplierID", supplierID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Supplier with ID %d not found", supplierID))
+	}
+
+	return nil
+}
+
+// SetSupplierIngredient adds an ingredient to the price list of a supplier or
+// replaces its offer. A preferred offer takes the preference away from the
+// other suppliers of the ingredient.
+func (r *supplierRepository) SetSupplierIngredient(ctx context.Context, tx Transaction, item models.SupplierIngredient) error {
+	if item.Preferred {
+		query := `
+			UPDATE supplier_ingredients
+			SET preferred = false
+			WHERE ingredient_id = $1 AND supplier_id <> $2 AND preferred
+		`
+
+		if _, err := tx.ExecContext(ctx, query, item.IngredientID, item.SupplierID); err != nil {
+			slog.Error("failed to clear preferred supplier", "ingredientID", item.IngredientID, "error", err)
+			return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+	}
+
+	query := `
+		INSERT INTO supplier_ingredients (supplier_id, ingredient_id, pack_size, unit_price, preferred)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (supplier_id, ingredient_id) DO UPDATE
+		SET pack_size = EXCLUDED.pack_size, unit_price = EXCLUDED.unit_price, preferred = EXCLUDED.preferred
+	`
+
+	_, err := tx.ExecContext(ctx, query, item.SupplierID, item.IngredientID, item.PackSize, item.UnitPrice, item.Preferred)
+	if err != nil {
+		switch pgErrorCode(err) {
+		case pgForeignKeyViolation:
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", item.IngredientID))
+		case pgUniqueViolation:
+			// Another supplier was made preferred concurrently
+			return internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Preferred supplier changed", fmt.Sprintf("The preferred supplier of ingredient %d changed concurrently", item.IngredientID))
+		}
+		slog.Error("failed to set supplier ingredient", "supplierID", item.SupplierID, "ingredientID", item.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// RemoveSupplierIngredient takes an ingredient off the price list of a supplier.
+func (r *supplierRepository) RemoveSupplierIngredient(ctx context.Context, supplierID int, ingredientID int) error {
+	query := `DELETE FROM supplier_ingredients WHERE supplier_id = $1 AND ingredient_id = $2`
+
+	result, err := r.db.ExecContext(ctx, query, supplierID, ingredientID)
+	if err != nil {
+		slog.Error("failed to remove supplier ingredient", "supplierID", supplierID, "ingredientID", ingredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		slog.Error("failed to remove supplier ingredient", "supplierID", supplierID, "ingredientID", ingredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	if rowsAffected == 0 {
+		return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d is not supplied by supplier %d", ingredientID, supplierID))
+	}
+
+	return nil
+}
+
+// supplierIngredientQuery selects price list entries in the order
+// scanSupplierIngredients reads them.
+const supplierIngredientQuery = `
+	SELECT si.supplier_id, s.name, si.ingredient_id, i.name, si.pack_size, si.unit_price, si.preferred
+	FROM supplier_ingredients si
+	JOIN suppliers s ON s.id = si.supplier_id
+	JOIN ingredients i ON i.id = si.ingredient_id
+`
+
+// ListSupplierIngredients returns the price list of a supplier by ingredient
+// name, within tx when given.
+func (r *supplierRepository) ListSupplierIngredients(ctx context.Context, tx Transaction, supplierID int) ([]models.SupplierIngredient, error) {
+	query := supplierIngredientQuery + `
+		WHERE si.supplier_id = $1
+		ORDER BY i.name, si.ingredient_id
+	`
+
+	var rows *sql.Rows
+	var err error
+	if tx != nil {
+		rows, err = tx.QueryContext(ctx, query, supplierID)
+	} else {
+		rows, err = r.db.QueryContext(ctx, query, supplierID)
+	}
+	if err != nil {
+		slog.Error("failed to list supplier ingredients", "supplierID", supplierID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return scanSupplierIngredients(rows)
+}
+
+// ListIngredientSuppliers returns the offers for an ingredient, the preferred
+// supplier first and then the cheapest.
+func (r *supplierRepository) ListIngredientSuppliers(ctx context.Context, ingredientID int) ([]models.SupplierIngredient, error) {
+	query := supplierIngredientQuery + `
+		WHERE si.ingredient_id = $1
+		ORDER BY si.preferred DESC, si.unit_price, si.supplier_id
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, ingredientID)
+	if err != nil {
+		slog.Error("failed to list ingredient suppliers", "ingredientID", ingredientID, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return scanSupplierIngredients(rows)
+}
+
+func scanSupplierIngredients(rows *sql.Rows) ([]models.SupplierIngredient, error) {
+	defer rows.Close()
+
+	items := []models.SupplierIngredient{}
+	for rows.Next() {
+		var item models.SupplierIngredient
+		if err := rows.Scan(
+			&item.SupplierID,
+			&item.SupplierName,
+			&item.IngredientID,
+			&item.IngredientName,
+			&item.PackSize,
+			&item.UnitPrice,
+			&item.Preferred,
+		); err != nil {
+			slog.Error("failed to retrieve supplier ingredient", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+		items = append(items, item)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to retrieve supplier ingredient", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return items, nil
+}
@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSupplierRepository_CreateSupplier(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewSupplierRepository(db)
+
+	createdAt := time.Now()
+
+	// Mock the insert, the second supplier repeats the name of the first one
+	mock.ExpectQuery(`INSERT INTO suppliers \(name, contact_email, lead_time_days, minimum_order\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id, created_at`).
+		WithArgs("Green Farms", "orders@greenfarms.test", 2, 150.0).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "created_at"}).AddRow(5, createdAt))
+	mock.ExpectQuery(`INSERT INTO suppliers`).
+		WithArgs("green farms", "sales@greenfarms.test", 3, 0.0).
+		WillReturnError(&pgconn.PgError{Code: pgUniqueViolation})
+
+	// Call the method under test
+	supplier := &models.Supplier{Name: "Green Farms", ContactEmail: "orders@greenfarms.test", LeadTimeDays: 2, MinimumOrder: 150}
+	err = repo.CreateSupplier(context.Background(), supplier)
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, 5, supplier.ID)
+	assert.Equal(t, createdAt, supplier.CreatedAt)
+
+	err = repo.CreateSupplier(context.Background(), &models.Supplier{Name: "green farms", ContactEmail: "sales@greenfarms.test", LeadTimeDays: 3})
+
+	var appErr *internalErrors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, internalErrors.ErrCodeConflict, appErr.Code)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSupplierRepository_UpdateSupplier_NotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewSupplierRepository(db)
+
+	// Mock the update matching no supplier
+	mock.ExpectQuery(`UPDATE suppliers SET name = \$1, contact_email = \$2, lead_time_days = \$3, minimum_order = \$4 WHERE id = \$5 RETURNING created_at`).
+		WithArgs("Green Farms", "orders@greenfarms.test", 2, 150.0, 99).
+		WillReturnRows(sqlmock.NewRows([]string{"created_at"}))
+
+	// Call the method under test
+	err = repo.UpdateSupplier(context.Background(), &models.Supplier{ID: 99, Name: "Green Farms", ContactEmail: "orders@greenfarms.test", LeadTimeDays: 2, MinimumOrder: 150})
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	if assert.ErrorAs(t, err, &appErr) {
+		assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSupplierRepository_SetSupplierIngredient_Preferred(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewSupplierRepository(db)
+
+	mock.ExpectBegin()
+
+	// Mock the preference being taken away from the other suppliers
+	mock.ExpectExec(`UPDATE supplier_ingredients SET preferred = false WHERE ingredient_id = \$1 AND supplier_id <> \$2 AND preferred`).
+		WithArgs(1, 5).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	// Mock the upsert of the offer
+	mock.ExpectExec(`INSERT INTO supplier_ingredients \(supplier_id, ingredient_id, pack_size, unit_price, preferred\) VALUES \(\$1, \$2, \$3, \$4, \$5\) ON CONFLICT \(supplier_id, ingredient_id\) DO UPDATE`).
+		WithArgs(5, 1, 5000.0, 0.012, true).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	mock.ExpectCommit()
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.SetSupplierIngredient(context.Background(), tx, models.SupplierIngredient{
+		SupplierID:   5,
+		IngredientID: 1,
+		PackSize:     5000,
+		UnitPrice:    0.012,
+		Preferred:    true,
+	})
+
+	// Assertions
+	assert.NoError(t, err)
+
+	err = tx.Commit()
+	if err != nil {
+		t.Fatalf("Failed to commit transaction: %v", err)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestSupplierRepository_ListIngredientSuppliers(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewSupplierRepository(db)
+
+	// Mock the offers query, preferred supplier first
+	mock.ExpectQuery(`SELECT si.supplier_id, s.name, si.ingredient_id, i.name, si.pack_size, si.unit_price, si.preferred FROM supplier_ingredients si .+ WHERE si.ingredient_id = \$1 ORDER BY si.preferred DESC, si.unit_price, si.supplier_id`).
+		WithArgs(1).
+		WillReturnRows(sqlmock.NewRows([]string{"supplier_id", "supplier_name", "ingredient_id", "ingredient_name", "pack_size", "unit_price", "preferred"}).
+			AddRow(5, "Green Farms", 1, "Beef", 5000.0, 0.012, true).
+			AddRow(8, "Metro", 1, "Beef", 1000.0, 0.011, false))
+
+	// Call the method under test
+	items, err := repo.ListIngredientSuppliers(context.Background(), 1)
+
+	// Assertions
+	assert.NoError(t, err)
+	if assert.Len(t, items, 2) {
+		assert.Equal(t, "Green Farms", items[0].SupplierName)
+		assert.True(t, items[0].Preferred)
+		assert.Equal(t, 0.011, items[1].UnitPrice)
+	}
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -2,7 +2,6 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log/slog"
 	"stockk/internal/models"
@@ -10,15 +9,33 @@ import (
 	"github.com/hibiken/asynq"
 )
 
-const TaskSendAlertEmail = "task:send_alert_email"
+const (
+	TaskSendAlertEmail       = "task:send_alert_email"
+	TaskScanExpiringLots     = "task:scan_expiring_lots"
+	TaskSendExpiryAlertEmail = "task:send_expiry_alert_email"
+	TaskPurgeIdempotencyKeys = "task:purge_idempotency_keys"
+)
 
 type PayloadSendAlertEmail struct {
 	Ingredients []models.Ingredient `json:"ingredients"`
 }
 
+// PayloadScanExpiringLots configures the periodic scan for lots expiring
+// within WindowDays days.
+type PayloadScanExpiringLots struct {
+	WindowDays int `json:"window_days"`
+}
+
+// PayloadSendExpiryAlertEmail lists the lots a scan claimed for its window.
+type PayloadSendExpiryAlertEmail struct {
+	WindowDays int                    `json:"window_days"`
+	Lots       []models.IngredientLot `json:"lots"`
+}
+
 type TaskQueueRepository interface {
-	EnqueueAlertEmailTask(ctx context.Context,
-		payload *PayloadSendAlertEmail,
+	EnqueueTask(ctx context.Context,
+		taskType string,
+		payload []byte,
 		opts ...asynq.Option,
 	) error
 }
@@ -32,15 +49,13 @@ func NewTaskQueueRepository(client *asynq.Client) TaskQueueRepository {
 
 var _ TaskQueueRepository = (*taskQueueRepository)(nil)
 
-func (r *taskQueueRepository) EnqueueAlertEmailTask(ctx context.Context,
-	payload *PayloadSendAlertEmail,
+// EnqueueTask enqueues a task whose payload is already encoded.
+func (r *taskQueueRepository) EnqueueTask(ctx context.Context,
+	taskType string,
+	payload []byte,
 	opts ...asynq.Option,
 ) error {
-	jsonPayload, err := json.Marshal(payload)
-	if err != nil {
-		return fmt.Errorf("failed to marshal task payload: %w", err)
-	}
-	task := asynq.NewTask(TaskSendAlertEmail, jsonPayload, opts...)
+	task := asynq.NewTask(taskType, payload, opts...)
 	info, err := r.client.EnqueueContext(ctx, task)
 	if err != nil {
 		return fmt.Errorf("failed to enqueue task: %w", err)
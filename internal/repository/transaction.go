@@ -3,6 +3,9 @@ package repository
 import (
 	"context"
 	"database/sql"
+	"log/slog"
+
+	internalErrors "stockk/internal/errors"
 )
 
 type Transaction interface {
@@ -17,3 +20,13 @@ type Transaction interface {
 	Prepare(query string) (*sql.Stmt, error)
 	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
 }
+
+// beginTransaction starts a new database transaction shared by the repositories.
+func beginTransaction(db *sql.DB) (Transaction, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		slog.Error("error begin transation", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "transaction failed")
+	}
+	return tx, nil
+}
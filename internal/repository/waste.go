@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+type WasteRepository interface {
+	BeginTransaction() (Transaction, error)
+	CreateWasteEntry(ctx context.Context, tx Transaction, entry *models.WasteEntry) error
+	GetWasteEntryByID(ctx context.Context, entryID int) (*models.WasteEntry, error)
+	SumWaste(ctx context.Context, filter models.WasteReportFilter) ([]models.WasteReasonTotal, error)
+}
+
+type wasteRepository struct {
+	db *sql.DB
+}
+
+func NewWasteRepository(db *sql.DB) WasteRepository {
+	return &wasteRepository{db: db}
+}
+
+var _ WasteRepository = (*wasteRepository)(nil)
+
+func (r *wasteRepository) BeginTransaction() (Transaction, error) {
+	return beginTransaction(r.db)
+}
+
+// CreateWasteEntry stores a waste entry, setting its generated ID and timestamp.
+func (r *wasteRepository) CreateWasteEntry(ctx context.Context, tx Transaction, entry *models.WasteEntry) error {
+	query := `
+		INSERT INTO waste_entries (ingredient_id, quantity, reason, notes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, recorded_at
+	`
+
+	err := tx.QueryRowContext(ctx, query, entry.IngredientID, entry.Quantity, entry.Reason, entry.Notes).Scan(&entry.ID, &entry.RecordedAt)
+	if err != nil {
+		if pgErrorCode(err) == pgForeignKeyViolation {
+			return internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Ingredient with ID %d not found", entry.IngredientID))
+		}
+		slog.Error("failed to create waste entry", "ingredientID", entry.IngredientID, "error", err)
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return nil
+}
+
+// GetWasteEntryByID fetches a waste entry.
+func (r *wasteRepository) GetWasteEntryByID(ctx context.Context, entryID int) (*models.WasteEntry, error) {
+	query := `
+		SELECT id, ingredient_id, quantity, reason, notes, recorded_at
+		FROM waste_entries
+		WHERE id = $1
+	`
+
+	var entry models.WasteEntry
+	err := r.db.QueryRowContext(ctx, query, entryID).Scan(
+		&entry.ID,
+		&entry.IngredientID,
+		&entry.Quantity,
+		&entry.Reason,
+		&entry.Notes,
+		&entry.RecordedAt,
+	)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Waste entry with ID %d not found", entryID))
+		}
+		slog.Error("failed to retrieve waste entry", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return &entry, nil
+}
+
+// SumWaste adds up the waste recorded within filter per reason and
+// ingredient, sorted by reason and ingredient ID.
+func (r *wasteRepository) SumWaste(ctx context.Context, filter models.WasteReportFilter) ([]models.WasteReasonTotal, error) {
+	var conditions []string
+	var args []interface{}
+
+	addCondition := func(condition string, arg interface{}) {
+		args = append(args, arg)
+		conditions = append(conditions, fmt.Sprintf(condition, len(args)))
+	}
+
+	if filter.From != nil {
+		addCondition("w.recorded_at >= $%d", *filter.From)
+	}
+	if filter.To != nil {
+		addCondition("w.recorded_at < $%d", *filter.To)
+	}
+
+	where := ""
+	if len(conditions) > 0 {
+		where = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT w.reason, w.ingredient_id, i.name, i.unit, SUM(w.quantity), COUNT(*)
+		FROM waste_entries w
+		JOIN ingredients i ON i.id = w.ingredient_id
+		%s
+		GROUP BY w.reason, w.ingredient_id, i.name, i.unit
+		ORDER BY w.reason, w.ingredient_id
+	`, where)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		slog.Error("failed to sum waste", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+	defer rows.Close()
+
+	totals := []models.WasteReasonTotal{}
+	for rows.Next() {
+		var reason string
+		var ingredient models.WasteIngredientTotal
+		if err := rows.Scan(&reason, &ingredient.IngredientID, &ingredient.IngredientName, &ingredient.Unit, &ingredient.Quantity, &ingredient.Entries); err != nil {
+			slog.Error("failed to sum waste", "error", err)
+			return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+		}
+
+		// Rows are sorted by reason, start a new total when it changes
+		if len(totals) == 0 || totals[len(totals)-1].Reason != reason {
+			totals = append(totals, models.WasteReasonTotal{Reason: reason, Ingredients: []models.WasteIngredientTotal{}})
+		}
+		total := &totals[len(totals)-1]
+		total.Entries += ingredient.Entries
+		total.Ingredients = append(total.Ingredients, ingredient)
+	}
+
+	if err := rows.Err(); err != nil {
+		slog.Error("failed to sum waste", "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "query failed")
+	}
+
+	return totals, nil
+}
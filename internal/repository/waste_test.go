@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWasteRepository_CreateWasteEntry_IngredientNotFound(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewWasteRepository(db)
+
+	mock.ExpectBegin()
+
+	// Mock the insert referencing an unknown ingredient
+	mock.ExpectQuery(`INSERT INTO waste_entries \(ingredient_id, quantity, reason, notes\) VALUES \(\$1, \$2, \$3, \$4\) RETURNING id, recorded_at`).
+		WithArgs(99, 500.0, models.WasteReasonDropped, "").
+		WillReturnError(&pgconn.PgError{Code: pgForeignKeyViolation})
+
+	tx, err := repo.BeginTransaction()
+	if err != nil {
+		t.Fatalf("Failed to begin transaction: %v", err)
+	}
+
+	// Call the method under test
+	err = repo.CreateWasteEntry(context.Background(), tx, &models.WasteEntry{IngredientID: 99, Quantity: 500, Reason: models.WasteReasonDropped})
+
+	// Assertions
+	var appErr *internalErrors.AppError
+	assert.ErrorAs(t, err, &appErr)
+	assert.Equal(t, internalErrors.ErrCodeNotFound, appErr.Code)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
+
+func TestWasteRepository_SumWaste(t *testing.T) {
+	// Create a mock DB and mock objects
+	db, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("Failed to open mock database: %v", err)
+	}
+	defer db.Close()
+
+	// Create the repository
+	repo := NewWasteRepository(db)
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	// Mock the sums, grouped by reason then ingredient
+	mock.ExpectQuery(`SELECT w.reason, w.ingredient_id, i.name, i.unit, SUM\(w.quantity\), COUNT\(\*\) FROM waste_entries w JOIN ingredients i ON i.id = w.ingredient_id WHERE w.recorded_at >= \$1 AND w.recorded_at < \$2 GROUP BY .+ ORDER BY w.reason, w.ingredient_id`).
+		WithArgs(from, to).
+		WillReturnRows(sqlmock.NewRows([]string{"reason", "ingredient_id", "name", "unit", "quantity", "entries"}).
+			AddRow(models.WasteReasonDropped, 2, "Cheese", models.UnitGram, 500.0, 2).
+			AddRow(models.WasteReasonSpoiled, 1, "Beef", models.UnitGram, 1200.0, 1).
+			AddRow(models.WasteReasonSpoiled, 3, "Milk", models.UnitMilliliter, 2000.0, 3))
+
+	// Call the method under test
+	totals, err := repo.SumWaste(context.Background(), models.WasteReportFilter{From: &from, To: &to})
+
+	// Assertions
+	assert.NoError(t, err)
+	assert.Equal(t, []models.WasteReasonTotal{
+		{Reason: models.WasteReasonDropped, Entries: 2, Ingredients: []models.WasteIngredientTotal{
+			{IngredientID: 2, IngredientName: "Cheese", Unit: models.UnitGram, Quantity: 500, Entries: 2},
+		}},
+		{Reason: models.WasteReasonSpoiled, Entries: 4, Ingredients: []models.WasteIngredientTotal{
+			{IngredientID: 1, IngredientName: "Beef", Unit: models.UnitGram, Quantity: 1200, Entries: 1},
+			{IngredientID: 3, IngredientName: "Milk", Unit: models.UnitMilliliter, Quantity: 2000, Entries: 3},
+		}},
+	}, totals)
+
+	// Ensure that all expectations were met
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("There were unfulfilled expectations: %v", err)
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+)
+
+// queueLowStockAlert claims the low stock alerts of the ingredients whose stock
+// tx changed and stores the alert email task in the outbox, so the alert is
+// published if and only if tx commits.
+func queueLowStockAlert(ctx context.Context, tx repository.Transaction, ingredientRepo repository.IngredientRepository, outboxRepo repository.OutboxRepository, ingredientIDs []int) error {
+	ingredients, err := ingredientRepo.ClaimLowStockAlerts(ctx, tx, ingredientIDs)
+	if err != nil {
+		return err
+	}
+	if len(ingredients) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(&repository.PayloadSendAlertEmail{Ingredients: ingredients})
+	if err != nil {
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to encode alert email task")
+	}
+
+	return outboxRepo.AddMessage(ctx, tx, &models.OutboxMessage{
+		TaskType: repository.TaskSendAlertEmail,
+		Payload:  payload,
+	})
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestQueueLowStockAlert(t *testing.T) {
+	testCases := []struct {
+		name        string
+		buildStubs  func(ingredientRepo *mockrepository.MockIngredientRepository, outboxRepo *mockrepository.MockOutboxRepository, tx *mockrepository.MockTransaction)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "Nothing Became Low",
+			buildStubs: func(ingredientRepo *mockrepository.MockIngredientRepository, outboxRepo *mockrepository.MockOutboxRepository, tx *mockrepository.MockTransaction) {
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 3}).Return([]models.Ingredient{}, nil)
+				outboxRepo.EXPECT().AddMessage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Claim Error",
+			buildStubs: func(ingredientRepo *mockrepository.MockIngredientRepository, outboxRepo *mockrepository.MockOutboxRepository, tx *mockrepository.MockTransaction) {
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 3}).Return(nil, errors.New("error"))
+				outboxRepo.EXPECT().AddMessage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "Claimed Alert Queued In Outbox",
+			buildStubs: func(ingredientRepo *mockrepository.MockIngredientRepository, outboxRepo *mockrepository.MockOutboxRepository, tx *mockrepository.MockTransaction) {
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 3}).Return([]models.Ingredient{{ID: 3, Name: "Onion"}}, nil)
+				outboxRepo.EXPECT().AddMessage(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, message *models.OutboxMessage) error {
+						var payload repository.PayloadSendAlertEmail
+						if err := json.Unmarshal(message.Payload, &payload); err != nil {
+							t.Fatalf("failed to decode outbox payload: %v", err)
+						}
+						if message.TaskType != repository.TaskSendAlertEmail || len(payload.Ingredients) != 1 || payload.Ingredients[0].ID != 3 {
+							t.Errorf("unexpected outbox message %s %s", message.TaskType, message.Payload)
+						}
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Outbox Error",
+			buildStubs: func(ingredientRepo *mockrepository.MockIngredientRepository, outboxRepo *mockrepository.MockOutboxRepository, tx *mockrepository.MockTransaction) {
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 3}).Return([]models.Ingredient{{ID: 3}}, nil)
+				outboxRepo.EXPECT().AddMessage(gomock.Any(), tx, gomock.Any()).Return(errors.New("error"))
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(ingredientRepo, outboxRepo, tx)
+
+			err := queueLowStockAlert(context.Background(), tx, ingredientRepo, outboxRepo, []int{1, 3})
+			tc.checkResult(t, err)
+		})
+	}
+}
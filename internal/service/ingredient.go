@@ -2,47 +2,368 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"log/slog"
 	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
 	"stockk/internal/repository"
+	"strconv"
+	"strings"
+	"time"
 )
 
 type IngredientService interface {
 	UpdateIngredientStock(ctx context.Context, ingredients []models.Ingredient) error
-	CheckIngredientLevelsAndAlert(ctx context.Context) error
+	ListIngredients(ctx context.Context) ([]models.Ingredient, error)
+	GetIngredient(ctx context.Context, ingredientID int) (*models.Ingredient, error)
+	CreateIngredient(ctx context.Context, ingredient *models.Ingredient) (*models.Ingredient, error)
+	RenameIngredient(ctx context.Context, ingredientID int, name string) (*models.Ingredient, error)
+	UpdateTotalStock(ctx context.Context, ingredientID int, totalStock float64) (*models.Ingredient, error)
+	ArchiveIngredient(ctx context.Context, ingredientID int) error
+	SetLowStockThreshold(ctx context.Context, ingredientID int, threshold *models.LowStockThreshold) (*models.Ingredient, error)
+	SetYieldPercent(ctx context.Context, ingredientID int, yieldPercent *float64) (*models.Ingredient, error)
+	ListStockMovements(ctx context.Context, filter models.StockMovementFilter) (*models.StockMovementPage, error)
+	CreatePack(ctx context.Context, pack *models.IngredientPack) (*models.IngredientPack, error)
+	ListPacks(ctx context.Context, ingredientID int) ([]models.IngredientPack, error)
+	DeletePack(ctx context.Context, ingredientID int, packID int) error
+	SetPrepRecipe(ctx context.Context, recipe *models.PrepRecipe) (*models.PrepRecipe, error)
+	GetPrepRecipe(ctx context.Context, ingredientID int) (*models.PrepRecipe, error)
+	DeletePrepRecipe(ctx context.Context, ingredientID int) error
+	ListLots(ctx context.Context, ingredientID int) ([]models.IngredientLot, error)
+	ListExpiringLots(ctx context.Context, days int) ([]models.IngredientLot, error)
 }
 type ingredientService struct {
 	ingredientRepo repository.IngredientRepository
-	taskRepo       repository.TaskQueueRepository
+	movementRepo   repository.StockMovementRepository
+	outboxRepo     repository.OutboxRepository
+	packRepo       repository.IngredientPackRepository
+	prepRepo       repository.PrepRecipeRepository
 }
 
-func NewIngredientService(ingredientRepo repository.IngredientRepository, taskRepo repository.TaskQueueRepository) IngredientService {
-	return &ingredientService{ingredientRepo: ingredientRepo, taskRepo: taskRepo}
+func NewIngredientService(ingredientRepo repository.IngredientRepository, movementRepo repository.StockMovementRepository, outboxRepo repository.OutboxRepository, packRepo repository.IngredientPackRepository, prepRepo repository.PrepRecipeRepository) IngredientService {
+	return &ingredientService{ingredientRepo: ingredientRepo, movementRepo: movementRepo, outboxRepo: outboxRepo, packRepo: packRepo, prepRepo: prepRepo}
 }
 
 var _ IngredientService = (*ingredientService)(nil)
 
-func (is *ingredientService) UpdateIngredientStock(ctx context.Context, ingredients []models.Ingredient) error {
-	for _, ingredient := range ingredients {
-		// Update stock in database
-		if err := is.ingredientRepo.UpdateStock(ctx, nil, ingredient.ID, ingredient.CurrentStock); err != nil {
+// UpdateIngredientStock manually sets the current stock of ingredients,
+// recording the difference as an adjustment in the stock ledger.
+func (is *ingredientService) UpdateIngredientStock(ctx context.Context, ingredients []models.Ingredient) (err error) {
+	tx, err := is.ingredientRepo.BeginTransaction()
+	if err != nil {
+		return internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	ingredientIDs := make([]int, len(ingredients))
+	for i, ingredient := range ingredients {
+		if err = is.adjustStock(ctx, tx, ingredient.ID, ingredient.CurrentStock); err != nil {
 			return err
 		}
+		ingredientIDs[i] = ingredient.ID
 	}
-	return nil
+
+	if err = queueLowStockAlert(ctx, tx, is.ingredientRepo, is.outboxRepo, ingredientIDs); err != nil {
+		return err
+	}
+
+	return tx.Commit()
 }
 
-func (is *ingredientService) CheckIngredientLevelsAndAlert(ctx context.Context) error {
-	ingredients, err := is.ingredientRepo.CheckLowStockIngredients(ctx)
+// adjustStock sets the stock of an ingredient, recording the change from the
+// stock it had under the row lock so that the ledger keeps adding up to the
+// current stock while orders run concurrently.
+func (is *ingredientService) adjustStock(ctx context.Context, tx repository.Transaction, ingredientID int, newStock float64) error {
+	previousStock, err := is.ingredientRepo.UpdateStock(ctx, tx, ingredientID, newStock)
 	if err != nil {
-		return internalErrors.Wrap(internalErrors.ErrInternalServer, "ingredient service: failed to retrieve low stock ingredients")
+		return err
+	}
+
+	delta := newStock - previousStock
+	if delta == 0 {
+		return nil
 	}
+	return is.movementRepo.RecordMovement(ctx, tx, &models.StockMovement{
+		IngredientID: ingredientID,
+		Delta:        delta,
+		Reason:       models.MovementReasonAdjustment,
+	})
+}
 
-	if len(ingredients) > 0 {
-		if err := is.taskRepo.EnqueueAlertEmailTask(ctx, &repository.PayloadSendAlertEmail{Ingredients: ingredients}); err != nil {
-			return internalErrors.Wrap(internalErrors.ErrInternalServer, "ingredient service: failed to enqueue alert email task")
+func (is *ingredientService) ListIngredients(ctx context.Context) ([]models.Ingredient, error) {
+	return is.ingredientRepo.ListIngredients(ctx)
+}
+
+func (is *ingredientService) GetIngredient(ctx context.Context, ingredientID int) (*models.Ingredient, error) {
+	return is.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID)
+}
 
+// CreateIngredient stores a new ingredient, opening its stock ledger with the
+// initial stock as an adjustment.
+func (is *ingredientService) CreateIngredient(ctx context.Context, ingredient *models.Ingredient) (created *models.Ingredient, err error) {
+	ingredient.Name = strings.TrimSpace(ingredient.Name)
+
+	tx, err := is.ingredientRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	if err = is.ingredientRepo.CreateIngredient(ctx, tx, ingredient); err != nil {
+		return nil, err
+	}
+
+	if ingredient.CurrentStock != 0 {
+		if err = is.movementRepo.RecordMovement(ctx, tx, &models.StockMovement{
+			IngredientID: ingredient.ID,
+			Delta:        ingredient.CurrentStock,
+			Reason:       models.MovementReasonAdjustment,
+		}); err != nil {
+			return nil, err
 		}
 	}
+
+	// Read it back to resolve the effective low stock threshold
+	created, err = is.ingredientRepo.GetIngredientByID(ctx, tx, ingredient.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return created, nil
+}
+
+func (is *ingredientService) RenameIngredient(ctx context.Context, ingredientID int, name string) (*models.Ingredient, error) {
+	if err := is.ingredientRepo.RenameIngredient(ctx, ingredientID, strings.TrimSpace(name)); err != nil {
+		return nil, err
+	}
+	return is.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID)
+}
+
+func (is *ingredientService) UpdateTotalStock(ctx context.Context, ingredientID int, totalStock float64) (*models.Ingredient, error) {
+	if err := is.ingredientRepo.UpdateTotalStock(ctx, ingredientID, totalStock); err != nil {
+		return nil, err
+	}
+	return is.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID)
+}
+
+func (is *ingredientService) ArchiveIngredient(ctx context.Context, ingredientID int) error {
+	return is.ingredientRepo.ArchiveIngredient(ctx, ingredientID)
+}
+
+// SetLowStockThreshold changes the low stock threshold of an ingredient, a nil
+// threshold reverts to the configured default. The low stock alert is re-armed
+// when the ingredient is no longer low under the new threshold.
+func (is *ingredientService) SetLowStockThreshold(ctx context.Context, ingredientID int, threshold *models.LowStockThreshold) (*models.Ingredient, error) {
+	if err := is.ingredientRepo.SetLowStockThreshold(ctx, ingredientID, threshold); err != nil {
+		return nil, err
+	}
+	if err := is.ingredientRepo.ResetAlertIfReplenished(ctx, nil, ingredientID); err != nil {
+		return nil, err
+	}
+	return is.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID)
+}
+
+// SetYieldPercent changes the share of an ingredient recipes get out of the
+// stock they take, a nil yield means nothing is lost. Recipe lines with a
+// yield of their own keep it.
+func (is *ingredientService) SetYieldPercent(ctx context.Context, ingredientID int, yieldPercent *float64) (*models.Ingredient, error) {
+	if err := is.ingredientRepo.SetYieldPercent(ctx, ingredientID, yieldPercent); err != nil {
+		return nil, err
+	}
+	return is.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID)
+}
+
+// ListStockMovements returns a page of the stock ledger of an ingredient and
+// the cursor of the next page, if any.
+func (is *ingredientService) ListStockMovements(ctx context.Context, filter models.StockMovementFilter) (*models.StockMovementPage, error) {
+	// Archived ingredients keep their history, only unknown ones are rejected
+	if _, err := is.ingredientRepo.GetIngredientByID(ctx, nil, filter.IngredientID); err != nil {
+		return nil, err
+	}
+
+	pageSize := filter.Limit
+
+	// Fetch one extra movement to find out whether another page follows
+	filter.Limit = pageSize + 1
+	movements, err := is.movementRepo.ListMovements(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.StockMovementPage{Movements: movements}
+	if len(movements) > pageSize {
+		page.Movements = movements[:pageSize]
+		nextCursor := page.Movements[pageSize-1].ID
+		page.NextCursor = &nextCursor
+	}
+
+	return page, nil
+}
+
+// CreatePack defines a pack an active ingredient is purchased in.
+func (is *ingredientService) CreatePack(ctx context.Context, pack *models.IngredientPack) (*models.IngredientPack, error) {
+	ingredient, err := is.ingredientRepo.GetIngredientByID(ctx, nil, pack.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+	if ingredient.ArchivedAt != nil {
+		return nil, internalErrors.NewAppError(
+			internalErrors.ErrCodeConflict,
+			"Ingredient archived",
+			fmt.Sprintf("Ingredient with ID %d is archived", pack.IngredientID),
+		)
+	}
+
+	pack.Label = strings.TrimSpace(pack.Label)
+	if err := is.packRepo.CreatePack(ctx, pack); err != nil {
+		return nil, err
+	}
+	return pack, nil
+}
+
+// ListLots returns the lots of an ingredient with stock left, in the order
+// they are consumed.
+func (is *ingredientService) ListLots(ctx context.Context, ingredientID int) ([]models.IngredientLot, error) {
+	if _, err := is.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID); err != nil {
+		return nil, err
+	}
+	return is.ingredientRepo.ListLots(ctx, ingredientID)
+}
+
+// ListExpiringLots returns the lots with stock left expiring within the given
+// number of days, expired ones included, soonest first.
+func (is *ingredientService) ListExpiringLots(ctx context.Context, days int) ([]models.IngredientLot, error) {
+	return is.ingredientRepo.ListExpiringLots(ctx, time.Now().AddDate(0, 0, days))
+}
+
+// ListPacks returns the packs an ingredient is purchased in.
+func (is *ingredientService) ListPacks(ctx context.Context, ingredientID int) ([]models.IngredientPack, error) {
+	if _, err := is.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID); err != nil {
+		return nil, err
+	}
+	return is.packRepo.ListPacks(ctx, ingredientID)
+}
+
+func (is *ingredientService) DeletePack(ctx context.Context, ingredientID int, packID int) error {
+	return is.packRepo.DeletePack(ctx, ingredientID, packID)
+}
+
+// SetPrepRecipe turns an ingredient into a prep item made from other
+// ingredients, or replaces its recipe. Recipes that would make a prep item
+// part of its own ingredients, directly or through other prep items, are
+// rejected.
+func (is *ingredientService) SetPrepRecipe(ctx context.Context, recipe *models.PrepRecipe) (saved *models.PrepRecipe, err error) {
+	tx, err := is.prepRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	prep, err := is.ingredientRepo.GetIngredientByID(ctx, tx, recipe.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+	if prep.ArchivedAt != nil {
+		return nil, internalErrors.NewAppError(
+			internalErrors.ErrCodeConflict,
+			"Ingredient archived",
+			fmt.Sprintf("Ingredient with ID %d is archived", recipe.IngredientID),
+		)
+	}
+
+	for i := range recipe.Ingredients {
+		recipe.Ingredients[i].PrepID = recipe.IngredientID
+		if err = is.resolvePrepUnit(ctx, tx, &recipe.Ingredients[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = is.prepRepo.LockPrepRecipes(ctx, tx); err != nil {
+		return nil, err
+	}
+	recipes, err := is.prepRepo.ListPrepRecipes(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	index := indexPrepRecipes(recipes)
+	index[recipe.IngredientID] = *recipe
+	if cycle := findPrepCycle(index, recipe.IngredientID); cycle != nil {
+		steps := make([]string, len(cycle))
+		for i, ingredientID := range cycle {
+			steps[i] = strconv.Itoa(ingredientID)
+		}
+		return nil, internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Recipe cycle",
+			fmt.Sprintf("Prep items would be made from themselves: %s", strings.Join(steps, " -> ")),
+		)
+	}
+
+	if err = is.prepRepo.SavePrepRecipe(ctx, tx, recipe); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return is.prepRepo.GetPrepRecipe(ctx, recipe.IngredientID)
+}
+
+// resolvePrepUnit checks that a prep recipe line unit can be converted to the
+// base unit of its ingredient, a line without a unit uses the base unit.
+func (is *ingredientService) resolvePrepUnit(ctx context.Context, tx repository.Transaction, line *models.PrepIngredient) error {
+	ingredient, err := is.ingredientRepo.GetIngredientByID(ctx, tx, line.IngredientID)
+	if err != nil {
+		return err
+	}
+
+	if line.Unit == "" {
+		line.Unit = ingredient.Unit
+	}
+	line.IngredientUnit = ingredient.Unit
+
+	if _, err := line.BaseAmount(); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Incompatible unit",
+			fmt.Sprintf("%s is measured in %s, %s", ingredient.Name, ingredient.Unit, err),
+		)
+	}
+
 	return nil
 }
+
+func (is *ingredientService) GetPrepRecipe(ctx context.Context, ingredientID int) (*models.PrepRecipe, error) {
+	return is.prepRepo.GetPrepRecipe(ctx, ingredientID)
+}
+
+// DeletePrepRecipe turns a prep item back into a plain ingredient.
+func (is *ingredientService) DeletePrepRecipe(ctx context.Context, ingredientID int) error {
+	return is.prepRepo.DeletePrepRecipe(ctx, ingredientID)
+}
@@ -6,7 +6,9 @@ import (
 	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
 	mockrepository "stockk/internal/repository/mock"
+	"strings"
 	"testing"
+	"time"
 
 	"go.uber.org/mock/gomock"
 )
@@ -16,7 +18,7 @@ func TestUpdateIngredientStock(t *testing.T) {
 	testCases := []struct {
 		name         string
 		input        []models.Ingredient
-		buildStubs   func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository)
+		buildStubs   func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository, tx *mockrepository.MockTransaction)
 		buildContext func(t *testing.T) context.Context
 		checkResult  func(t *testing.T, err error)
 	}{
@@ -31,8 +33,19 @@ func TestUpdateIngredientStock(t *testing.T) {
 					AlertSent:    false,
 				},
 			},
-			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository) {
-				ingredientrepo.EXPECT().UpdateStock(gomock.Any(), nil, 1, float64(40)).Return(nil)
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository, tx *mockrepository.MockTransaction) {
+				ingredientrepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientrepo.EXPECT().UpdateStock(gomock.Any(), tx, 1, float64(40)).Return(float64(100), nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, movement *models.StockMovement) error {
+						if movement.Delta != -60 || movement.Reason != models.MovementReasonAdjustment {
+							t.Errorf("unexpected movement %+v", movement)
+						}
+						return nil
+					})
+				ingredientrepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1}).Return(nil, nil)
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
 			},
 			buildContext: func(t *testing.T) context.Context {
 				return context.Background()
@@ -54,8 +67,11 @@ func TestUpdateIngredientStock(t *testing.T) {
 					AlertSent:    false,
 				},
 			},
-			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository) {
-				ingredientrepo.EXPECT().UpdateStock(gomock.Any(), nil, 1, float64(40)).Return(errors.New("error"))
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository, tx *mockrepository.MockTransaction) {
+				ingredientrepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientrepo.EXPECT().UpdateStock(gomock.Any(), tx, 1, float64(40)).Return(float64(0), errors.New("error"))
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				tx.EXPECT().Rollback().Return(nil)
 			},
 			buildContext: func(t *testing.T) context.Context {
 				return context.Background()
@@ -77,8 +93,12 @@ func TestUpdateIngredientStock(t *testing.T) {
 					AlertSent:    false,
 				},
 			},
-			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository) {
-				ingredientrepo.EXPECT().UpdateStock(gomock.Any(), nil, 1, float64(40)).Return(internalErrors.ErrNotFound)
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository, tx *mockrepository.MockTransaction) {
+				ingredientrepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientrepo.EXPECT().UpdateStock(gomock.Any(), tx, 1, float64(40)).
+					Return(float64(0), internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found"))
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				tx.EXPECT().Rollback().Return(nil)
 			},
 			buildContext: func(t *testing.T) context.Context {
 				return context.Background()
@@ -96,10 +116,12 @@ func TestUpdateIngredientStock(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			ir := mockrepository.NewMockIngredientRepository(ctrl)
-			tr := mockrepository.NewMockTaskQueueRepository(ctrl)
+			mr := mockrepository.NewMockStockMovementRepository(ctrl)
+			or := mockrepository.NewMockOutboxRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
 
-			tc.buildStubs(ir, tr)
-			is := NewIngredientService(ir, tr)
+			tc.buildStubs(ir, mr, tx)
+			is := NewIngredientService(ir, mr, or, mockrepository.NewMockIngredientPackRepository(ctrl), mockrepository.NewMockPrepRecipeRepository(ctrl))
 
 			ctx := tc.buildContext(t)
 
@@ -109,67 +131,126 @@ func TestUpdateIngredientStock(t *testing.T) {
 	}
 }
 
-func TestCheckIngredientLevelsAndAlert(t *testing.T) {
+func TestCreateIngredient(t *testing.T) {
 
 	testCases := []struct {
 		name         string
-		buildStubs   func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository)
+		input        *models.Ingredient
+		buildStubs   func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository, tx *mockrepository.MockTransaction)
 		buildContext func(t *testing.T) context.Context
-		checkResult  func(t *testing.T, err error)
+		checkResult  func(t *testing.T, ingredient *models.Ingredient, err error)
 	}{
 		{
-			name: "Success Check",
-			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository) {
-				ingredientrepo.EXPECT().CheckLowStockIngredients(gomock.Any()).Return([]models.Ingredient{}, nil)
+			name:  "Success Create",
+			input: &models.Ingredient{Name: "  Tomato ", TotalStock: 3000, CurrentStock: 3000},
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository, tx *mockrepository.MockTransaction) {
+				ingredientrepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientrepo.EXPECT().CreateIngredient(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, ingredient *models.Ingredient) error {
+						if ingredient.Name != "Tomato" {
+							t.Errorf("expected trimmed name, got %q", ingredient.Name)
+						}
+						ingredient.ID = 4
+						return nil
+					})
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, movement *models.StockMovement) error {
+						if movement.IngredientID != 4 || movement.Delta != 3000 || movement.Reason != models.MovementReasonAdjustment {
+							t.Errorf("unexpected movement %+v", movement)
+						}
+						return nil
+					})
+				ingredientrepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 4).
+					Return(&models.Ingredient{ID: 4, Name: "Tomato"}, nil)
+				tx.EXPECT().Commit().Return(nil)
 			},
 			buildContext: func(t *testing.T) context.Context {
 				return context.Background()
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, ingredient *models.Ingredient, err error) {
 				if err != nil {
-					t.Errorf("expected no error, got %v", err)
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if ingredient.ID != 4 || ingredient.Name != "Tomato" {
+					t.Errorf("unexpected ingredient %+v", ingredient)
 				}
 			},
 		},
 		{
-			name: "Error Check",
-			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository) {
-				ingredientrepo.EXPECT().CheckLowStockIngredients(gomock.Any()).Return(nil, errors.New("error"))
+			name:  "Error Create",
+			input: &models.Ingredient{Name: "Tomato", TotalStock: 3000, CurrentStock: 3000},
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository, tx *mockrepository.MockTransaction) {
+				ingredientrepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientrepo.EXPECT().CreateIngredient(gomock.Any(), tx, gomock.Any()).Return(errors.New("error"))
+				tx.EXPECT().Rollback().Return(nil)
 			},
 			buildContext: func(t *testing.T) context.Context {
 				return context.Background()
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, ingredient *models.Ingredient, err error) {
 				if err == nil {
 					t.Errorf("expected error, got nil")
 				}
 			},
 		},
+	}
+
+	for _, tc := range testCases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ir := mockrepository.NewMockIngredientRepository(ctrl)
+			mr := mockrepository.NewMockStockMovementRepository(ctrl)
+			or := mockrepository.NewMockOutboxRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(ir, mr, tx)
+			is := NewIngredientService(ir, mr, or, mockrepository.NewMockIngredientPackRepository(ctrl), mockrepository.NewMockPrepRecipeRepository(ctrl))
+
+			ctx := tc.buildContext(t)
+
+			ingredient, err := is.CreateIngredient(ctx, tc.input)
+			tc.checkResult(t, ingredient, err)
+		})
+	}
+}
+
+func TestRenameIngredient(t *testing.T) {
+
+	testCases := []struct {
+		name         string
+		buildStubs   func(ingredientrepo *mockrepository.MockIngredientRepository)
+		buildContext func(t *testing.T) context.Context
+		checkResult  func(t *testing.T, ingredient *models.Ingredient, err error)
+	}{
 		{
-			name: "Success Check and Enqueue",
-			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository) {
-				ingredientrepo.EXPECT().CheckLowStockIngredients(gomock.Any()).Return([]models.Ingredient{{ID: 1}}, nil)
-				taskRepo.EXPECT().EnqueueAlertEmailTask(gomock.Any(), gomock.Any()).Return(nil)
+			name: "Success Rename",
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository) {
+				ingredientrepo.EXPECT().RenameIngredient(gomock.Any(), 1, "Minced Beef").Return(nil)
+				ingredientrepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 1).
+					Return(&models.Ingredient{ID: 1, Name: "Minced Beef"}, nil)
 			},
 			buildContext: func(t *testing.T) context.Context {
 				return context.Background()
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, ingredient *models.Ingredient, err error) {
 				if err != nil {
-					t.Errorf("expected no error, got %v", err)
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if ingredient.Name != "Minced Beef" {
+					t.Errorf("expected renamed ingredient, got %+v", ingredient)
 				}
 			},
 		},
 		{
-			name: "Error Check and Enqueue",
-			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, taskRepo *mockrepository.MockTaskQueueRepository) {
-				ingredientrepo.EXPECT().CheckLowStockIngredients(gomock.Any()).Return([]models.Ingredient{{ID: 1}}, nil)
-				taskRepo.EXPECT().EnqueueAlertEmailTask(gomock.Any(), gomock.Any()).Return(errors.New("error"))
+			name: "Error Not Found",
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository) {
+				ingredientrepo.EXPECT().RenameIngredient(gomock.Any(), 1, "Minced Beef").Return(internalErrors.ErrNotFound)
 			},
 			buildContext: func(t *testing.T) context.Context {
 				return context.Background()
 			},
-			checkResult: func(t *testing.T, err error) {
+			checkResult: func(t *testing.T, ingredient *models.Ingredient, err error) {
 				if err == nil {
 					t.Errorf("expected error, got nil")
 				}
@@ -182,15 +263,260 @@ func TestCheckIngredientLevelsAndAlert(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			ir := mockrepository.NewMockIngredientRepository(ctrl)
-			tr := mockrepository.NewMockTaskQueueRepository(ctrl)
+			mr := mockrepository.NewMockStockMovementRepository(ctrl)
+			or := mockrepository.NewMockOutboxRepository(ctrl)
 
-			tc.buildStubs(ir, tr)
-			is := NewIngredientService(ir, tr)
+			tc.buildStubs(ir)
+			is := NewIngredientService(ir, mr, or, mockrepository.NewMockIngredientPackRepository(ctrl), mockrepository.NewMockPrepRecipeRepository(ctrl))
 
 			ctx := tc.buildContext(t)
 
-			err := is.CheckIngredientLevelsAndAlert(ctx)
-			tc.checkResult(t, err)
+			ingredient, err := is.RenameIngredient(ctx, 1, " Minced Beef")
+			tc.checkResult(t, ingredient, err)
+		})
+	}
+}
+
+func TestSetLowStockThreshold(t *testing.T) {
+
+	threshold := &models.LowStockThreshold{Type: models.ThresholdTypeQuantity, Value: 500}
+
+	testCases := []struct {
+		name         string
+		buildStubs   func(ingredientrepo *mockrepository.MockIngredientRepository)
+		buildContext func(t *testing.T) context.Context
+		checkResult  func(t *testing.T, ingredient *models.Ingredient, err error)
+	}{
+		{
+			name: "Success Set Threshold",
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository) {
+				ingredientrepo.EXPECT().SetLowStockThreshold(gomock.Any(), 1, threshold).Return(nil)
+				ingredientrepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), nil, 1).Return(nil)
+				ingredientrepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 1).
+					Return(&models.Ingredient{ID: 1, LowStockThreshold: *threshold}, nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, ingredient *models.Ingredient, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if ingredient.LowStockThreshold != *threshold {
+					t.Errorf("unexpected threshold %+v", ingredient.LowStockThreshold)
+				}
+			},
+		},
+		{
+			name: "Error Not Found",
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository) {
+				ingredientrepo.EXPECT().SetLowStockThreshold(gomock.Any(), 1, threshold).Return(internalErrors.ErrNotFound)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, ingredient *models.Ingredient, err error) {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ir := mockrepository.NewMockIngredientRepository(ctrl)
+			mr := mockrepository.NewMockStockMovementRepository(ctrl)
+			or := mockrepository.NewMockOutboxRepository(ctrl)
+
+			tc.buildStubs(ir)
+			is := NewIngredientService(ir, mr, or, mockrepository.NewMockIngredientPackRepository(ctrl), mockrepository.NewMockPrepRecipeRepository(ctrl))
+
+			ctx := tc.buildContext(t)
+
+			ingredient, err := is.SetLowStockThreshold(ctx, 1, threshold)
+			tc.checkResult(t, ingredient, err)
+		})
+	}
+}
+
+func TestListStockMovements(t *testing.T) {
+	testCases := []struct {
+		name        string
+		buildStubs  func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository)
+		checkResult func(t *testing.T, page *models.StockMovementPage, err error)
+	}{
+		{
+			name: "Has Next Page",
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository) {
+				ingredientrepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 1).Return(&models.Ingredient{ID: 1}, nil)
+				movementRepo.EXPECT().ListMovements(gomock.Any(), models.StockMovementFilter{IngredientID: 1, Limit: 3}).
+					Return([]models.StockMovement{{ID: 9}, {ID: 8}, {ID: 5}}, nil)
+			},
+			checkResult: func(t *testing.T, page *models.StockMovementPage, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(page.Movements) != 2 {
+					t.Errorf("expected 2 movements, got %d", len(page.Movements))
+				}
+				if page.NextCursor == nil || *page.NextCursor != 8 {
+					t.Errorf("expected next cursor 8, got %v", page.NextCursor)
+				}
+			},
+		},
+		{
+			name: "Unknown Ingredient",
+			buildStubs: func(ingredientrepo *mockrepository.MockIngredientRepository, movementRepo *mockrepository.MockStockMovementRepository) {
+				ingredientrepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 1).
+					Return(nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found"))
+				movementRepo.EXPECT().ListMovements(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, page *models.StockMovementPage, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeNotFound {
+					t.Errorf("expected not found error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ir := mockrepository.NewMockIngredientRepository(ctrl)
+			mr := mockrepository.NewMockStockMovementRepository(ctrl)
+			or := mockrepository.NewMockOutboxRepository(ctrl)
+
+			tc.buildStubs(ir, mr)
+			is := NewIngredientService(ir, mr, or, mockrepository.NewMockIngredientPackRepository(ctrl), mockrepository.NewMockPrepRecipeRepository(ctrl))
+
+			page, err := is.ListStockMovements(context.Background(), models.StockMovementFilter{IngredientID: 1, Limit: 2})
+			tc.checkResult(t, page, err)
+		})
+	}
+}
+
+func TestSetPrepRecipe(t *testing.T) {
+	// Onion base (ingredient 5) is made from sauce (ingredient 4)
+	existing := []models.PrepRecipe{
+		{IngredientID: 5, Yield: 50, Ingredients: []models.PrepIngredient{
+			{PrepID: 5, IngredientID: 4, Amount: 100, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+		}},
+	}
+
+	testCases := []struct {
+		name       string
+		input      *models.PrepRecipe
+		buildStubs func(
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			prepRepo *mockrepository.MockPrepRecipeRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, recipe *models.PrepRecipe, err error)
+	}{
+		{
+			name: "Success Set Recipe",
+			input: &models.PrepRecipe{IngredientID: 4, Yield: 500, Ingredients: []models.PrepIngredient{
+				{IngredientID: 1, Amount: 0.4, Unit: "kg"},
+			}},
+			buildStubs: func(
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				prepRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 4).Return(&models.Ingredient{ID: 4, Unit: models.UnitGram}, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 1).Return(&models.Ingredient{ID: 1, Unit: models.UnitGram}, nil)
+				prepRepo.EXPECT().LockPrepRecipes(gomock.Any(), tx).Return(nil)
+				prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), tx).Return(existing, nil)
+				prepRepo.EXPECT().SavePrepRecipe(gomock.Any(), tx, &models.PrepRecipe{IngredientID: 4, Yield: 500, Ingredients: []models.PrepIngredient{
+					{PrepID: 4, IngredientID: 1, Amount: 0.4, Unit: "kg", IngredientUnit: models.UnitGram},
+				}}).Return(nil)
+				prepRepo.EXPECT().GetPrepRecipe(gomock.Any(), 4).Return(&models.PrepRecipe{IngredientID: 4, Yield: 500}, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, recipe *models.PrepRecipe, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if recipe.IngredientID != 4 {
+					t.Errorf("expected the recipe of ingredient 4, got %+v", recipe)
+				}
+			},
+		},
+		{
+			name: "Cycle Through Another Prep Rejected",
+			input: &models.PrepRecipe{IngredientID: 4, Yield: 500, Ingredients: []models.PrepIngredient{
+				{IngredientID: 5, Amount: 20},
+			}},
+			buildStubs: func(
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				prepRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 4).Return(&models.Ingredient{ID: 4, Unit: models.UnitGram}, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 5).Return(&models.Ingredient{ID: 5, Unit: models.UnitGram}, nil)
+				prepRepo.EXPECT().LockPrepRecipes(gomock.Any(), tx).Return(nil)
+				prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), tx).Return(existing, nil)
+				prepRepo.EXPECT().SavePrepRecipe(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, recipe *models.PrepRecipe, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Fatalf("expected validation error, got %v", err)
+				}
+				if !strings.Contains(appErr.Details, "4 -> 5 -> 4") {
+					t.Errorf("expected the cycle in the details, got %q", appErr.Details)
+				}
+			},
+		},
+		{
+			name: "Archived Prep Item",
+			input: &models.PrepRecipe{IngredientID: 4, Yield: 500, Ingredients: []models.PrepIngredient{
+				{IngredientID: 1, Amount: 400},
+			}},
+			buildStubs: func(
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				archivedAt := time.Now()
+				prepRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 4).Return(&models.Ingredient{ID: 4, ArchivedAt: &archivedAt}, nil)
+				prepRepo.EXPECT().SavePrepRecipe(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, recipe *models.PrepRecipe, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeConflict {
+					t.Errorf("expected conflict error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			ir := mockrepository.NewMockIngredientRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(ir, prepRepo, tx)
+			is := NewIngredientService(ir, mockrepository.NewMockStockMovementRepository(ctrl), mockrepository.NewMockOutboxRepository(ctrl), mockrepository.NewMockIngredientPackRepository(ctrl), prepRepo)
+
+			recipe, err := is.SetPrepRecipe(context.Background(), tc.input)
+			tc.checkResult(t, recipe, err)
 		})
 	}
 }
@@ -1,9 +1,9 @@
 // Code generated by MockGen. DO NOT EDIT.
-// Source: stockk/internal/service (interfaces: IngredientService,OrderService)
+// Source: stockk/internal/service (interfaces: IngredientService,OrderService,OutboxService,ProductService,ReceiptService,ReportService,StockCountService,SupplierService,WasteService)
 //
 // Generated by this command:
 //
-//	mockgen -package mockservice -destination internal/service/mock/service.go stockk/internal/service IngredientService,OrderService
+//	mockgen -package mockservice -destination internal/service/mock/service.go stockk/internal/service IngredientService,OrderService,OutboxService,ProductService,ReceiptService,ReportService,StockCountService,SupplierService,WasteService
 //
 
 // Package mockservice is a generated GoMock package.
@@ -41,18 +41,241 @@ func (m *MockIngredientService) EXPECT() *MockIngredientServiceMockRecorder {
 	return m.recorder
 }
 
-// CheckIngredientLevelsAndAlert mocks base method.
-func (m *MockIngredientService) CheckIngredientLevelsAndAlert(ctx context.Context) error {
+// ArchiveIngredient mocks base method.
+func (m *MockIngredientService) ArchiveIngredient(ctx context.Context, ingredientID int) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "CheckIngredientLevelsAndAlert", ctx)
+	ret := m.ctrl.Call(m, "ArchiveIngredient", ctx, ingredientID)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// CheckIngredientLevelsAndAlert indicates an expected call of CheckIngredientLevelsAndAlert.
-func (mr *MockIngredientServiceMockRecorder) CheckIngredientLevelsAndAlert(ctx any) *gomock.Call {
+// ArchiveIngredient indicates an expected call of ArchiveIngredient.
+func (mr *MockIngredientServiceMockRecorder) ArchiveIngredient(ctx, ingredientID any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CheckIngredientLevelsAndAlert", reflect.TypeOf((*MockIngredientService)(nil).CheckIngredientLevelsAndAlert), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ArchiveIngredient", reflect.TypeOf((*MockIngredientService)(nil).ArchiveIngredient), ctx, ingredientID)
+}
+
+// CreateIngredient mocks base method.
+func (m *MockIngredientService) CreateIngredient(ctx context.Context, ingredient *models.Ingredient) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateIngredient", ctx, ingredient)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateIngredient indicates an expected call of CreateIngredient.
+func (mr *MockIngredientServiceMockRecorder) CreateIngredient(ctx, ingredient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateIngredient", reflect.TypeOf((*MockIngredientService)(nil).CreateIngredient), ctx, ingredient)
+}
+
+// CreatePack mocks base method.
+func (m *MockIngredientService) CreatePack(ctx context.Context, pack *models.IngredientPack) (*models.IngredientPack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreatePack", ctx, pack)
+	ret0, _ := ret[0].(*models.IngredientPack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreatePack indicates an expected call of CreatePack.
+func (mr *MockIngredientServiceMockRecorder) CreatePack(ctx, pack any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreatePack", reflect.TypeOf((*MockIngredientService)(nil).CreatePack), ctx, pack)
+}
+
+// DeletePack mocks base method.
+func (m *MockIngredientService) DeletePack(ctx context.Context, ingredientID, packID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePack", ctx, ingredientID, packID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePack indicates an expected call of DeletePack.
+func (mr *MockIngredientServiceMockRecorder) DeletePack(ctx, ingredientID, packID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePack", reflect.TypeOf((*MockIngredientService)(nil).DeletePack), ctx, ingredientID, packID)
+}
+
+// DeletePrepRecipe mocks base method.
+func (m *MockIngredientService) DeletePrepRecipe(ctx context.Context, ingredientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePrepRecipe", ctx, ingredientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePrepRecipe indicates an expected call of DeletePrepRecipe.
+func (mr *MockIngredientServiceMockRecorder) DeletePrepRecipe(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePrepRecipe", reflect.TypeOf((*MockIngredientService)(nil).DeletePrepRecipe), ctx, ingredientID)
+}
+
+// GetIngredient mocks base method.
+func (m *MockIngredientService) GetIngredient(ctx context.Context, ingredientID int) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetIngredient", ctx, ingredientID)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetIngredient indicates an expected call of GetIngredient.
+func (mr *MockIngredientServiceMockRecorder) GetIngredient(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetIngredient", reflect.TypeOf((*MockIngredientService)(nil).GetIngredient), ctx, ingredientID)
+}
+
+// GetPrepRecipe mocks base method.
+func (m *MockIngredientService) GetPrepRecipe(ctx context.Context, ingredientID int) (*models.PrepRecipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPrepRecipe", ctx, ingredientID)
+	ret0, _ := ret[0].(*models.PrepRecipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPrepRecipe indicates an expected call of GetPrepRecipe.
+func (mr *MockIngredientServiceMockRecorder) GetPrepRecipe(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrepRecipe", reflect.TypeOf((*MockIngredientService)(nil).GetPrepRecipe), ctx, ingredientID)
+}
+
+// ListExpiringLots mocks base method.
+func (m *MockIngredientService) ListExpiringLots(ctx context.Context, days int) ([]models.IngredientLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListExpiringLots", ctx, days)
+	ret0, _ := ret[0].([]models.IngredientLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListExpiringLots indicates an expected call of ListExpiringLots.
+func (mr *MockIngredientServiceMockRecorder) ListExpiringLots(ctx, days any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListExpiringLots", reflect.TypeOf((*MockIngredientService)(nil).ListExpiringLots), ctx, days)
+}
+
+// ListIngredients mocks base method.
+func (m *MockIngredientService) ListIngredients(ctx context.Context) ([]models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngredients", ctx)
+	ret0, _ := ret[0].([]models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIngredients indicates an expected call of ListIngredients.
+func (mr *MockIngredientServiceMockRecorder) ListIngredients(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngredients", reflect.TypeOf((*MockIngredientService)(nil).ListIngredients), ctx)
+}
+
+// ListLots mocks base method.
+func (m *MockIngredientService) ListLots(ctx context.Context, ingredientID int) ([]models.IngredientLot, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListLots", ctx, ingredientID)
+	ret0, _ := ret[0].([]models.IngredientLot)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListLots indicates an expected call of ListLots.
+func (mr *MockIngredientServiceMockRecorder) ListLots(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListLots", reflect.TypeOf((*MockIngredientService)(nil).ListLots), ctx, ingredientID)
+}
+
+// ListPacks mocks base method.
+func (m *MockIngredientService) ListPacks(ctx context.Context, ingredientID int) ([]models.IngredientPack, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListPacks", ctx, ingredientID)
+	ret0, _ := ret[0].([]models.IngredientPack)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListPacks indicates an expected call of ListPacks.
+func (mr *MockIngredientServiceMockRecorder) ListPacks(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListPacks", reflect.TypeOf((*MockIngredientService)(nil).ListPacks), ctx, ingredientID)
+}
+
+// ListStockMovements mocks base method.
+func (m *MockIngredientService) ListStockMovements(ctx context.Context, filter models.StockMovementFilter) (*models.StockMovementPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStockMovements", ctx, filter)
+	ret0, _ := ret[0].(*models.StockMovementPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStockMovements indicates an expected call of ListStockMovements.
+func (mr *MockIngredientServiceMockRecorder) ListStockMovements(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStockMovements", reflect.TypeOf((*MockIngredientService)(nil).ListStockMovements), ctx, filter)
+}
+
+// RenameIngredient mocks base method.
+func (m *MockIngredientService) RenameIngredient(ctx context.Context, ingredientID int, name string) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenameIngredient", ctx, ingredientID, name)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenameIngredient indicates an expected call of RenameIngredient.
+func (mr *MockIngredientServiceMockRecorder) RenameIngredient(ctx, ingredientID, name any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenameIngredient", reflect.TypeOf((*MockIngredientService)(nil).RenameIngredient), ctx, ingredientID, name)
+}
+
+// SetLowStockThreshold mocks base method.
+func (m *MockIngredientService) SetLowStockThreshold(ctx context.Context, ingredientID int, threshold *models.LowStockThreshold) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetLowStockThreshold", ctx, ingredientID, threshold)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetLowStockThreshold indicates an expected call of SetLowStockThreshold.
+func (mr *MockIngredientServiceMockRecorder) SetLowStockThreshold(ctx, ingredientID, threshold any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetLowStockThreshold", reflect.TypeOf((*MockIngredientService)(nil).SetLowStockThreshold), ctx, ingredientID, threshold)
+}
+
+// SetPrepRecipe mocks base method.
+func (m *MockIngredientService) SetPrepRecipe(ctx context.Context, recipe *models.PrepRecipe) (*models.PrepRecipe, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPrepRecipe", ctx, recipe)
+	ret0, _ := ret[0].(*models.PrepRecipe)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetPrepRecipe indicates an expected call of SetPrepRecipe.
+func (mr *MockIngredientServiceMockRecorder) SetPrepRecipe(ctx, recipe any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPrepRecipe", reflect.TypeOf((*MockIngredientService)(nil).SetPrepRecipe), ctx, recipe)
+}
+
+// SetYieldPercent mocks base method.
+func (m *MockIngredientService) SetYieldPercent(ctx context.Context, ingredientID int, yieldPercent *float64) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetYieldPercent", ctx, ingredientID, yieldPercent)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetYieldPercent indicates an expected call of SetYieldPercent.
+func (mr *MockIngredientServiceMockRecorder) SetYieldPercent(ctx, ingredientID, yieldPercent any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetYieldPercent", reflect.TypeOf((*MockIngredientService)(nil).SetYieldPercent), ctx, ingredientID, yieldPercent)
 }
 
 // UpdateIngredientStock mocks base method.
@@ -69,6 +292,21 @@ func (mr *MockIngredientServiceMockRecorder) UpdateIngredientStock(ctx, ingredie
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateIngredientStock", reflect.TypeOf((*MockIngredientService)(nil).UpdateIngredientStock), ctx, ingredients)
 }
 
+// UpdateTotalStock mocks base method.
+func (m *MockIngredientService) UpdateTotalStock(ctx context.Context, ingredientID int, totalStock float64) (*models.Ingredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTotalStock", ctx, ingredientID, totalStock)
+	ret0, _ := ret[0].(*models.Ingredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTotalStock indicates an expected call of UpdateTotalStock.
+func (mr *MockIngredientServiceMockRecorder) UpdateTotalStock(ctx, ingredientID, totalStock any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTotalStock", reflect.TypeOf((*MockIngredientService)(nil).UpdateTotalStock), ctx, ingredientID, totalStock)
+}
+
 // MockOrderService is a mock of OrderService interface.
 type MockOrderService struct {
 	ctrl     *gomock.Controller
@@ -93,6 +331,21 @@ func (m *MockOrderService) EXPECT() *MockOrderServiceMockRecorder {
 	return m.recorder
 }
 
+// CancelOrder mocks base method.
+func (m *MockOrderService) CancelOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelOrder", ctx, orderID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelOrder indicates an expected call of CancelOrder.
+func (mr *MockOrderServiceMockRecorder) CancelOrder(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelOrder", reflect.TypeOf((*MockOrderService)(nil).CancelOrder), ctx, orderID)
+}
+
 // CreateOrder mocks base method.
 func (m *MockOrderService) CreateOrder(ctx context.Context, orderItems []models.OrderItem) (*models.Order, error) {
 	m.ctrl.T.Helper()
@@ -107,3 +360,783 @@ func (mr *MockOrderServiceMockRecorder) CreateOrder(ctx, orderItems any) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrder", reflect.TypeOf((*MockOrderService)(nil).CreateOrder), ctx, orderItems)
 }
+
+// CreateOrderIdempotent mocks base method.
+func (m *MockOrderService) CreateOrderIdempotent(ctx context.Context, key, requestHash string, orderItems []models.OrderItem) (*models.Order, bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateOrderIdempotent", ctx, key, requestHash, orderItems)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(bool)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// CreateOrderIdempotent indicates an expected call of CreateOrderIdempotent.
+func (mr *MockOrderServiceMockRecorder) CreateOrderIdempotent(ctx, key, requestHash, orderItems any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateOrderIdempotent", reflect.TypeOf((*MockOrderService)(nil).CreateOrderIdempotent), ctx, key, requestHash, orderItems)
+}
+
+// GetOrder mocks base method.
+func (m *MockOrderService) GetOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOrder", ctx, orderID)
+	ret0, _ := ret[0].(*models.Order)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOrder indicates an expected call of GetOrder.
+func (mr *MockOrderServiceMockRecorder) GetOrder(ctx, orderID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOrder", reflect.TypeOf((*MockOrderService)(nil).GetOrder), ctx, orderID)
+}
+
+// ListOrders mocks base method.
+func (m *MockOrderService) ListOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListOrders", ctx, filter)
+	ret0, _ := ret[0].(*models.OrderPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListOrders indicates an expected call of ListOrders.
+func (mr *MockOrderServiceMockRecorder) ListOrders(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListOrders", reflect.TypeOf((*MockOrderService)(nil).ListOrders), ctx, filter)
+}
+
+// PreviewOrder mocks base method.
+func (m *MockOrderService) PreviewOrder(ctx context.Context, orderItems []models.OrderItem) (*models.OrderPreview, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PreviewOrder", ctx, orderItems)
+	ret0, _ := ret[0].(*models.OrderPreview)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PreviewOrder indicates an expected call of PreviewOrder.
+func (mr *MockOrderServiceMockRecorder) PreviewOrder(ctx, orderItems any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PreviewOrder", reflect.TypeOf((*MockOrderService)(nil).PreviewOrder), ctx, orderItems)
+}
+
+// MockOutboxService is a mock of OutboxService interface.
+type MockOutboxService struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockOutboxServiceMockRecorder is the mock recorder for MockOutboxService.
+type MockOutboxServiceMockRecorder struct {
+	mock *MockOutboxService
+}
+
+// NewMockOutboxService creates a new mock instance.
+func NewMockOutboxService(ctrl *gomock.Controller) *MockOutboxService {
+	mock := &MockOutboxService{ctrl: ctrl}
+	mock.recorder = &MockOutboxServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxService) EXPECT() *MockOutboxServiceMockRecorder {
+	return m.recorder
+}
+
+// DispatchPending mocks base method.
+func (m *MockOutboxService) DispatchPending(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DispatchPending", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DispatchPending indicates an expected call of DispatchPending.
+func (mr *MockOutboxServiceMockRecorder) DispatchPending(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DispatchPending", reflect.TypeOf((*MockOutboxService)(nil).DispatchPending), ctx)
+}
+
+// MockProductService is a mock of ProductService interface.
+type MockProductService struct {
+	ctrl     *gomock.Controller
+	recorder *MockProductServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockProductServiceMockRecorder is the mock recorder for MockProductService.
+type MockProductServiceMockRecorder struct {
+	mock *MockProductService
+}
+
+// NewMockProductService creates a new mock instance.
+func NewMockProductService(ctrl *gomock.Controller) *MockProductService {
+	mock := &MockProductService{ctrl: ctrl}
+	mock.recorder = &MockProductServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockProductService) EXPECT() *MockProductServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateModifier mocks base method.
+func (m *MockProductService) CreateModifier(ctx context.Context, modifier *models.ProductModifier) (*models.ProductModifier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateModifier", ctx, modifier)
+	ret0, _ := ret[0].(*models.ProductModifier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateModifier indicates an expected call of CreateModifier.
+func (mr *MockProductServiceMockRecorder) CreateModifier(ctx, modifier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateModifier", reflect.TypeOf((*MockProductService)(nil).CreateModifier), ctx, modifier)
+}
+
+// CreateProduct mocks base method.
+func (m *MockProductService) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateProduct", ctx, product)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateProduct indicates an expected call of CreateProduct.
+func (mr *MockProductServiceMockRecorder) CreateProduct(ctx, product any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateProduct", reflect.TypeOf((*MockProductService)(nil).CreateProduct), ctx, product)
+}
+
+// CreateVariant mocks base method.
+func (m *MockProductService) CreateVariant(ctx context.Context, variant *models.ProductVariant) (*models.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateVariant", ctx, variant)
+	ret0, _ := ret[0].(*models.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateVariant indicates an expected call of CreateVariant.
+func (mr *MockProductServiceMockRecorder) CreateVariant(ctx, variant any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateVariant", reflect.TypeOf((*MockProductService)(nil).CreateVariant), ctx, variant)
+}
+
+// DeleteModifier mocks base method.
+func (m *MockProductService) DeleteModifier(ctx context.Context, productID, modifierID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteModifier", ctx, productID, modifierID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteModifier indicates an expected call of DeleteModifier.
+func (mr *MockProductServiceMockRecorder) DeleteModifier(ctx, productID, modifierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteModifier", reflect.TypeOf((*MockProductService)(nil).DeleteModifier), ctx, productID, modifierID)
+}
+
+// DeleteVariant mocks base method.
+func (m *MockProductService) DeleteVariant(ctx context.Context, productID, variantID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteVariant", ctx, productID, variantID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteVariant indicates an expected call of DeleteVariant.
+func (mr *MockProductServiceMockRecorder) DeleteVariant(ctx, productID, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteVariant", reflect.TypeOf((*MockProductService)(nil).DeleteVariant), ctx, productID, variantID)
+}
+
+// GetProduct mocks base method.
+func (m *MockProductService) GetProduct(ctx context.Context, productID int) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProduct", ctx, productID)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProduct indicates an expected call of GetProduct.
+func (mr *MockProductServiceMockRecorder) GetProduct(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProduct", reflect.TypeOf((*MockProductService)(nil).GetProduct), ctx, productID)
+}
+
+// GetProductCapacity mocks base method.
+func (m *MockProductService) GetProductCapacity(ctx context.Context, productID int, variantID *int) (*models.ProductCapacity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProductCapacity", ctx, productID, variantID)
+	ret0, _ := ret[0].(*models.ProductCapacity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProductCapacity indicates an expected call of GetProductCapacity.
+func (mr *MockProductServiceMockRecorder) GetProductCapacity(ctx, productID, variantID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProductCapacity", reflect.TypeOf((*MockProductService)(nil).GetProductCapacity), ctx, productID, variantID)
+}
+
+// ListMenuAvailability mocks base method.
+func (m *MockProductService) ListMenuAvailability(ctx context.Context) ([]models.MenuItemAvailability, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListMenuAvailability", ctx)
+	ret0, _ := ret[0].([]models.MenuItemAvailability)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListMenuAvailability indicates an expected call of ListMenuAvailability.
+func (mr *MockProductServiceMockRecorder) ListMenuAvailability(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListMenuAvailability", reflect.TypeOf((*MockProductService)(nil).ListMenuAvailability), ctx)
+}
+
+// ListModifiers mocks base method.
+func (m *MockProductService) ListModifiers(ctx context.Context, productID int) ([]models.ProductModifier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListModifiers", ctx, productID)
+	ret0, _ := ret[0].([]models.ProductModifier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListModifiers indicates an expected call of ListModifiers.
+func (mr *MockProductServiceMockRecorder) ListModifiers(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListModifiers", reflect.TypeOf((*MockProductService)(nil).ListModifiers), ctx, productID)
+}
+
+// ListProductCapacities mocks base method.
+func (m *MockProductService) ListProductCapacities(ctx context.Context) ([]models.ProductCapacity, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProductCapacities", ctx)
+	ret0, _ := ret[0].([]models.ProductCapacity)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProductCapacities indicates an expected call of ListProductCapacities.
+func (mr *MockProductServiceMockRecorder) ListProductCapacities(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProductCapacities", reflect.TypeOf((*MockProductService)(nil).ListProductCapacities), ctx)
+}
+
+// ListProducts mocks base method.
+func (m *MockProductService) ListProducts(ctx context.Context) ([]models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListProducts", ctx)
+	ret0, _ := ret[0].([]models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListProducts indicates an expected call of ListProducts.
+func (mr *MockProductServiceMockRecorder) ListProducts(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListProducts", reflect.TypeOf((*MockProductService)(nil).ListProducts), ctx)
+}
+
+// ListVariants mocks base method.
+func (m *MockProductService) ListVariants(ctx context.Context, productID int) ([]models.ProductVariant, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListVariants", ctx, productID)
+	ret0, _ := ret[0].([]models.ProductVariant)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListVariants indicates an expected call of ListVariants.
+func (mr *MockProductServiceMockRecorder) ListVariants(ctx, productID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListVariants", reflect.TypeOf((*MockProductService)(nil).ListVariants), ctx, productID)
+}
+
+// RemoveProductIngredient mocks base method.
+func (m *MockProductService) RemoveProductIngredient(ctx context.Context, productID, ingredientID int) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveProductIngredient", ctx, productID, ingredientID)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RemoveProductIngredient indicates an expected call of RemoveProductIngredient.
+func (mr *MockProductServiceMockRecorder) RemoveProductIngredient(ctx, productID, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveProductIngredient", reflect.TypeOf((*MockProductService)(nil).RemoveProductIngredient), ctx, productID, ingredientID)
+}
+
+// ReplaceProductIngredients mocks base method.
+func (m *MockProductService) ReplaceProductIngredients(ctx context.Context, productID int, ingredients []models.ProductIngredient) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceProductIngredients", ctx, productID, ingredients)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceProductIngredients indicates an expected call of ReplaceProductIngredients.
+func (mr *MockProductServiceMockRecorder) ReplaceProductIngredients(ctx, productID, ingredients any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceProductIngredients", reflect.TypeOf((*MockProductService)(nil).ReplaceProductIngredients), ctx, productID, ingredients)
+}
+
+// SetProductIngredient mocks base method.
+func (m *MockProductService) SetProductIngredient(ctx context.Context, productIngredient models.ProductIngredient) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProductIngredient", ctx, productIngredient)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProductIngredient indicates an expected call of SetProductIngredient.
+func (mr *MockProductServiceMockRecorder) SetProductIngredient(ctx, productIngredient any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProductIngredient", reflect.TypeOf((*MockProductService)(nil).SetProductIngredient), ctx, productIngredient)
+}
+
+// SetProductMarkedUnavailable mocks base method.
+func (m *MockProductService) SetProductMarkedUnavailable(ctx context.Context, productID int, unavailable bool) (*models.Product, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetProductMarkedUnavailable", ctx, productID, unavailable)
+	ret0, _ := ret[0].(*models.Product)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetProductMarkedUnavailable indicates an expected call of SetProductMarkedUnavailable.
+func (mr *MockProductServiceMockRecorder) SetProductMarkedUnavailable(ctx, productID, unavailable any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetProductMarkedUnavailable", reflect.TypeOf((*MockProductService)(nil).SetProductMarkedUnavailable), ctx, productID, unavailable)
+}
+
+// MockReceiptService is a mock of ReceiptService interface.
+type MockReceiptService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReceiptServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockReceiptServiceMockRecorder is the mock recorder for MockReceiptService.
+type MockReceiptServiceMockRecorder struct {
+	mock *MockReceiptService
+}
+
+// NewMockReceiptService creates a new mock instance.
+func NewMockReceiptService(ctrl *gomock.Controller) *MockReceiptService {
+	mock := &MockReceiptService{ctrl: ctrl}
+	mock.recorder = &MockReceiptServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReceiptService) EXPECT() *MockReceiptServiceMockRecorder {
+	return m.recorder
+}
+
+// GetReceipt mocks base method.
+func (m *MockReceiptService) GetReceipt(ctx context.Context, receiptID int) (*models.StockReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetReceipt", ctx, receiptID)
+	ret0, _ := ret[0].(*models.StockReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetReceipt indicates an expected call of GetReceipt.
+func (mr *MockReceiptServiceMockRecorder) GetReceipt(ctx, receiptID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetReceipt", reflect.TypeOf((*MockReceiptService)(nil).GetReceipt), ctx, receiptID)
+}
+
+// ReceiveStock mocks base method.
+func (m *MockReceiptService) ReceiveStock(ctx context.Context, receipt *models.StockReceipt) (*models.StockReceipt, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveStock", ctx, receipt)
+	ret0, _ := ret[0].(*models.StockReceipt)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReceiveStock indicates an expected call of ReceiveStock.
+func (mr *MockReceiptServiceMockRecorder) ReceiveStock(ctx, receipt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveStock", reflect.TypeOf((*MockReceiptService)(nil).ReceiveStock), ctx, receipt)
+}
+
+// MockReportService is a mock of ReportService interface.
+type MockReportService struct {
+	ctrl     *gomock.Controller
+	recorder *MockReportServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockReportServiceMockRecorder is the mock recorder for MockReportService.
+type MockReportServiceMockRecorder struct {
+	mock *MockReportService
+}
+
+// NewMockReportService creates a new mock instance.
+func NewMockReportService(ctrl *gomock.Controller) *MockReportService {
+	mock := &MockReportService{ctrl: ctrl}
+	mock.recorder = &MockReportServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReportService) EXPECT() *MockReportServiceMockRecorder {
+	return m.recorder
+}
+
+// GetUsageVarianceReport mocks base method.
+func (m *MockReportService) GetUsageVarianceReport(ctx context.Context, filter models.UsageReportFilter) (*models.UsageVarianceReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsageVarianceReport", ctx, filter)
+	ret0, _ := ret[0].(*models.UsageVarianceReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsageVarianceReport indicates an expected call of GetUsageVarianceReport.
+func (mr *MockReportServiceMockRecorder) GetUsageVarianceReport(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsageVarianceReport", reflect.TypeOf((*MockReportService)(nil).GetUsageVarianceReport), ctx, filter)
+}
+
+// MockStockCountService is a mock of StockCountService interface.
+type MockStockCountService struct {
+	ctrl     *gomock.Controller
+	recorder *MockStockCountServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockStockCountServiceMockRecorder is the mock recorder for MockStockCountService.
+type MockStockCountServiceMockRecorder struct {
+	mock *MockStockCountService
+}
+
+// NewMockStockCountService creates a new mock instance.
+func NewMockStockCountService(ctrl *gomock.Controller) *MockStockCountService {
+	mock := &MockStockCountService{ctrl: ctrl}
+	mock.recorder = &MockStockCountServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockStockCountService) EXPECT() *MockStockCountServiceMockRecorder {
+	return m.recorder
+}
+
+// CloseSession mocks base method.
+func (m *MockStockCountService) CloseSession(ctx context.Context, sessionID int) (*models.StockCountReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CloseSession", ctx, sessionID)
+	ret0, _ := ret[0].(*models.StockCountReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CloseSession indicates an expected call of CloseSession.
+func (mr *MockStockCountServiceMockRecorder) CloseSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CloseSession", reflect.TypeOf((*MockStockCountService)(nil).CloseSession), ctx, sessionID)
+}
+
+// GetSession mocks base method.
+func (m *MockStockCountService) GetSession(ctx context.Context, sessionID int) (*models.StockCountSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSession", ctx, sessionID)
+	ret0, _ := ret[0].(*models.StockCountSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSession indicates an expected call of GetSession.
+func (mr *MockStockCountServiceMockRecorder) GetSession(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSession", reflect.TypeOf((*MockStockCountService)(nil).GetSession), ctx, sessionID)
+}
+
+// GetVarianceReport mocks base method.
+func (m *MockStockCountService) GetVarianceReport(ctx context.Context, sessionID int) (*models.StockCountReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetVarianceReport", ctx, sessionID)
+	ret0, _ := ret[0].(*models.StockCountReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetVarianceReport indicates an expected call of GetVarianceReport.
+func (mr *MockStockCountServiceMockRecorder) GetVarianceReport(ctx, sessionID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetVarianceReport", reflect.TypeOf((*MockStockCountService)(nil).GetVarianceReport), ctx, sessionID)
+}
+
+// OpenSession mocks base method.
+func (m *MockStockCountService) OpenSession(ctx context.Context, session *models.StockCountSession) (*models.StockCountSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OpenSession", ctx, session)
+	ret0, _ := ret[0].(*models.StockCountSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// OpenSession indicates an expected call of OpenSession.
+func (mr *MockStockCountServiceMockRecorder) OpenSession(ctx, session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OpenSession", reflect.TypeOf((*MockStockCountService)(nil).OpenSession), ctx, session)
+}
+
+// SubmitCounts mocks base method.
+func (m *MockStockCountService) SubmitCounts(ctx context.Context, sessionID int, counts []models.StockCount) (*models.StockCountSession, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubmitCounts", ctx, sessionID, counts)
+	ret0, _ := ret[0].(*models.StockCountSession)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubmitCounts indicates an expected call of SubmitCounts.
+func (mr *MockStockCountServiceMockRecorder) SubmitCounts(ctx, sessionID, counts any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubmitCounts", reflect.TypeOf((*MockStockCountService)(nil).SubmitCounts), ctx, sessionID, counts)
+}
+
+// MockSupplierService is a mock of SupplierService interface.
+type MockSupplierService struct {
+	ctrl     *gomock.Controller
+	recorder *MockSupplierServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockSupplierServiceMockRecorder is the mock recorder for MockSupplierService.
+type MockSupplierServiceMockRecorder struct {
+	mock *MockSupplierService
+}
+
+// NewMockSupplierService creates a new mock instance.
+func NewMockSupplierService(ctrl *gomock.Controller) *MockSupplierService {
+	mock := &MockSupplierService{ctrl: ctrl}
+	mock.recorder = &MockSupplierServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSupplierService) EXPECT() *MockSupplierServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateSupplier mocks base method.
+func (m *MockSupplierService) CreateSupplier(ctx context.Context, supplier *models.Supplier) (*models.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSupplier", ctx, supplier)
+	ret0, _ := ret[0].(*models.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateSupplier indicates an expected call of CreateSupplier.
+func (mr *MockSupplierServiceMockRecorder) CreateSupplier(ctx, supplier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSupplier", reflect.TypeOf((*MockSupplierService)(nil).CreateSupplier), ctx, supplier)
+}
+
+// DeleteSupplier mocks base method.
+func (m *MockSupplierService) DeleteSupplier(ctx context.Context, supplierID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteSupplier", ctx, supplierID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteSupplier indicates an expected call of DeleteSupplier.
+func (mr *MockSupplierServiceMockRecorder) DeleteSupplier(ctx, supplierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSupplier", reflect.TypeOf((*MockSupplierService)(nil).DeleteSupplier), ctx, supplierID)
+}
+
+// GetSupplier mocks base method.
+func (m *MockSupplierService) GetSupplier(ctx context.Context, supplierID int) (*models.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSupplier", ctx, supplierID)
+	ret0, _ := ret[0].(*models.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSupplier indicates an expected call of GetSupplier.
+func (mr *MockSupplierServiceMockRecorder) GetSupplier(ctx, supplierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSupplier", reflect.TypeOf((*MockSupplierService)(nil).GetSupplier), ctx, supplierID)
+}
+
+// ListIngredientSuppliers mocks base method.
+func (m *MockSupplierService) ListIngredientSuppliers(ctx context.Context, ingredientID int) ([]models.SupplierIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListIngredientSuppliers", ctx, ingredientID)
+	ret0, _ := ret[0].([]models.SupplierIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListIngredientSuppliers indicates an expected call of ListIngredientSuppliers.
+func (mr *MockSupplierServiceMockRecorder) ListIngredientSuppliers(ctx, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListIngredientSuppliers", reflect.TypeOf((*MockSupplierService)(nil).ListIngredientSuppliers), ctx, ingredientID)
+}
+
+// ListSupplierIngredients mocks base method.
+func (m *MockSupplierService) ListSupplierIngredients(ctx context.Context, supplierID int) ([]models.SupplierIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSupplierIngredients", ctx, supplierID)
+	ret0, _ := ret[0].([]models.SupplierIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSupplierIngredients indicates an expected call of ListSupplierIngredients.
+func (mr *MockSupplierServiceMockRecorder) ListSupplierIngredients(ctx, supplierID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSupplierIngredients", reflect.TypeOf((*MockSupplierService)(nil).ListSupplierIngredients), ctx, supplierID)
+}
+
+// ListSuppliers mocks base method.
+func (m *MockSupplierService) ListSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListSuppliers", ctx)
+	ret0, _ := ret[0].([]models.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListSuppliers indicates an expected call of ListSuppliers.
+func (mr *MockSupplierServiceMockRecorder) ListSuppliers(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSuppliers", reflect.TypeOf((*MockSupplierService)(nil).ListSuppliers), ctx)
+}
+
+// RemoveSupplierIngredient mocks base method.
+func (m *MockSupplierService) RemoveSupplierIngredient(ctx context.Context, supplierID, ingredientID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RemoveSupplierIngredient", ctx, supplierID, ingredientID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RemoveSupplierIngredient indicates an expected call of RemoveSupplierIngredient.
+func (mr *MockSupplierServiceMockRecorder) RemoveSupplierIngredient(ctx, supplierID, ingredientID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RemoveSupplierIngredient", reflect.TypeOf((*MockSupplierService)(nil).RemoveSupplierIngredient), ctx, supplierID, ingredientID)
+}
+
+// SetSupplierIngredient mocks base method.
+func (m *MockSupplierService) SetSupplierIngredient(ctx context.Context, item models.SupplierIngredient) ([]models.SupplierIngredient, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetSupplierIngredient", ctx, item)
+	ret0, _ := ret[0].([]models.SupplierIngredient)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetSupplierIngredient indicates an expected call of SetSupplierIngredient.
+func (mr *MockSupplierServiceMockRecorder) SetSupplierIngredient(ctx, item any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetSupplierIngredient", reflect.TypeOf((*MockSupplierService)(nil).SetSupplierIngredient), ctx, item)
+}
+
+// UpdateSupplier mocks base method.
+func (m *MockSupplierService) UpdateSupplier(ctx context.Context, supplier *models.Supplier) (*models.Supplier, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateSupplier", ctx, supplier)
+	ret0, _ := ret[0].(*models.Supplier)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateSupplier indicates an expected call of UpdateSupplier.
+func (mr *MockSupplierServiceMockRecorder) UpdateSupplier(ctx, supplier any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateSupplier", reflect.TypeOf((*MockSupplierService)(nil).UpdateSupplier), ctx, supplier)
+}
+
+// MockWasteService is a mock of WasteService interface.
+type MockWasteService struct {
+	ctrl     *gomock.Controller
+	recorder *MockWasteServiceMockRecorder
+	isgomock struct{}
+}
+
+// MockWasteServiceMockRecorder is the mock recorder for MockWasteService.
+type MockWasteServiceMockRecorder struct {
+	mock *MockWasteService
+}
+
+// NewMockWasteService creates a new mock instance.
+func NewMockWasteService(ctrl *gomock.Controller) *MockWasteService {
+	mock := &MockWasteService{ctrl: ctrl}
+	mock.recorder = &MockWasteServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockWasteService) EXPECT() *MockWasteServiceMockRecorder {
+	return m.recorder
+}
+
+// GetWasteEntry mocks base method.
+func (m *MockWasteService) GetWasteEntry(ctx context.Context, entryID int) (*models.WasteEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWasteEntry", ctx, entryID)
+	ret0, _ := ret[0].(*models.WasteEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWasteEntry indicates an expected call of GetWasteEntry.
+func (mr *MockWasteServiceMockRecorder) GetWasteEntry(ctx, entryID any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWasteEntry", reflect.TypeOf((*MockWasteService)(nil).GetWasteEntry), ctx, entryID)
+}
+
+// GetWasteReport mocks base method.
+func (m *MockWasteService) GetWasteReport(ctx context.Context, filter models.WasteReportFilter) (*models.WasteReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetWasteReport", ctx, filter)
+	ret0, _ := ret[0].(*models.WasteReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetWasteReport indicates an expected call of GetWasteReport.
+func (mr *MockWasteServiceMockRecorder) GetWasteReport(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetWasteReport", reflect.TypeOf((*MockWasteService)(nil).GetWasteReport), ctx, filter)
+}
+
+// RecordWaste mocks base method.
+func (m *MockWasteService) RecordWaste(ctx context.Context, entry *models.WasteEntry) (*models.WasteEntry, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordWaste", ctx, entry)
+	ret0, _ := ret[0].(*models.WasteEntry)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordWaste indicates an expected call of RecordWaste.
+func (mr *MockWasteServiceMockRecorder) RecordWaste(ctx, entry any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordWaste", reflect.TypeOf((*MockWasteService)(nil).RecordWaste), ctx, entry)
+}
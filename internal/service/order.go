@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
+	"sort"
 	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
 	"stockk/internal/repository"
@@ -12,19 +15,32 @@ import (
 
 type OrderService interface {
 	CreateOrder(ctx context.Context, orderItems []models.OrderItem) (*models.Order, error)
+	CreateOrderIdempotent(ctx context.Context, key string, requestHash string, orderItems []models.OrderItem) (order *models.Order, replayed bool, err error)
+	PreviewOrder(ctx context.Context, orderItems []models.OrderItem) (*models.OrderPreview, error)
+	GetOrder(ctx context.Context, orderID int) (*models.Order, error)
+	ListOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderPage, error)
+	CancelOrder(ctx context.Context, orderID int) (*models.Order, error)
 }
 
 type orderService struct {
-	orderRepo      repository.OrderRepository
-	productRepo    repository.ProductRepository
-	ingredientRepo repository.IngredientRepository
+	orderRepo       repository.OrderRepository
+	productRepo     repository.ProductRepository
+	ingredientRepo  repository.IngredientRepository
+	movementRepo    repository.StockMovementRepository
+	idempotencyRepo repository.IdempotencyRepository
+	outboxRepo      repository.OutboxRepository
+	prepRepo        repository.PrepRecipeRepository
 }
 
-func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, ingredientRepo repository.IngredientRepository) OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, productRepo repository.ProductRepository, ingredientRepo repository.IngredientRepository, movementRepo repository.StockMovementRepository, idempotencyRepo repository.IdempotencyRepository, outboxRepo repository.OutboxRepository, prepRepo repository.PrepRecipeRepository) OrderService {
 	return &orderService{
-		orderRepo:      orderRepo,
-		productRepo:    productRepo,
-		ingredientRepo: ingredientRepo,
+		orderRepo:       orderRepo,
+		productRepo:     productRepo,
+		ingredientRepo:  ingredientRepo,
+		movementRepo:    movementRepo,
+		idempotencyRepo: idempotencyRepo,
+		outboxRepo:      outboxRepo,
+		prepRepo:        prepRepo,
 	}
 }
 
@@ -36,17 +52,282 @@ func (os *orderService) CreateOrder(ctx context.Context, orderItems []models.Ord
 	if err != nil {
 		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
 	}
-	// Defer rollback, this will be called only if there's an error before committing
+	// Defer rollback, this will be called only if there's an error before committing.
+	// A failed order must not keep holding the ingredient row locks.
 	defer func() {
 		if err != nil {
-			if err := tx.Rollback(); err != nil {
-				slog.Error("failed to rollback transaction", "error", err)
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
 			}
 		}
 	}()
 
+	order, err := os.placeOrder(ctx, tx, orderItems)
+	if err != nil {
+		return nil, err
+	}
+
+	// Commit transaction
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CreateOrderIdempotent creates an order at most once per idempotency key.
+// Repeating a key with the same request hash replays the order created the
+// first time, a different request hash is rejected.
+func (os *orderService) CreateOrderIdempotent(ctx context.Context, key string, requestHash string, orderItems []models.OrderItem) (order *models.Order, replayed bool, err error) {
+	tx, err := os.orderRepo.BeginTransaction()
+	if err != nil {
+		return nil, false, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds, the key is
+	// released with it so a retry of a failed order runs again
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	existing, err := os.idempotencyRepo.ClaimKey(ctx, tx, key, requestHash)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		if order, err = replayOrder(existing, requestHash); err != nil {
+			return nil, false, err
+		}
+		// Nothing was written, release the transaction
+		if rbErr := tx.Rollback(); rbErr != nil {
+			slog.Error("failed to rollback transaction", "error", rbErr)
+		}
+		return order, true, nil
+	}
+
+	order, err = os.placeOrder(ctx, tx, orderItems)
+	if err != nil {
+		return nil, false, err
+	}
+
+	response, err := json.Marshal(order)
+	if err != nil {
+		return nil, false, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to encode order")
+	}
+	if err = os.idempotencyRepo.SaveResponse(ctx, tx, key, response); err != nil {
+		return nil, false, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, false, err
+	}
+
+	return order, false, nil
+}
+
+// replayOrder returns the order stored for an idempotency key used before.
+func replayOrder(existing *models.IdempotencyKey, requestHash string) (*models.Order, error) {
+	if existing.RequestHash != requestHash {
+		return nil, internalErrors.NewAppError(
+			internalErrors.ErrCodeUnprocessable,
+			"Idempotency key reused",
+			fmt.Sprintf("Idempotency key %q was already used with a different request", existing.Key),
+		)
+	}
+
+	var order models.Order
+	if err := json.Unmarshal(existing.Response, &order); err != nil {
+		slog.Error("failed to decode idempotent response", "key", existing.Key, "error", err)
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to decode stored order")
+	}
+
+	return &order, nil
+}
+
+// PreviewOrder runs the stock deduction of an order and rolls it back, reporting
+// the projected stock of every ingredient used, the items that would fail for
+// insufficient stock and the ingredients that would cross their low stock threshold.
+func (os *orderService) PreviewOrder(ctx context.Context, orderItems []models.OrderItem) (*models.OrderPreview, error) {
+	tx, err := os.orderRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// A preview never commits, whatever happens the deductions are discarded
+	defer func() {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			slog.Error("failed to rollback transaction", "error", rbErr)
+		}
+	}()
+
+	products, err := os.orderProducts(ctx, tx, orderItems)
+	if err != nil {
+		return nil, err
+	}
+
+	preview := &models.OrderPreview{
+		Feasible:          true,
+		Ingredients:       []models.IngredientProjection{},
+		InsufficientItems: []models.InsufficientItem{},
+	}
+	insufficient := make(map[int]bool)
+	consumptions, err := sumConsumptions(0, orderItems, products)
+	if err != nil {
+		return nil, err
+	}
+	consumptions, recipes, err := os.resolvePreps(ctx, tx, consumptions)
+	if err != nil {
+		return nil, err
+	}
+	for _, consumption := range consumptions {
+		projection, err := os.projectConsumption(ctx, tx, consumption)
+		if err != nil {
+			return nil, err
+		}
+		if projection.Insufficient {
+			insufficient[projection.IngredientID] = true
+			preview.Feasible = false
+		}
+		preview.Ingredients = append(preview.Ingredients, *projection)
+	}
+
+	for i, item := range orderItems {
+		// A product runs short when an ingredient of its recipe does, or one a prep item of it is made from
+		var lineIngredientIDs []int
+		for _, productIngredient := range products[i].Ingredients {
+			lineIngredientIDs = append(lineIngredientIDs, productIngredient.IngredientID)
+		}
+		var ingredientIDs []int
+		for _, ingredientID := range prepClosure(recipes, lineIngredientIDs) {
+			if insufficient[ingredientID] {
+				ingredientIDs = append(ingredientIDs, ingredientID)
+			}
+		}
+		if len(ingredientIDs) > 0 {
+			preview.InsufficientItems = append(preview.InsufficientItems, models.InsufficientItem{
+				ProductID:     item.ProductID,
+				VariantID:     item.VariantID,
+				Quantity:      item.Quantity,
+				IngredientIDs: ingredientIDs,
+			})
+		}
+	}
+
+	return preview, nil
+}
+
+// projectConsumption deducts the stock used by an order like consumeIngredient
+// does and reports the stock the ingredient would have left.
+func (os *orderService) projectConsumption(ctx context.Context, tx repository.Transaction, consumption models.OrderConsumption) (*models.IngredientProjection, error) {
+	ingredient, err := os.ingredientRepo.GetIngredientByID(ctx, tx, consumption.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+
+	projection := &models.IngredientProjection{
+		IngredientID:   ingredient.ID,
+		Name:           ingredient.Name,
+		CurrentStock:   ingredient.CurrentStock,
+		Theoretical:    consumption.Theoretical,
+		Required:       consumption.Amount,
+		ProjectedStock: ingredient.CurrentStock - consumption.Amount,
+	}
+
+	err = os.ingredientRepo.DecrementStock(ctx, tx, consumption.IngredientID, consumption.Amount)
+	var appErr *internalErrors.AppError
+	switch {
+	case err == nil:
+		projection.CrossesThreshold = !ingredient.IsLowStockAt(ingredient.CurrentStock) && ingredient.IsLowStockAt(projection.ProjectedStock)
+	case errors.As(err, &appErr) && appErr.Code == internalErrors.ErrCodeInsufficientStock:
+		projection.Insufficient = true
+	default:
+		return nil, err
+	}
+
+	return projection, nil
+}
+
+func (os *orderService) GetOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	return os.orderRepo.GetOrderByID(ctx, orderID)
+}
+
+// ListOrders returns a page of orders matching filter and the cursor of the next page, if any.
+func (os *orderService) ListOrders(ctx context.Context, filter models.OrderFilter) (*models.OrderPage, error) {
+	pageSize := filter.Limit
+
+	// Fetch one extra order to find out whether another page follows
+	filter.Limit = pageSize + 1
+	orders, err := os.orderRepo.ListOrders(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &models.OrderPage{Orders: orders}
+	if len(orders) > pageSize {
+		page.Orders = orders[:pageSize]
+		nextCursor := page.Orders[pageSize-1].ID
+		page.NextCursor = &nextCursor
+	}
+
+	return page, nil
+}
+
+// CancelOrder cancels a placed order and restores the ingredient stock it consumed,
+// using the amounts recorded when the order was placed.
+func (os *orderService) CancelOrder(ctx context.Context, orderID int) (*models.Order, error) {
+	tx, err := os.orderRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	if err = os.orderRepo.CancelOrder(ctx, tx, orderID); err != nil {
+		return nil, err
+	}
+
+	consumptions, err := os.orderRepo.GetOrderConsumptions(ctx, tx, orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, consumption := range consumptions {
+		if err = os.ingredientRepo.IncrementStock(ctx, tx, consumption.IngredientID, consumption.Amount); err != nil {
+			return nil, err
+		}
+		if err = os.movementRepo.RecordMovement(ctx, tx, &models.StockMovement{
+			IngredientID: consumption.IngredientID,
+			Delta:        consumption.Amount,
+			Reason:       models.MovementReasonCancellation,
+			ReferenceID:  &orderID,
+		}); err != nil {
+			return nil, err
+		}
+		if err = os.ingredientRepo.ResetAlertIfReplenished(ctx, tx, consumption.IngredientID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return os.orderRepo.GetOrderByID(ctx, orderID)
+}
+
+// placeOrder creates an order and deducts the ingredients it consumes within tx.
+func (os *orderService) placeOrder(ctx context.Context, tx repository.Transaction, orderItems []models.OrderItem) (*models.Order, error) {
 	// Create the order
 	order := &models.Order{
+		Status:    models.OrderStatusPlaced,
 		Items:     orderItems,
 		CreatedAt: time.Now(),
 	}
@@ -55,55 +336,251 @@ func (os *orderService) CreateOrder(ctx context.Context, orderItems []models.Ord
 		return nil, err
 	}
 
-	// Process each product and update ingredient stocks
-	for _, item := range orderItems {
-		if err := os.processOrderItem(ctx, tx, item); err != nil {
+	consumptions, err := os.orderConsumptions(ctx, tx, order.ID, orderItems)
+	if err != nil {
+		return nil, err
+	}
+
+	// Update ingredient stocks in ingredient ID order so concurrent orders
+	// lock the ingredient rows in the same order and cannot deadlock
+	ingredientIDs := make([]int, len(consumptions))
+	for i, consumption := range consumptions {
+		if err := os.consumeIngredient(ctx, tx, consumption); err != nil {
 			return nil, err
 		}
+		ingredientIDs[i] = consumption.IngredientID
 	}
 
-	// Commit transaction
-	if err := tx.Commit(); err != nil {
+	// Raise the low stock alert together with the order so it cannot get lost
+	if err := queueLowStockAlert(ctx, tx, os.ingredientRepo, os.outboxRepo, ingredientIDs); err != nil {
 		return nil, err
 	}
 
 	return order, nil
 }
 
-func (os *orderService) processOrderItem(ctx context.Context, tx repository.Transaction, item models.OrderItem) error {
-	// Retrieve the product
-	product, err := os.productRepo.GetProductById(ctx, tx, item.ProductID)
+// orderConsumptions adds up the ingredients used by all order items, sorted by
+// ingredient ID, making the prep items short on prepared stock from their recipes.
+func (os *orderService) orderConsumptions(ctx context.Context, tx repository.Transaction, orderID int, orderItems []models.OrderItem) ([]models.OrderConsumption, error) {
+	products, err := os.orderProducts(ctx, tx, orderItems)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	consumptions, err := sumConsumptions(orderID, orderItems, products)
+	if err != nil {
+		return nil, err
+	}
+
+	consumptions, _, err = os.resolvePreps(ctx, tx, consumptions)
+	return consumptions, err
+}
+
+// resolvePreps expands the consumption of prep items beyond their prepared
+// stock into the ingredients of their recipes, recursively. It returns the
+// resulting consumptions, sorted by ingredient ID, and the prep recipes by
+// prep item ID.
+func (os *orderService) resolvePreps(ctx context.Context, tx repository.Transaction, consumptions []models.OrderConsumption) ([]models.OrderConsumption, map[int]models.PrepRecipe, error) {
+	list, err := os.prepRepo.ListPrepRecipes(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	recipes := indexPrepRecipes(list)
+
+	ingredientIDs := make([]int, 0, len(consumptions))
+	usesPrep := false
+	for _, consumption := range consumptions {
+		ingredientIDs = append(ingredientIDs, consumption.IngredientID)
+		if _, ok := recipes[consumption.IngredientID]; ok {
+			usesPrep = true
+		}
+	}
+	if !usesPrep {
+		return consumptions, recipes, nil
+	}
+
+	// How much of a prep item is made depends on its prepared stock, so lock
+	// everything it may need up front, in ingredient ID order
+	stock, err := os.ingredientRepo.LockStock(ctx, tx, prepClosure(recipes, ingredientIDs))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	consumptions, err = planPrepConsumptions(consumptions, recipes, stock)
+	if err != nil {
+		return nil, nil, err
 	}
 
-	// Update stock for each ingredient
-	for _, productIngredient := range product.Ingredients {
-		if err := os.updateIngredientStock(ctx, tx, productIngredient.IngredientID, productIngredient.Amount, item.Quantity); err != nil {
-			return err
+	return consumptions, recipes, nil
+}
+
+// orderProducts retrieves the product of every order item, in item order,
+// rejecting products taken off the menu. The recipe of each product is the
+// one of the item: the recipe of its variant, with the item modifiers applied.
+func (os *orderService) orderProducts(ctx context.Context, tx repository.Transaction, orderItems []models.OrderItem) ([]*models.Product, error) {
+	products := make([]*models.Product, 0, len(orderItems))
+	for _, item := range orderItems {
+		product, err := os.productRepo.GetProductById(ctx, tx, item.ProductID)
+		if err != nil {
+			return nil, err
+		}
+		// Products running short on stock fail on the deduction itself
+		if product.MarkedUnavailable {
+			return nil, internalErrors.NewAppError(
+				internalErrors.ErrCodeConflict,
+				"Product unavailable",
+				fmt.Sprintf("%s is marked unavailable", product.Name),
+			)
 		}
+		if item.VariantID != nil {
+			if err := os.applyVariant(ctx, tx, product, *item.VariantID); err != nil {
+				return nil, err
+			}
+		}
+		if len(item.Modifiers) > 0 {
+			if err := os.applyModifiers(ctx, tx, product, item.Modifiers); err != nil {
+				return nil, err
+			}
+		}
+		products = append(products, product)
 	}
 
+	return products, nil
+}
+
+// applyVariant replaces the recipe of product with the one of its variant.
+func (os *orderService) applyVariant(ctx context.Context, tx repository.Transaction, product *models.Product, variantID int) error {
+	variant, err := os.productRepo.GetVariant(ctx, tx, variantID)
+	if err != nil {
+		return err
+	}
+	if variant.ProductID != product.ID {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid variant",
+			fmt.Sprintf("Variant %d is not available for %s", variantID, product.Name),
+		)
+	}
+	product.Ingredients = variant.Recipe(product.Ingredients)
 	return nil
 }
 
-func (os *orderService) updateIngredientStock(ctx context.Context, tx repository.Transaction, ingredientID int, amountPerUnit float64, quantity int) error {
-	// Retrieve the ingredient
-	ingredient, err := os.ingredientRepo.GetIngredientByID(ctx, tx, ingredientID)
+// applyModifiers changes the recipe of product as the modifiers of an order
+// item ask, rejecting modifiers the product does not allow.
+func (os *orderService) applyModifiers(ctx context.Context, tx repository.Transaction, product *models.Product, modifierIDs []int) error {
+	allowed, err := os.productRepo.ListModifiers(ctx, tx, product.ID)
 	if err != nil {
 		return err
 	}
+	modifiers := make(map[int]models.ProductModifier, len(allowed))
+	for _, modifier := range allowed {
+		modifiers[modifier.ID] = modifier
+	}
 
-	// Calculate and update stock
-	newStock := ingredient.CurrentStock - (amountPerUnit * float64(quantity))
-	// validate that remaining stock is positive number
-	if newStock < 0 {
-		return internalErrors.NewAppError(internalErrors.ErrCodeInsufficientStock, "Insufficient Ingredient stock", fmt.Sprintf("%s stock is insufficient", ingredient.Name))
+	applied := make(map[int]bool, len(modifierIDs))
+	for _, modifierID := range modifierIDs {
+		modifier, ok := modifiers[modifierID]
+		if !ok {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid modifier",
+				fmt.Sprintf("Modifier %d is not available for %s", modifierID, product.Name),
+			)
+		}
+		if applied[modifierID] {
+			return internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid modifier",
+				fmt.Sprintf("Modifier %d is applied more than once", modifierID),
+			)
+		}
+		applied[modifierID] = true
+		product.Ingredients = applyModifier(product.Ingredients, modifier)
+	}
+
+	return nil
+}
+
+// applyModifier returns the recipe lines with modifier applied.
+func applyModifier(lines []models.ProductIngredient, modifier models.ProductModifier) []models.ProductIngredient {
+	modified := make([]models.ProductIngredient, 0, len(lines)+1)
+	for _, line := range lines {
+		// Removed and replaced ingredients are left out
+		if modifier.Action != models.ModifierActionAdd && line.IngredientID == modifier.IngredientID {
+			continue
+		}
+		modified = append(modified, line)
+	}
+
+	if modifier.Action != models.ModifierActionRemove {
+		modified = append(modified, models.ProductIngredient{
+			ProductID:      modifier.ProductID,
+			IngredientID:   modifier.AmountIngredientID(),
+			Amount:         modifier.Amount,
+			Unit:           modifier.Unit,
+			IngredientUnit: modifier.IngredientUnit,
+			// The ingredient added loses what it always does
+			IngredientYieldPercent: modifier.IngredientYieldPercent,
+		})
+	}
+
+	return modified
+}
+
+// sumConsumptions adds up the ingredients used by the order items made of
+// products in the ingredient base units, sorted by ingredient ID. Amounts
+// include the yield loss of each recipe line, Theoretical leaves it out.
+func sumConsumptions(orderID int, orderItems []models.OrderItem, products []*models.Product) ([]models.OrderConsumption, error) {
+	amounts := make(map[int]float64)
+	theoretical := make(map[int]float64)
+	for i, item := range orderItems {
+		for _, productIngredient := range products[i].Ingredients {
+			amount, err := baseAmount(productIngredient)
+			if err != nil {
+				return nil, err
+			}
+			gross := models.GrossAmount(amount, productIngredient.YieldPercent, productIngredient.IngredientYieldPercent)
+			if gross <= 0 {
+				// Lines using none of their ingredient take nothing from stock, like in the capacity
+				continue
+			}
+			amounts[productIngredient.IngredientID] += gross * float64(item.Quantity)
+			theoretical[productIngredient.IngredientID] += amount * float64(item.Quantity)
+		}
+	}
+
+	consumptions := make([]models.OrderConsumption, 0, len(amounts))
+	for ingredientID, amount := range amounts {
+		consumptions = append(consumptions, models.OrderConsumption{
+			OrderID:      orderID,
+			IngredientID: ingredientID,
+			Amount:       amount,
+			Theoretical:  theoretical[ingredientID],
+		})
 	}
+	sort.Slice(consumptions, func(i, j int) bool {
+		return consumptions[i].IngredientID < consumptions[j].IngredientID
+	})
 
-	if err := os.ingredientRepo.UpdateStock(ctx, tx, ingredientID, newStock); err != nil {
+	return consumptions, nil
+}
+
+// consumeIngredient deducts the stock used by an order and records it in the
+// stock ledger and against the order, so a cancellation can restore it.
+func (os *orderService) consumeIngredient(ctx context.Context, tx repository.Transaction, consumption models.OrderConsumption) error {
+	// Deduct atomically, the repository rejects the update when the remaining stock would be negative
+	if err := os.ingredientRepo.DecrementStock(ctx, tx, consumption.IngredientID, consumption.Amount); err != nil {
 		return err
 	}
 
-	return nil
+	if err := os.movementRepo.RecordMovement(ctx, tx, &models.StockMovement{
+		IngredientID: consumption.IngredientID,
+		Delta:        -consumption.Amount,
+		Reason:       models.MovementReasonOrder,
+		ReferenceID:  &consumption.OrderID,
+	}); err != nil {
+		return err
+	}
+
+	return os.orderRepo.AddOrderConsumption(ctx, tx, consumption)
 }
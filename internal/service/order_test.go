@@ -2,6 +2,10 @@ package service
 
 import (
 	"context"
+	"errors"
+	"math"
+	"sort"
+	internalErrors "stockk/internal/errors"
 	"stockk/internal/models"
 	mockrepository "stockk/internal/repository/mock"
 	"testing"
@@ -10,6 +14,8 @@ import (
 )
 
 func TestCreateOrder(t *testing.T) {
+	doubleID, otherVariantID := 5, 6
+
 	testCases := []struct {
 		name       string
 		input      []models.OrderItem
@@ -17,6 +23,7 @@ func TestCreateOrder(t *testing.T) {
 			orderRepo *mockrepository.MockOrderRepository,
 			productRepo *mockrepository.MockProductRepository,
 			ingredientRepo *mockrepository.MockIngredientRepository,
+			movementRepo *mockrepository.MockStockMovementRepository,
 			tx *mockrepository.MockTransaction,
 		)
 		buildContext func(t *testing.T) context.Context
@@ -34,6 +41,7 @@ func TestCreateOrder(t *testing.T) {
 				orderRepo *mockrepository.MockOrderRepository,
 				productRepo *mockrepository.MockProductRepository,
 				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
 				tx *mockrepository.MockTransaction,
 			) {
 				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
@@ -47,10 +55,17 @@ func TestCreateOrder(t *testing.T) {
 						},
 					}, nil)
 
-				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, gomock.Any()).
-					Return(&models.Ingredient{ID: 1, CurrentStock: 10}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(2)).Return(nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, movement *models.StockMovement) error {
+						if movement.Delta != -2 || movement.Reason != models.MovementReasonOrder {
+							t.Errorf("unexpected movement %+v", movement)
+						}
+						return nil
+					})
 
-				ingredientRepo.EXPECT().UpdateStock(gomock.Any(), tx, gomock.Any(), float64(8)).Return(nil)
+				orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).Return(nil)
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1}).Return(nil, nil)
 
 				tx.EXPECT().Commit().Return(nil) // Expect commit on success
 				tx.EXPECT().Rollback().Times(0)  // No rollback expected in success
@@ -64,6 +79,353 @@ func TestCreateOrder(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "Ingredients Deducted Once In ID Order And Base Unit",
+			input: []models.OrderItem{
+				{ProductID: 1, Quantity: 2},
+				{ProductID: 2, Quantity: 1},
+			},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+						{ProductID: 1, IngredientID: 3, Amount: 20},
+					}}, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 2).
+					Return(&models.Product{ID: 2, Ingredients: []models.ProductIngredient{
+						{ProductID: 2, IngredientID: 1, Amount: 0.15, Unit: "kg", IngredientUnit: models.UnitGram},
+						{ProductID: 2, IngredientID: 3, Amount: 10},
+					}}, nil)
+
+				gomock.InOrder(
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(150)).Return(nil),
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 3, float64(50)).Return(nil),
+				)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil).Times(2)
+				orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).Return(nil).Times(2)
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 3}).Return(nil, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Yield Loss Deducted As Gross Amount",
+			input: []models.OrderItem{
+				{ProductID: 1, Quantity: 2},
+			},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				// Beef uses the ingredient yield, the line yield overrides it for onion
+				beefYield, onionYield, burgerOnionYield := 75.0, 90.0, 80.0
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+						{ProductID: 1, IngredientID: 1, Amount: 150, IngredientYieldPercent: &beefYield},
+						{ProductID: 1, IngredientID: 3, Amount: 20, YieldPercent: &burgerOnionYield, IngredientYieldPercent: &onionYield},
+					}}, nil)
+
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(400)).Return(nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 3, float64(50)).Return(nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil).Times(2)
+				gomock.InOrder(
+					orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).
+						DoAndReturn(func(ctx context.Context, tx any, consumption models.OrderConsumption) error {
+							if consumption.Amount != 400 || consumption.Theoretical != 300 {
+								t.Errorf("unexpected beef consumption %+v", consumption)
+							}
+							return nil
+						}),
+					orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).
+						DoAndReturn(func(ctx context.Context, tx any, consumption models.OrderConsumption) error {
+							if consumption.Amount != 50 || consumption.Theoretical != 40 {
+								t.Errorf("unexpected onion consumption %+v", consumption)
+							}
+							return nil
+						}),
+				)
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 3}).Return(nil, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Modifiers Change Recipe",
+			input: []models.OrderItem{
+				{ProductID: 1, Quantity: 2, Modifiers: []int{7, 8}},
+				{ProductID: 1, Quantity: 1},
+			},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				// A burger of beef (1), cheese (2) and onion (3)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					DoAndReturn(func(ctx context.Context, tx any, productID int) (*models.Product, error) {
+						return &models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+							{ProductID: 1, IngredientID: 1, Amount: 150},
+							{ProductID: 1, IngredientID: 2, Amount: 30},
+							{ProductID: 1, IngredientID: 3, Amount: 20},
+						}}, nil
+					}).Times(2)
+				// Only the modified item lists the modifiers: no onion and cheddar (4) instead of cheese
+				replacementID := 4
+				productRepo.EXPECT().ListModifiers(gomock.Any(), tx, 1).Return([]models.ProductModifier{
+					{ID: 7, ProductID: 1, Action: models.ModifierActionRemove, IngredientID: 3},
+					{ID: 8, ProductID: 1, Action: models.ModifierActionReplace, IngredientID: 2, ReplacementIngredientID: &replacementID, Amount: 0.04, Unit: "kg", IngredientUnit: models.UnitGram},
+				}, nil)
+
+				gomock.InOrder(
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(450)).Return(nil),
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 2, float64(30)).Return(nil),
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 3, float64(20)).Return(nil),
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 4, float64(80)).Return(nil),
+				)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil).Times(4)
+				orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).Return(nil).Times(4)
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 2, 3, 4}).Return(nil, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Modifier Of Another Product Rejected",
+			input: []models.OrderItem{
+				{ProductID: 1, Quantity: 1, Modifiers: []int{9}},
+			},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Name: "Burger", Ingredients: []models.ProductIngredient{
+						{ProductID: 1, IngredientID: 1, Amount: 150},
+					}}, nil)
+				productRepo.EXPECT().ListModifiers(gomock.Any(), tx, 1).Return([]models.ProductModifier{
+					{ID: 7, ProductID: 1, Action: models.ModifierActionRemove, IngredientID: 1},
+				}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Errorf("expected validation error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Variant Scales Recipe",
+			input: []models.OrderItem{
+				{ProductID: 1, VariantID: &doubleID, Quantity: 1, Modifiers: []int{7}},
+				{ProductID: 1, Quantity: 1},
+			},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				// A burger of beef (1), cheese (2) and onion (3)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					DoAndReturn(func(ctx context.Context, tx any, productID int) (*models.Product, error) {
+						return &models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+							{ProductID: 1, IngredientID: 1, Amount: 150},
+							{ProductID: 1, IngredientID: 2, Amount: 30, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+							{ProductID: 1, IngredientID: 3, Amount: 20},
+						}}, nil
+					}).Times(2)
+				// The double takes twice the beef and onion but only 45 g of cheese,
+				// the modifier then leaves the onion out
+				productRepo.EXPECT().GetVariant(gomock.Any(), tx, doubleID).Return(&models.ProductVariant{
+					ID: doubleID, ProductID: 1, Name: "Double", Multiplier: 2, Overrides: []models.VariantIngredient{
+						{VariantID: doubleID, IngredientID: 2, Amount: 45, Unit: models.UnitGram},
+					},
+				}, nil)
+				productRepo.EXPECT().ListModifiers(gomock.Any(), tx, 1).Return([]models.ProductModifier{
+					{ID: 7, ProductID: 1, Action: models.ModifierActionRemove, IngredientID: 3},
+				}, nil)
+
+				gomock.InOrder(
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(450)).Return(nil),
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 2, float64(75)).Return(nil),
+					ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 3, float64(20)).Return(nil),
+				)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil).Times(3)
+				orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).Return(nil).Times(3)
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 2, 3}).Return(nil, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Variant Of Another Product Rejected",
+			input: []models.OrderItem{
+				{ProductID: 1, VariantID: &otherVariantID, Quantity: 1},
+			},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Name: "Burger", Ingredients: []models.ProductIngredient{
+						{ProductID: 1, IngredientID: 1, Amount: 150},
+					}}, nil)
+				productRepo.EXPECT().GetVariant(gomock.Any(), tx, otherVariantID).
+					Return(&models.ProductVariant{ID: otherVariantID, ProductID: 2, Name: "Large", Multiplier: 1.5}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Errorf("expected validation error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Insufficient Stock Rolls Back",
+			input: []models.OrderItem{
+				{ProductID: 1, Quantity: 1},
+			},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+						{ProductID: 1, IngredientID: 1, Amount: 150},
+					}}, nil)
+
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(150)).
+					Return(internalErrors.NewAppError(internalErrors.ErrCodeInsufficientStock, "Insufficient Ingredient stock"))
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeInsufficientStock {
+					t.Errorf("expected insufficient stock error, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "Product Marked Unavailable",
+			input: []models.OrderItem{{ProductID: 1, Quantity: 1}},
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Name: "Burger", InStock: true, MarkedUnavailable: true}, nil)
+
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			buildContext: func(t *testing.T) context.Context {
+				return context.Background()
+			},
+			checkResult: func(t *testing.T, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeConflict {
+					t.Errorf("expected conflict error, got %v", err)
+				}
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -74,11 +436,16 @@ func TestCreateOrder(t *testing.T) {
 			orderRepo := mockrepository.NewMockOrderRepository(ctrl)
 			productRepo := mockrepository.NewMockProductRepository(ctrl)
 			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			idempotencyRepo := mockrepository.NewMockIdempotencyRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
 			tx := mockrepository.NewMockTransaction(ctrl)
 
-			tc.buildStubs(orderRepo, productRepo, ingredientRepo, tx)
+			tc.buildStubs(orderRepo, productRepo, ingredientRepo, movementRepo, tx)
 
-			os := NewOrderService(orderRepo, productRepo, ingredientRepo)
+			os := NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
 
 			ctx := tc.buildContext(t)
 			_, err := os.CreateOrder(ctx, tc.input)
@@ -86,3 +453,583 @@ func TestCreateOrder(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateOrderWithPrepItems(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	orderRepo := mockrepository.NewMockOrderRepository(ctrl)
+	productRepo := mockrepository.NewMockProductRepository(ctrl)
+	ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+	movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+	idempotencyRepo := mockrepository.NewMockIdempotencyRepository(ctrl)
+	outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+	prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+	tx := mockrepository.NewMockTransaction(ctrl)
+
+	orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+	orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx any, order *models.Order) error {
+			order.ID = 5
+			return nil
+		})
+
+	// A burger takes 100 g of sauce (ingredient 4) and a bun (ingredient 3)
+	productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+		Return(&models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+			{ProductID: 1, IngredientID: 3, Amount: 1},
+			{ProductID: 1, IngredientID: 4, Amount: 100},
+		}}, nil)
+
+	// One batch of sauce makes 500 g from 0.4 kg of tomato (ingredient 1) and
+	// 100 g of onion base (ingredient 5), itself a prep made of onion (ingredient 2)
+	prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), tx).Return([]models.PrepRecipe{
+		{IngredientID: 4, Yield: 500, Ingredients: []models.PrepIngredient{
+			{PrepID: 4, IngredientID: 1, Amount: 0.4, Unit: "kg", IngredientUnit: models.UnitGram},
+			{PrepID: 4, IngredientID: 5, Amount: 100, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+		}},
+		{IngredientID: 5, Yield: 50, Ingredients: []models.PrepIngredient{
+			{PrepID: 5, IngredientID: 2, Amount: 100, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+		}},
+	}, nil)
+
+	// 300 g of sauce are prepared for three burgers, the other 200 g are made
+	// from 160 g of tomato and 40 g of onion base, which has 10 g prepared and
+	// makes the other 30 g from 60 g of onion
+	ingredientRepo.EXPECT().LockStock(gomock.Any(), tx, []int{1, 2, 3, 4, 5}).
+		Return(map[int]float64{1: 1000, 2: 1000, 3: 10, 4: 300, 5: 10}, nil)
+
+	expected := map[int]float64{1: 160, 2: 60, 3: 5, 4: 300, 5: 10}
+	var deducted []int
+	ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx any, ingredientID int, amount float64) error {
+			if math.Abs(amount-expected[ingredientID]) > 1e-9 {
+				t.Errorf("expected %v of ingredient %d, got %v", expected[ingredientID], ingredientID, amount)
+			}
+			deducted = append(deducted, ingredientID)
+			return nil
+		}).Times(5)
+	movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil).Times(5)
+	orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).
+		DoAndReturn(func(ctx context.Context, tx any, consumption models.OrderConsumption) error {
+			if consumption.OrderID != 5 {
+				t.Errorf("expected consumption of order 5, got %+v", consumption)
+			}
+			return nil
+		}).Times(5)
+	ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 2, 3, 4, 5}).Return(nil, nil)
+
+	tx.EXPECT().Commit().Return(nil)
+	tx.EXPECT().Rollback().Times(0)
+
+	os := NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
+
+	if _, err := os.CreateOrder(context.Background(), []models.OrderItem{{ProductID: 1, Quantity: 5}}); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if !sort.IntsAreSorted(deducted) {
+		t.Errorf("expected deductions in ingredient ID order, got %v", deducted)
+	}
+}
+
+func TestSumConsumptionsSkipsZeroAmountLines(t *testing.T) {
+	// The truffle line of the plate uses none of it, so it takes nothing from stock
+	products := []*models.Product{
+		{ID: 5, Ingredients: []models.ProductIngredient{
+			{ProductID: 5, IngredientID: 3, Amount: 0},
+			{ProductID: 5, IngredientID: 2, Amount: 0.1, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+		}},
+	}
+
+	consumptions, err := sumConsumptions(5, []models.OrderItem{{ProductID: 5, Quantity: 2}}, products)
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(consumptions) != 1 || consumptions[0].IngredientID != 2 || math.Abs(consumptions[0].Amount-0.2) > 1e-9 {
+		t.Errorf("expected 0.2 of ingredient 2 only, got %+v", consumptions)
+	}
+}
+
+func TestCreateOrderIdempotent(t *testing.T) {
+	storedOrder := []byte(`{"id":7,"status":"placed","items":[{"product_id":1,"quantity":1}],"created_at":"2024-01-01T10:00:00Z"}`)
+
+	testCases := []struct {
+		name       string
+		buildStubs func(
+			orderRepo *mockrepository.MockOrderRepository,
+			productRepo *mockrepository.MockProductRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			movementRepo *mockrepository.MockStockMovementRepository,
+			idempotencyRepo *mockrepository.MockIdempotencyRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, order *models.Order, replayed bool, err error)
+	}{
+		{
+			name: "First Request Creates Order",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				idempotencyRepo *mockrepository.MockIdempotencyRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				idempotencyRepo.EXPECT().ClaimKey(gomock.Any(), tx, "key-1", "hash-1").Return(nil, nil)
+
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, order *models.Order) error {
+						order.ID = 7
+						return nil
+					})
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+						{ProductID: 1, IngredientID: 1, Amount: 150},
+					}}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(150)).Return(nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil)
+				orderRepo.EXPECT().AddOrderConsumption(gomock.Any(), tx, gomock.Any()).Return(nil)
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1}).Return(nil, nil)
+
+				idempotencyRepo.EXPECT().SaveResponse(gomock.Any(), tx, "key-1", gomock.Any()).Return(nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, order *models.Order, replayed bool, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if replayed || order.ID != 7 {
+					t.Errorf("expected new order 7, got %+v (replayed %v)", order, replayed)
+				}
+			},
+		},
+		{
+			name: "Repeated Request Replays Order",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				idempotencyRepo *mockrepository.MockIdempotencyRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				idempotencyRepo.EXPECT().ClaimKey(gomock.Any(), tx, "key-1", "hash-1").
+					Return(&models.IdempotencyKey{Key: "key-1", RequestHash: "hash-1", Response: storedOrder}, nil)
+
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, order *models.Order, replayed bool, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if !replayed || order.ID != 7 || len(order.Items) != 1 {
+					t.Errorf("expected replayed order 7, got %+v (replayed %v)", order, replayed)
+				}
+			},
+		},
+		{
+			name: "Different Request Rejected",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				idempotencyRepo *mockrepository.MockIdempotencyRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				idempotencyRepo.EXPECT().ClaimKey(gomock.Any(), tx, "key-1", "hash-1").
+					Return(&models.IdempotencyKey{Key: "key-1", RequestHash: "hash-2", Response: storedOrder}, nil)
+
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil).Times(1)
+			},
+			checkResult: func(t *testing.T, order *models.Order, replayed bool, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeUnprocessable {
+					t.Errorf("expected unprocessable error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mockrepository.NewMockOrderRepository(ctrl)
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			idempotencyRepo := mockrepository.NewMockIdempotencyRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, tx)
+
+			os := NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
+
+			order, replayed, err := os.CreateOrderIdempotent(context.Background(), "key-1", "hash-1", []models.OrderItem{{ProductID: 1, Quantity: 1}})
+			tc.checkResult(t, order, replayed, err)
+		})
+	}
+}
+
+func TestPreviewOrder(t *testing.T) {
+	burger := &models.Product{
+		ID: 1,
+		Ingredients: []models.ProductIngredient{
+			{ProductID: 1, IngredientID: 1, Amount: 150},
+			{ProductID: 1, IngredientID: 2, Amount: 30},
+		},
+	}
+	percentage := models.LowStockThreshold{Type: models.ThresholdTypePercentage, Value: 50, Default: true}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(
+			orderRepo *mockrepository.MockOrderRepository,
+			productRepo *mockrepository.MockProductRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, preview *models.OrderPreview, err error)
+	}{
+		{
+			name: "Feasible Order Crossing Threshold",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(burger, nil)
+
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 1).
+					Return(&models.Ingredient{ID: 1, Name: "Beef", TotalStock: 1000, CurrentStock: 600, LowStockThreshold: percentage}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(300)).Return(nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 2).
+					Return(&models.Ingredient{ID: 2, Name: "Cheese", TotalStock: 1000, CurrentStock: 1000, LowStockThreshold: percentage}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 2, float64(60)).Return(nil)
+
+				// Nothing is placed, the preview always rolls back
+				orderRepo.EXPECT().CreateOrder(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, preview *models.OrderPreview, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if !preview.Feasible || len(preview.InsufficientItems) != 0 {
+					t.Errorf("expected a feasible order, got %+v", preview)
+				}
+				if len(preview.Ingredients) != 2 {
+					t.Fatalf("expected 2 projections, got %d", len(preview.Ingredients))
+				}
+				if beef := preview.Ingredients[0]; beef.ProjectedStock != 300 || !beef.CrossesThreshold {
+					t.Errorf("expected beef to drop to 300 below its threshold, got %+v", beef)
+				}
+				if cheese := preview.Ingredients[1]; cheese.ProjectedStock != 940 || cheese.CrossesThreshold {
+					t.Errorf("expected cheese to stay above its threshold, got %+v", cheese)
+				}
+			},
+		},
+		{
+			name: "Insufficient Stock Reported",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(burger, nil)
+
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 1).
+					Return(&models.Ingredient{ID: 1, Name: "Beef", TotalStock: 1000, CurrentStock: 200, LowStockThreshold: percentage}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(300)).
+					Return(internalErrors.NewAppError(internalErrors.ErrCodeInsufficientStock, "Insufficient Ingredient stock"))
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 2).
+					Return(&models.Ingredient{ID: 2, Name: "Cheese", TotalStock: 1000, CurrentStock: 1000, LowStockThreshold: percentage}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 2, float64(60)).Return(nil)
+
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, preview *models.OrderPreview, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if preview.Feasible {
+					t.Errorf("expected an infeasible order")
+				}
+				if !preview.Ingredients[0].Insufficient || preview.Ingredients[0].ProjectedStock != -100 {
+					t.Errorf("expected beef to be insufficient, got %+v", preview.Ingredients[0])
+				}
+				if len(preview.InsufficientItems) != 1 || preview.InsufficientItems[0].IngredientIDs[0] != 1 {
+					t.Errorf("expected the burger to fail on beef, got %+v", preview.InsufficientItems)
+				}
+			},
+		},
+		{
+			name: "Yield Loss Reported Apart",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				beefYield := 75.0
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(&models.Product{ID: 1, Ingredients: []models.ProductIngredient{
+						{ProductID: 1, IngredientID: 1, Amount: 150, IngredientYieldPercent: &beefYield},
+					}}, nil)
+
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 1).
+					Return(&models.Ingredient{ID: 1, Name: "Beef", TotalStock: 1000, CurrentStock: 1000, LowStockThreshold: percentage}, nil)
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 1, float64(400)).Return(nil)
+
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, preview *models.OrderPreview, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if beef := preview.Ingredients[0]; beef.Theoretical != 300 || beef.Required != 400 || beef.ProjectedStock != 600 {
+					t.Errorf("expected 300 g of beef to need 400 g of stock, got %+v", beef)
+				}
+			},
+		},
+		{
+			name: "Product Not Found",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).
+					Return(nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found"))
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, preview *models.OrderPreview, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeNotFound {
+					t.Errorf("expected not found error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mockrepository.NewMockOrderRepository(ctrl)
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			idempotencyRepo := mockrepository.NewMockIdempotencyRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(orderRepo, productRepo, ingredientRepo, tx)
+
+			os := NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
+
+			preview, err := os.PreviewOrder(context.Background(), []models.OrderItem{{ProductID: 1, Quantity: 2}})
+			tc.checkResult(t, preview, err)
+		})
+	}
+}
+
+func TestListOrders(t *testing.T) {
+	testCases := []struct {
+		name        string
+		buildStubs  func(orderRepo *mockrepository.MockOrderRepository)
+		checkResult func(t *testing.T, page *models.OrderPage, err error)
+	}{
+		{
+			name: "Has Next Page",
+			buildStubs: func(orderRepo *mockrepository.MockOrderRepository) {
+				orderRepo.EXPECT().ListOrders(gomock.Any(), models.OrderFilter{Limit: 3}).
+					Return([]models.Order{{ID: 9}, {ID: 8}, {ID: 5}}, nil)
+			},
+			checkResult: func(t *testing.T, page *models.OrderPage, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(page.Orders) != 2 {
+					t.Errorf("expected 2 orders, got %d", len(page.Orders))
+				}
+				if page.NextCursor == nil || *page.NextCursor != 8 {
+					t.Errorf("expected next cursor 8, got %v", page.NextCursor)
+				}
+			},
+		},
+		{
+			name: "Last Page",
+			buildStubs: func(orderRepo *mockrepository.MockOrderRepository) {
+				orderRepo.EXPECT().ListOrders(gomock.Any(), models.OrderFilter{Limit: 3}).
+					Return([]models.Order{{ID: 2}}, nil)
+			},
+			checkResult: func(t *testing.T, page *models.OrderPage, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(page.Orders) != 1 || page.NextCursor != nil {
+					t.Errorf("unexpected page %+v", page)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mockrepository.NewMockOrderRepository(ctrl)
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			idempotencyRepo := mockrepository.NewMockIdempotencyRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+
+			tc.buildStubs(orderRepo)
+
+			os := NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
+
+			page, err := os.ListOrders(context.Background(), models.OrderFilter{Limit: 2})
+			tc.checkResult(t, page, err)
+		})
+	}
+}
+
+func TestCancelOrder(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(
+			orderRepo *mockrepository.MockOrderRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			movementRepo *mockrepository.MockStockMovementRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, order *models.Order, err error)
+	}{
+		{
+			name: "Success Cancel Order",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CancelOrder(gomock.Any(), tx, 1).Return(nil)
+				orderRepo.EXPECT().GetOrderConsumptions(gomock.Any(), tx, 1).Return([]models.OrderConsumption{
+					{OrderID: 1, IngredientID: 1, Amount: 300},
+					{OrderID: 1, IngredientID: 2, Amount: 60},
+				}, nil)
+
+				ingredientRepo.EXPECT().IncrementStock(gomock.Any(), tx, 1, float64(300)).Return(nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, movement *models.StockMovement) error {
+						if movement.Delta != 300 || movement.Reason != models.MovementReasonCancellation || *movement.ReferenceID != 1 {
+							t.Errorf("unexpected movement %+v", movement)
+						}
+						return nil
+					})
+				ingredientRepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), tx, 1).Return(nil)
+				ingredientRepo.EXPECT().IncrementStock(gomock.Any(), tx, 2, float64(60)).Return(nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil)
+				ingredientRepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), tx, 2).Return(nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+
+				orderRepo.EXPECT().GetOrderByID(gomock.Any(), 1).
+					Return(&models.Order{ID: 1, Status: models.OrderStatusCancelled}, nil)
+			},
+			checkResult: func(t *testing.T, order *models.Order, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if order.Status != models.OrderStatusCancelled {
+					t.Errorf("expected cancelled order, got %s", order.Status)
+				}
+			},
+		},
+		{
+			name: "Already Cancelled",
+			buildStubs: func(
+				orderRepo *mockrepository.MockOrderRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				orderRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				orderRepo.EXPECT().CancelOrder(gomock.Any(), tx, 1).
+					Return(internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Order cannot be cancelled"))
+
+				ingredientRepo.EXPECT().IncrementStock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, order *models.Order, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeConflict {
+					t.Errorf("expected conflict error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			orderRepo := mockrepository.NewMockOrderRepository(ctrl)
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			idempotencyRepo := mockrepository.NewMockIdempotencyRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(orderRepo, ingredientRepo, movementRepo, tx)
+
+			os := NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
+
+			order, err := os.CancelOrder(context.Background(), 1)
+			tc.checkResult(t, order, err)
+		})
+	}
+}
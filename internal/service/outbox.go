@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/repository"
+
+	"github.com/hibiken/asynq"
+)
+
+// outboxBatchSize is the maximum number of messages published per dispatch.
+const outboxBatchSize = 100
+
+type OutboxService interface {
+	DispatchPending(ctx context.Context) (int, error)
+}
+
+type outboxService struct {
+	outboxRepo repository.OutboxRepository
+	taskRepo   repository.TaskQueueRepository
+}
+
+func NewOutboxService(outboxRepo repository.OutboxRepository, taskRepo repository.TaskQueueRepository) OutboxService {
+	return &outboxService{outboxRepo: outboxRepo, taskRepo: taskRepo}
+}
+
+var _ OutboxService = (*outboxService)(nil)
+
+// DispatchPending publishes pending outbox messages to the task queue and
+// returns how many were published. Publishing stops at the first failure,
+// which usually means the queue is unavailable, and the remaining messages
+// are retried on the next dispatch.
+func (s *outboxService) DispatchPending(ctx context.Context) (dispatched int, err error) {
+	tx, err := s.outboxRepo.BeginTransaction()
+	if err != nil {
+		return 0, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	messages, err := s.outboxRepo.ListPendingMessages(ctx, tx, outboxBatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, message := range messages {
+		// The task ID makes the queue drop a message published twice, e.g. when
+		// the relay stopped before marking it dispatched
+		publishErr := s.taskRepo.EnqueueTask(ctx, message.TaskType, message.Payload, asynq.TaskID(outboxTaskID(message.ID)))
+		if publishErr != nil && !errors.Is(publishErr, asynq.ErrTaskIDConflict) {
+			slog.Warn("failed to publish outbox message", "messageID", message.ID, "attempts", message.Attempts+1, "error", publishErr)
+			if err = s.outboxRepo.RecordFailure(ctx, tx, message.ID, publishErr.Error()); err != nil {
+				return 0, err
+			}
+			break
+		}
+
+		if err = s.outboxRepo.MarkDispatched(ctx, tx, message.ID); err != nil {
+			return 0, err
+		}
+		dispatched++
+	}
+
+	if err = tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	return dispatched, nil
+}
+
+func outboxTaskID(messageID int) string {
+	return fmt.Sprintf("outbox:%d", messageID)
+}
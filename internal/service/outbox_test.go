@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"stockk/internal/models"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDispatchPending(t *testing.T) {
+	pending := []models.OutboxMessage{
+		{ID: 1, TaskType: "task:a", Payload: []byte(`{}`)},
+		{ID: 2, TaskType: "task:b", Payload: []byte(`{}`)},
+	}
+
+	testCases := []struct {
+		name       string
+		buildStubs func(
+			outboxRepo *mockrepository.MockOutboxRepository,
+			taskRepo *mockrepository.MockTaskQueueRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, dispatched int, err error)
+	}{
+		{
+			name: "All Messages Published",
+			buildStubs: func(
+				outboxRepo *mockrepository.MockOutboxRepository,
+				taskRepo *mockrepository.MockTaskQueueRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				outboxRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				outboxRepo.EXPECT().ListPendingMessages(gomock.Any(), tx, outboxBatchSize).Return(pending, nil)
+
+				taskRepo.EXPECT().EnqueueTask(gomock.Any(), "task:a", gomock.Any(), gomock.Any()).Return(nil)
+				outboxRepo.EXPECT().MarkDispatched(gomock.Any(), tx, 1).Return(nil)
+				taskRepo.EXPECT().EnqueueTask(gomock.Any(), "task:b", gomock.Any(), gomock.Any()).Return(nil)
+				outboxRepo.EXPECT().MarkDispatched(gomock.Any(), tx, 2).Return(nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, dispatched int, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if dispatched != 2 {
+					t.Errorf("expected 2 dispatched messages, got %d", dispatched)
+				}
+			},
+		},
+		{
+			name: "Queue Unavailable Keeps Messages Pending",
+			buildStubs: func(
+				outboxRepo *mockrepository.MockOutboxRepository,
+				taskRepo *mockrepository.MockTaskQueueRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				outboxRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				outboxRepo.EXPECT().ListPendingMessages(gomock.Any(), tx, outboxBatchSize).Return(pending, nil)
+
+				taskRepo.EXPECT().EnqueueTask(gomock.Any(), "task:a", gomock.Any(), gomock.Any()).Return(errors.New("redis down"))
+				outboxRepo.EXPECT().RecordFailure(gomock.Any(), tx, 1, "redis down").Return(nil)
+				outboxRepo.EXPECT().MarkDispatched(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				taskRepo.EXPECT().EnqueueTask(gomock.Any(), "task:b", gomock.Any(), gomock.Any()).Times(0)
+
+				// The failure is recorded, so the transaction still commits
+				tx.EXPECT().Commit().Return(nil)
+			},
+			checkResult: func(t *testing.T, dispatched int, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if dispatched != 0 {
+					t.Errorf("expected no dispatched messages, got %d", dispatched)
+				}
+			},
+		},
+		{
+			name: "Already Published Message Marked Dispatched",
+			buildStubs: func(
+				outboxRepo *mockrepository.MockOutboxRepository,
+				taskRepo *mockrepository.MockTaskQueueRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				outboxRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				outboxRepo.EXPECT().ListPendingMessages(gomock.Any(), tx, outboxBatchSize).Return(pending[:1], nil)
+
+				taskRepo.EXPECT().EnqueueTask(gomock.Any(), "task:a", gomock.Any(), gomock.Any()).
+					Return(fmt.Errorf("failed to enqueue task: %w", asynq.ErrTaskIDConflict))
+				outboxRepo.EXPECT().MarkDispatched(gomock.Any(), tx, 1).Return(nil)
+
+				tx.EXPECT().Commit().Return(nil)
+			},
+			checkResult: func(t *testing.T, dispatched int, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if dispatched != 1 {
+					t.Errorf("expected 1 dispatched message, got %d", dispatched)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			taskRepo := mockrepository.NewMockTaskQueueRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(outboxRepo, taskRepo, tx)
+
+			s := NewOutboxService(outboxRepo, taskRepo)
+
+			dispatched, err := s.DispatchPending(context.Background())
+			tc.checkResult(t, dispatched, err)
+		})
+	}
+}
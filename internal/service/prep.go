@@ -0,0 +1,211 @@
+package service
+
+import (
+	"log/slog"
+	"math"
+	"sort"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+)
+
+// maxPrepDepth bounds the nesting of prep items. Cycles are rejected when a
+// recipe is saved, the bound only guards the expansion against bad data.
+const maxPrepDepth = 32
+
+// indexPrepRecipes maps prep recipes by the ID of their prep item.
+func indexPrepRecipes(recipes []models.PrepRecipe) map[int]models.PrepRecipe {
+	index := make(map[int]models.PrepRecipe, len(recipes))
+	for _, recipe := range recipes {
+		index[recipe.IngredientID] = recipe
+	}
+	return index
+}
+
+// findPrepCycle returns the prep items leading from prepID back to itself
+// through recipes, or nil when prepID is not part of a cycle.
+func findPrepCycle(recipes map[int]models.PrepRecipe, prepID int) []int {
+	visited := make(map[int]bool)
+	var path []int
+
+	var visit func(ingredientID int) bool
+	visit = func(ingredientID int) bool {
+		path = append(path, ingredientID)
+		for _, line := range recipes[ingredientID].Ingredients {
+			if line.IngredientID == prepID {
+				path = append(path, prepID)
+				return true
+			}
+			if _, ok := recipes[line.IngredientID]; ok && !visited[line.IngredientID] {
+				visited[line.IngredientID] = true
+				if visit(line.IngredientID) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		return false
+	}
+
+	if visit(prepID) {
+		return path
+	}
+	return nil
+}
+
+// prepClosure returns ingredientIDs together with every ingredient their prep
+// recipes use, directly or through other prep items, sorted by ID.
+func prepClosure(recipes map[int]models.PrepRecipe, ingredientIDs []int) []int {
+	seen := make(map[int]bool)
+	var visit func(ingredientID int)
+	visit = func(ingredientID int) {
+		if seen[ingredientID] {
+			return
+		}
+		seen[ingredientID] = true
+		for _, line := range recipes[ingredientID].Ingredients {
+			visit(line.IngredientID)
+		}
+	}
+	for _, ingredientID := range ingredientIDs {
+		visit(ingredientID)
+	}
+
+	closure := make([]int, 0, len(seen))
+	for ingredientID := range seen {
+		closure = append(closure, ingredientID)
+	}
+	sort.Ints(closure)
+	return closure
+}
+
+// planPrepConsumptions replaces the demand for prep items beyond their
+// prepared stock with the ingredients their recipes need to make the rest.
+// stock must hold the current stock of the whole prep closure of
+// consumptions, the result is sorted by ingredient ID. The theoretical
+// amounts follow the same plan without the yield loss.
+func planPrepConsumptions(consumptions []models.OrderConsumption, recipes map[int]models.PrepRecipe, stock map[int]float64) ([]models.OrderConsumption, error) {
+	amounts := make(map[int]float64)
+	theoretical := make(map[int]float64)
+
+	// share is the theoretical part of amount
+	var expand func(ingredientID int, amount float64, share float64, depth int) error
+	expand = func(ingredientID int, amount float64, share float64, depth int) error {
+		recipe, ok := recipes[ingredientID]
+		if !ok {
+			amounts[ingredientID] += amount
+			theoretical[ingredientID] += amount * share
+			return nil
+		}
+		if depth > maxPrepDepth {
+			slog.Error("prep recipes nested too deep", "ingredientID", ingredientID)
+			return internalErrors.Wrap(internalErrors.ErrInternalServer, "prep recipes nested too deep")
+		}
+
+		// Take what is already prepared first
+		taken := math.Max(math.Min(stock[ingredientID], amount), 0)
+		stock[ingredientID] -= taken
+		amounts[ingredientID] += taken
+		theoretical[ingredientID] += taken * share
+
+		remainder := amount - taken
+		if remainder <= capacityEpsilon {
+			return nil
+		}
+		// Nothing to make it from, the shortage surfaces on the prep item itself
+		if len(recipe.Ingredients) == 0 {
+			amounts[ingredientID] += remainder
+			theoretical[ingredientID] += remainder * share
+			return nil
+		}
+
+		batches := remainder / recipe.Yield
+		for _, line := range recipe.Ingredients {
+			lineAmount, err := prepBaseAmount(line)
+			if err != nil {
+				return err
+			}
+			gross := models.GrossAmount(lineAmount, line.YieldPercent, line.IngredientYieldPercent)
+			if err := expand(line.IngredientID, gross*batches, share*lineAmount/gross, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	for _, consumption := range consumptions {
+		share := 1.0
+		if consumption.Amount > 0 {
+			share = consumption.Theoretical / consumption.Amount
+		}
+		if err := expand(consumption.IngredientID, consumption.Amount, share, 0); err != nil {
+			return nil, err
+		}
+	}
+
+	var orderID int
+	if len(consumptions) > 0 {
+		orderID = consumptions[0].OrderID
+	}
+	planned := make([]models.OrderConsumption, 0, len(amounts))
+	for ingredientID, amount := range amounts {
+		if amount <= 0 {
+			continue
+		}
+		planned = append(planned, models.OrderConsumption{
+			OrderID:      orderID,
+			IngredientID: ingredientID,
+			Amount:       amount,
+			Theoretical:  theoretical[ingredientID],
+		})
+	}
+	sort.Slice(planned, func(i, j int) bool {
+		return planned[i].IngredientID < planned[j].IngredientID
+	})
+
+	return planned, nil
+}
+
+// availableAmount returns how much of an ingredient the current stock offers,
+// counting for a prep item what its recipe can still make on top of its
+// prepared stock. Ingredients shared by several recipe lines are counted in
+// full for each of them. Ingredients missing from stock, such as archived ones,
+// offer nothing.
+func availableAmount(ingredientID int, recipes map[int]models.PrepRecipe, stock map[int]float64, depth int) (float64, error) {
+	if _, ok := stock[ingredientID]; !ok {
+		return 0, nil
+	}
+	recipe, ok := recipes[ingredientID]
+	if !ok || len(recipe.Ingredients) == 0 {
+		return stock[ingredientID], nil
+	}
+	if depth > maxPrepDepth {
+		slog.Error("prep recipes nested too deep", "ingredientID", ingredientID)
+		return 0, internalErrors.Wrap(internalErrors.ErrInternalServer, "prep recipes nested too deep")
+	}
+
+	batches := math.Inf(1)
+	for _, line := range recipe.Ingredients {
+		lineAmount, err := prepBaseAmount(line)
+		if err != nil {
+			return 0, err
+		}
+		available, err := availableAmount(line.IngredientID, recipes, stock, depth+1)
+		if err != nil {
+			return 0, err
+		}
+		gross := models.GrossAmount(lineAmount, line.YieldPercent, line.IngredientYieldPercent)
+		batches = math.Min(batches, math.Max(available, 0)/gross)
+	}
+
+	return stock[ingredientID] + batches*recipe.Yield, nil
+}
+
+// prepBaseAmount converts a prep recipe amount to the base unit of its ingredient.
+func prepBaseAmount(line models.PrepIngredient) (float64, error) {
+	amount, err := line.BaseAmount()
+	if err != nil {
+		slog.Error("failed to convert prep recipe amount", "prepID", line.PrepID, "ingredientID", line.IngredientID, "error", err)
+		return 0, internalErrors.Wrap(internalErrors.ErrInternalServer, "invalid recipe unit")
+	}
+	return amount, nil
+}
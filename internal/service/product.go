@@ -0,0 +1,584 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	"strings"
+)
+
+type ProductService interface {
+	ListProducts(ctx context.Context) ([]models.Product, error)
+	GetProduct(ctx context.Context, productID int) (*models.Product, error)
+	CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error)
+	SetProductIngredient(ctx context.Context, productIngredient models.ProductIngredient) (*models.Product, error)
+	RemoveProductIngredient(ctx context.Context, productID int, ingredientID int) (*models.Product, error)
+	ReplaceProductIngredients(ctx context.Context, productID int, ingredients []models.ProductIngredient) (*models.Product, error)
+	GetProductCapacity(ctx context.Context, productID int, variantID *int) (*models.ProductCapacity, error)
+	ListProductCapacities(ctx context.Context) ([]models.ProductCapacity, error)
+	ListMenuAvailability(ctx context.Context) ([]models.MenuItemAvailability, error)
+	SetProductMarkedUnavailable(ctx context.Context, productID int, unavailable bool) (*models.Product, error)
+	ListModifiers(ctx context.Context, productID int) ([]models.ProductModifier, error)
+	CreateModifier(ctx context.Context, modifier *models.ProductModifier) (*models.ProductModifier, error)
+	DeleteModifier(ctx context.Context, productID int, modifierID int) error
+	ListVariants(ctx context.Context, productID int) ([]models.ProductVariant, error)
+	CreateVariant(ctx context.Context, variant *models.ProductVariant) (*models.ProductVariant, error)
+	DeleteVariant(ctx context.Context, productID int, variantID int) error
+}
+
+type productService struct {
+	productRepo    repository.ProductRepository
+	ingredientRepo repository.IngredientRepository
+	prepRepo       repository.PrepRecipeRepository
+}
+
+func NewProductService(productRepo repository.ProductRepository, ingredientRepo repository.IngredientRepository, prepRepo repository.PrepRecipeRepository) ProductService {
+	return &productService{productRepo: productRepo, ingredientRepo: ingredientRepo, prepRepo: prepRepo}
+}
+
+var _ ProductService = (*productService)(nil)
+
+// ListProducts returns every product with its recipe and whether the current
+// stock can make it.
+func (ps *productService) ListProducts(ctx context.Context) ([]models.Product, error) {
+	products, err := ps.productRepo.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes, stock, err := ps.menuStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range products {
+		if err := setInStock(&products[i], recipes, stock); err != nil {
+			return nil, err
+		}
+	}
+
+	return products, nil
+}
+
+func (ps *productService) GetProduct(ctx context.Context, productID int) (*models.Product, error) {
+	product, err := ps.productRepo.GetProductById(ctx, nil, productID)
+	if err != nil {
+		return nil, err
+	}
+	return ps.withStock(ctx, nil, product)
+}
+
+// CreateProduct stores a product together with its recipe in a single transaction.
+func (ps *productService) CreateProduct(ctx context.Context, product *models.Product) (*models.Product, error) {
+	product.Name = strings.TrimSpace(product.Name)
+	return ps.inTransaction(ctx, func(tx repository.Transaction) (int, error) {
+		if err := ps.productRepo.CreateProduct(ctx, tx, product); err != nil {
+			return 0, err
+		}
+		return product.ID, ps.setIngredients(ctx, tx, product.ID, product.Ingredients)
+	})
+}
+
+// SetProductIngredient attaches an ingredient to a product or changes its amount.
+func (ps *productService) SetProductIngredient(ctx context.Context, productIngredient models.ProductIngredient) (*models.Product, error) {
+	return ps.inTransaction(ctx, func(tx repository.Transaction) (int, error) {
+		// Make sure the product exists so a foreign key failure points at the ingredient
+		if _, err := ps.productRepo.GetProductById(ctx, tx, productIngredient.ProductID); err != nil {
+			return 0, err
+		}
+		if err := ps.resolveUnit(ctx, tx, &productIngredient); err != nil {
+			return 0, err
+		}
+		return productIngredient.ProductID, ps.productRepo.SetProductIngredient(ctx, tx, productIngredient)
+	})
+}
+
+// RemoveProductIngredient detaches an ingredient from a product recipe.
+func (ps *productService) RemoveProductIngredient(ctx context.Context, productID int, ingredientID int) (*models.Product, error) {
+	return ps.inTransaction(ctx, func(tx repository.Transaction) (int, error) {
+		if _, err := ps.productRepo.GetProductById(ctx, tx, productID); err != nil {
+			return 0, err
+		}
+		return productID, ps.productRepo.RemoveProductIngredient(ctx, tx, productID, ingredientID)
+	})
+}
+
+// ReplaceProductIngredients atomically swaps the whole recipe of a product.
+func (ps *productService) ReplaceProductIngredients(ctx context.Context, productID int, ingredients []models.ProductIngredient) (*models.Product, error) {
+	return ps.inTransaction(ctx, func(tx repository.Transaction) (int, error) {
+		if _, err := ps.productRepo.GetProductById(ctx, tx, productID); err != nil {
+			return 0, err
+		}
+		if err := ps.productRepo.ClearProductIngredients(ctx, tx, productID); err != nil {
+			return 0, err
+		}
+		return productID, ps.setIngredients(ctx, tx, productID, ingredients)
+	})
+}
+
+// ListMenuAvailability returns whether each product can currently be ordered.
+func (ps *productService) ListMenuAvailability(ctx context.Context) ([]models.MenuItemAvailability, error) {
+	products, err := ps.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	menu := make([]models.MenuItemAvailability, 0, len(products))
+	for _, product := range products {
+		menu = append(menu, models.MenuItemAvailability{
+			ProductID:         product.ID,
+			Name:              product.Name,
+			Available:         product.Available,
+			InStock:           product.InStock,
+			MarkedUnavailable: product.MarkedUnavailable,
+		})
+	}
+
+	return menu, nil
+}
+
+// SetProductMarkedUnavailable takes a product off the menu or puts it back.
+// A product put back is only available again while it is in stock.
+func (ps *productService) SetProductMarkedUnavailable(ctx context.Context, productID int, unavailable bool) (*models.Product, error) {
+	if err := ps.productRepo.SetMarkedUnavailable(ctx, productID, unavailable); err != nil {
+		return nil, err
+	}
+	return ps.GetProduct(ctx, productID)
+}
+
+// ListModifiers returns the modifiers customers may apply to a product.
+func (ps *productService) ListModifiers(ctx context.Context, productID int) ([]models.ProductModifier, error) {
+	if _, err := ps.productRepo.GetProductById(ctx, nil, productID); err != nil {
+		return nil, err
+	}
+	return ps.productRepo.ListModifiers(ctx, nil, productID)
+}
+
+// CreateModifier allows a new modifier for a product. The ingredient a modifier
+// removes or replaces must be part of the product recipe, and the unit of the
+// amount it adds must suit the ingredient added.
+func (ps *productService) CreateModifier(ctx context.Context, modifier *models.ProductModifier) (*models.ProductModifier, error) {
+	product, err := ps.productRepo.GetProductById(ctx, nil, modifier.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	if modifier.Action != models.ModifierActionAdd {
+		inRecipe := false
+		for _, productIngredient := range product.Ingredients {
+			if productIngredient.IngredientID == modifier.IngredientID {
+				inRecipe = true
+				break
+			}
+		}
+		if !inRecipe {
+			return nil, internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid modifier",
+				fmt.Sprintf("Ingredient with ID %d is not part of %s", modifier.IngredientID, product.Name),
+			)
+		}
+	}
+
+	if modifier.Action == models.ModifierActionRemove {
+		modifier.Amount, modifier.Unit = 0, ""
+	} else {
+		ingredient, err := ps.ingredientRepo.GetIngredientByID(ctx, nil, modifier.AmountIngredientID())
+		if err != nil {
+			return nil, err
+		}
+		if modifier.Unit == "" {
+			modifier.Unit = ingredient.Unit
+		}
+		modifier.IngredientUnit = ingredient.Unit
+		if _, err := modifier.BaseAmount(); err != nil {
+			return nil, internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Incompatible unit",
+				fmt.Sprintf("%s is measured in %s, %s", ingredient.Name, ingredient.Unit, err),
+			)
+		}
+	}
+
+	modifier.Name = strings.TrimSpace(modifier.Name)
+	if err := ps.productRepo.CreateModifier(ctx, modifier); err != nil {
+		return nil, err
+	}
+	return modifier, nil
+}
+
+func (ps *productService) DeleteModifier(ctx context.Context, productID int, modifierID int) error {
+	return ps.productRepo.DeleteModifier(ctx, productID, modifierID)
+}
+
+// ListVariants returns the sizes a product is sold in.
+func (ps *productService) ListVariants(ctx context.Context, productID int) ([]models.ProductVariant, error) {
+	if _, err := ps.productRepo.GetProductById(ctx, nil, productID); err != nil {
+		return nil, err
+	}
+	return ps.productRepo.ListVariants(ctx, productID)
+}
+
+// CreateVariant adds a size of a product. The ingredients a variant overrides
+// must be part of the product recipe, and their units must suit them.
+func (ps *productService) CreateVariant(ctx context.Context, variant *models.ProductVariant) (saved *models.ProductVariant, err error) {
+	tx, err := ps.productRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	product, err := ps.productRepo.GetProductById(ctx, tx, variant.ProductID)
+	if err != nil {
+		return nil, err
+	}
+
+	recipe := make(map[int]models.ProductIngredient, len(product.Ingredients))
+	for _, productIngredient := range product.Ingredients {
+		recipe[productIngredient.IngredientID] = productIngredient
+	}
+	for i := range variant.Overrides {
+		override := &variant.Overrides[i]
+		productIngredient, ok := recipe[override.IngredientID]
+		if !ok {
+			return nil, internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Invalid variant",
+				fmt.Sprintf("Ingredient with ID %d is not part of %s", override.IngredientID, product.Name),
+			)
+		}
+		if override.Unit == "" {
+			override.Unit = productIngredient.IngredientUnit
+		}
+		line := models.ProductIngredient{Amount: override.Amount, Unit: override.Unit, IngredientUnit: productIngredient.IngredientUnit}
+		if _, err := line.BaseAmount(); err != nil {
+			return nil, internalErrors.NewAppError(
+				internalErrors.ErrCodeValidation,
+				"Incompatible unit",
+				fmt.Sprintf("%s is measured in %s, %s", productIngredient.IngredientName, productIngredient.IngredientUnit, err),
+			)
+		}
+	}
+
+	variant.Name = strings.TrimSpace(variant.Name)
+	if variant.Multiplier == 0 {
+		variant.Multiplier = 1
+	}
+	if variant.Overrides == nil {
+		variant.Overrides = []models.VariantIngredient{}
+	}
+	if err = ps.productRepo.CreateVariant(ctx, tx, variant); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return variant, nil
+}
+
+func (ps *productService) DeleteVariant(ctx context.Context, productID int, variantID int) error {
+	return ps.productRepo.DeleteVariant(ctx, productID, variantID)
+}
+
+// GetProductCapacity returns how many units of a product, or of one of its
+// variants when variantID is given, the current stock can make.
+func (ps *productService) GetProductCapacity(ctx context.Context, productID int, variantID *int) (*models.ProductCapacity, error) {
+	product, err := ps.productRepo.GetProductById(ctx, nil, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	var variant *models.ProductVariant
+	if variantID != nil {
+		variant, err = ps.productRepo.GetVariant(ctx, nil, *variantID)
+		if err != nil {
+			return nil, err
+		}
+		if variant.ProductID != product.ID {
+			return nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found", fmt.Sprintf("Variant with ID %d not found for product %d", *variantID, productID))
+		}
+	}
+
+	recipes, stock, err := ps.recipeStock(ctx, nil, product)
+	if err != nil {
+		return nil, err
+	}
+
+	if variant != nil {
+		return variantCapacity(product, *variant, recipes, stock)
+	}
+	return productCapacity(product, recipes, stock)
+}
+
+// ListProductCapacities returns how many units of every product the current
+// stock can make, each product followed by its variants.
+func (ps *productService) ListProductCapacities(ctx context.Context) ([]models.ProductCapacity, error) {
+	products, err := ps.productRepo.ListProducts(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recipes, stock, err := ps.menuStock(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allVariants, err := ps.productRepo.ListAllVariants(ctx)
+	if err != nil {
+		return nil, err
+	}
+	variants := make(map[int][]models.ProductVariant)
+	for _, variant := range allVariants {
+		variants[variant.ProductID] = append(variants[variant.ProductID], variant)
+	}
+
+	capacities := make([]models.ProductCapacity, 0, len(products)+len(allVariants))
+	for i := range products {
+		capacity, err := productCapacity(&products[i], recipes, stock)
+		if err != nil {
+			return nil, err
+		}
+		capacities = append(capacities, *capacity)
+
+		for _, variant := range variants[products[i].ID] {
+			capacity, err := variantCapacity(&products[i], variant, recipes, stock)
+			if err != nil {
+				return nil, err
+			}
+			capacities = append(capacities, *capacity)
+		}
+	}
+
+	return capacities, nil
+}
+
+// recipeStock loads the prep recipes and the stock of the ingredients of
+// product, together with the ingredients its prep items are made from.
+// Archived ingredients are left out so they count as out of stock.
+func (ps *productService) recipeStock(ctx context.Context, tx repository.Transaction, product *models.Product) (map[int]models.PrepRecipe, map[int]float64, error) {
+	list, err := ps.prepRepo.ListPrepRecipes(ctx, tx)
+	if err != nil {
+		return nil, nil, err
+	}
+	recipes := indexPrepRecipes(list)
+
+	ingredientIDs := make([]int, 0, len(product.Ingredients))
+	for _, productIngredient := range product.Ingredients {
+		ingredientIDs = append(ingredientIDs, productIngredient.IngredientID)
+	}
+
+	// Prep items also draw on the stock of the ingredients they are made from
+	closure := prepClosure(recipes, ingredientIDs)
+	stock := make(map[int]float64, len(closure))
+	for _, ingredientID := range closure {
+		ingredient, err := ps.ingredientRepo.GetIngredientByID(ctx, tx, ingredientID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if ingredient.ArchivedAt == nil {
+			stock[ingredient.ID] = ingredient.CurrentStock
+		}
+	}
+
+	return recipes, stock, nil
+}
+
+// menuStock loads the prep recipes and the stock of every active ingredient.
+func (ps *productService) menuStock(ctx context.Context) (map[int]models.PrepRecipe, map[int]float64, error) {
+	ingredients, err := ps.ingredientRepo.ListIngredients(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stock := make(map[int]float64, len(ingredients))
+	for _, ingredient := range ingredients {
+		stock[ingredient.ID] = ingredient.CurrentStock
+	}
+
+	list, err := ps.prepRepo.ListPrepRecipes(ctx, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return indexPrepRecipes(list), stock, nil
+}
+
+// withStock sets whether the current stock can make product.
+func (ps *productService) withStock(ctx context.Context, tx repository.Transaction, product *models.Product) (*models.Product, error) {
+	recipes, stock, err := ps.recipeStock(ctx, tx, product)
+	if err != nil {
+		return nil, err
+	}
+	if err := setInStock(product, recipes, stock); err != nil {
+		return nil, err
+	}
+	return product, nil
+}
+
+// setInStock marks product in stock while its capacity is at least a single
+// unit, using the same recipe math as orders so the menu never offers what an
+// order would reject. A product without a recipe is always in stock.
+func setInStock(product *models.Product, recipes map[int]models.PrepRecipe, stock map[int]float64) error {
+	capacity, err := productCapacity(product, recipes, stock)
+	if err != nil {
+		return err
+	}
+	product.InStock = capacity.MaxQuantity == nil || *capacity.MaxQuantity > 0
+	product.Available = product.InStock && !product.MarkedUnavailable
+	return nil
+}
+
+// capacityEpsilon absorbs floating point error, so 0.3 of stock still makes
+// three units of a recipe using 0.1.
+const capacityEpsilon = 1e-9
+
+// productCapacity computes the capacity of product from the current stock of
+// its ingredients, yield loss included, prep items count what their recipes
+// can still make.
+// Ingredients missing from stock, such as archived ones, count as out of stock,
+// recipe lines with a zero amount are left out.
+func productCapacity(product *models.Product, recipes map[int]models.PrepRecipe, stock map[int]float64) (*models.ProductCapacity, error) {
+	capacity := &models.ProductCapacity{ProductID: product.ID, Name: product.Name}
+	for _, productIngredient := range product.Ingredients {
+		amount, err := baseAmount(productIngredient)
+		if err != nil {
+			return nil, err
+		}
+		amount = models.GrossAmount(amount, productIngredient.YieldPercent, productIngredient.IngredientYieldPercent)
+		if amount <= 0 {
+			// A line using none of its ingredient, such as a garnish on request, never limits the capacity
+			continue
+		}
+		available, err := availableAmount(productIngredient.IngredientID, recipes, stock, 0)
+		if err != nil {
+			return nil, err
+		}
+		units := int(math.Floor(available/amount + capacityEpsilon))
+		if units < 0 {
+			units = 0
+		}
+		if capacity.MaxQuantity == nil || units < *capacity.MaxQuantity {
+			ingredientID := productIngredient.IngredientID
+			capacity.MaxQuantity = &units
+			capacity.LimitingIngredientID = &ingredientID
+			capacity.LimitingIngredientName = productIngredient.IngredientName
+		}
+	}
+	return capacity, nil
+}
+
+// variantCapacity computes the capacity of a variant of product from its recipe.
+func variantCapacity(product *models.Product, variant models.ProductVariant, recipes map[int]models.PrepRecipe, stock map[int]float64) (*models.ProductCapacity, error) {
+	sized := *product
+	sized.Ingredients = variant.Recipe(product.Ingredients)
+
+	capacity, err := productCapacity(&sized, recipes, stock)
+	if err != nil {
+		return nil, err
+	}
+	variantID := variant.ID
+	capacity.VariantID = &variantID
+	capacity.VariantName = variant.Name
+	return capacity, nil
+}
+
+// baseAmount converts a recipe amount to the base unit of its ingredient.
+// Recipe units are validated when the recipe is stored, so a failure means
+// the stored data is inconsistent.
+func baseAmount(productIngredient models.ProductIngredient) (float64, error) {
+	amount, err := productIngredient.BaseAmount()
+	if err != nil {
+		slog.Error("failed to convert recipe amount", "productID", productIngredient.ProductID, "ingredientID", productIngredient.IngredientID, "error", err)
+		return 0, internalErrors.Wrap(internalErrors.ErrInternalServer, "invalid recipe unit")
+	}
+	return amount, nil
+}
+
+func (ps *productService) setIngredients(ctx context.Context, tx repository.Transaction, productID int, ingredients []models.ProductIngredient) error {
+	for _, ingredient := range ingredients {
+		ingredient.ProductID = productID
+		if err := ps.resolveUnit(ctx, tx, &ingredient); err != nil {
+			return err
+		}
+		if err := ps.productRepo.SetProductIngredient(ctx, tx, ingredient); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resolveUnit checks that a recipe line unit can be converted to the base unit
+// of its ingredient, a line without a unit uses the base unit.
+func (ps *productService) resolveUnit(ctx context.Context, tx repository.Transaction, productIngredient *models.ProductIngredient) error {
+	ingredient, err := ps.ingredientRepo.GetIngredientByID(ctx, tx, productIngredient.IngredientID)
+	if err != nil {
+		return err
+	}
+
+	if productIngredient.Unit == "" {
+		productIngredient.Unit = ingredient.Unit
+	}
+	productIngredient.IngredientUnit = ingredient.Unit
+
+	if _, err := productIngredient.BaseAmount(); err != nil {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Incompatible unit",
+			fmt.Sprintf("%s is measured in %s, %s", ingredient.Name, ingredient.Unit, err),
+		)
+	}
+
+	return nil
+}
+
+// inTransaction runs fn inside a transaction and returns the resulting
+// product, read back together with its stock within the same transaction
+// before committing.
+func (ps *productService) inTransaction(ctx context.Context, fn func(tx repository.Transaction) (int, error)) (product *models.Product, err error) {
+	tx, err := ps.productRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	productID, err := fn(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err = ps.productRepo.GetProductById(ctx, tx, productID)
+	if err != nil {
+		return nil, err
+	}
+
+	product, err = ps.withStock(ctx, tx, product)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return product, nil
+}
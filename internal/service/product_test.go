@@ -0,0 +1,600 @@
+package service
+
+import (
+	"context"
+	"errors"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestCreateProduct(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      *models.Product
+		buildStubs func(
+			productRepo *mockrepository.MockProductRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			prepRepo *mockrepository.MockPrepRecipeRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, product *models.Product, err error)
+	}{
+		{
+			name: "Success Create Product",
+			input: &models.Product{
+				Name: " Cheeseburger ",
+				Ingredients: []models.ProductIngredient{
+					{IngredientID: 1, Amount: 0.15, Unit: "kg"},
+					{IngredientID: 2, Amount: 60},
+				},
+			},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().CreateProduct(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, product *models.Product) error {
+						product.ID = 2
+						return nil
+					})
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 1).Return(&models.Ingredient{ID: 1, Unit: models.UnitGram}, nil)
+				productRepo.EXPECT().SetProductIngredient(gomock.Any(), tx, models.ProductIngredient{ProductID: 2, IngredientID: 1, Amount: 0.15, Unit: "kg", IngredientUnit: models.UnitGram}).Return(nil)
+				// A line without a unit takes the ingredient base unit
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 2).Return(&models.Ingredient{ID: 2, Unit: models.UnitGram}, nil)
+				productRepo.EXPECT().SetProductIngredient(gomock.Any(), tx, models.ProductIngredient{ProductID: 2, IngredientID: 2, Amount: 60, Unit: models.UnitGram, IngredientUnit: models.UnitGram}).Return(nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 2).
+					Return(&models.Product{ID: 2, Name: "Cheeseburger"}, nil)
+				prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), tx).Return(nil, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, product *models.Product, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if product.ID != 2 {
+					t.Errorf("expected product ID 2, got %d", product.ID)
+				}
+				if !product.InStock || !product.Available {
+					t.Errorf("expected a product without a recipe to be available, got %+v", product)
+				}
+			},
+		},
+		{
+			name: "Unknown Ingredient Rolls Back",
+			input: &models.Product{
+				Name:        "Cheeseburger",
+				Ingredients: []models.ProductIngredient{{IngredientID: 99, Amount: 10}},
+			},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().CreateProduct(gomock.Any(), tx, gomock.Any()).Return(nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 99).Return(nil, internalErrors.ErrNotFound)
+				productRepo.EXPECT().SetProductIngredient(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, product *models.Product, err error) {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+			},
+		},
+		{
+			name: "Incompatible Unit Rejected",
+			input: &models.Product{
+				Name:        "Cheeseburger",
+				Ingredients: []models.ProductIngredient{{IngredientID: 4, Amount: 100, Unit: models.UnitGram}},
+			},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().CreateProduct(gomock.Any(), tx, gomock.Any()).Return(nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 4).
+					Return(&models.Ingredient{ID: 4, Name: "Bun", Unit: models.UnitPiece}, nil)
+				productRepo.EXPECT().SetProductIngredient(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, product *models.Product, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Errorf("expected validation error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(productRepo, ingredientRepo, prepRepo, tx)
+
+			ps := NewProductService(productRepo, ingredientRepo, prepRepo)
+
+			product, err := ps.CreateProduct(context.Background(), tc.input)
+			tc.checkResult(t, product, err)
+		})
+	}
+}
+
+func TestReplaceProductIngredients(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      []models.ProductIngredient
+		buildStubs func(
+			productRepo *mockrepository.MockProductRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			prepRepo *mockrepository.MockPrepRecipeRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, product *models.Product, err error)
+	}{
+		{
+			name:  "Success Replace Recipe",
+			input: []models.ProductIngredient{{IngredientID: 1, Amount: 300}},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				gomock.InOrder(
+					productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(&models.Product{ID: 1}, nil),
+					productRepo.EXPECT().ClearProductIngredients(gomock.Any(), tx, 1).Return(nil),
+					ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 1).Return(&models.Ingredient{ID: 1, Unit: models.UnitGram}, nil),
+					productRepo.EXPECT().SetProductIngredient(gomock.Any(), tx, models.ProductIngredient{ProductID: 1, IngredientID: 1, Amount: 300, Unit: models.UnitGram, IngredientUnit: models.UnitGram}).Return(nil),
+					productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(&models.Product{
+						ID:          1,
+						Ingredients: []models.ProductIngredient{{ProductID: 1, IngredientID: 1, Amount: 300}},
+					}, nil),
+					// Stock is read within the transaction, so the new recipe decides availability
+					prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), tx).Return(nil, nil),
+					ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), tx, 1).Return(&models.Ingredient{ID: 1, CurrentStock: 250}, nil),
+				)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, product *models.Product, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(product.Ingredients) != 1 {
+					t.Errorf("expected replaced recipe, got %+v", product.Ingredients)
+				}
+				if product.InStock || product.Available {
+					t.Errorf("expected product out of stock for the new recipe, got %+v", product)
+				}
+			},
+		},
+		{
+			name:  "Product Not Found",
+			input: []models.ProductIngredient{{IngredientID: 1, Amount: 300}},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(nil, internalErrors.ErrNotFound)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, product *models.Product, err error) {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+			},
+		},
+		{
+			name:  "Error Begin Transaction",
+			input: []models.ProductIngredient{{IngredientID: 1, Amount: 300}},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				prepRepo *mockrepository.MockPrepRecipeRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				productRepo.EXPECT().BeginTransaction().Return(nil, errors.New("error"))
+			},
+			checkResult: func(t *testing.T, product *models.Product, err error) {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(productRepo, ingredientRepo, prepRepo, tx)
+
+			ps := NewProductService(productRepo, ingredientRepo, prepRepo)
+
+			product, err := ps.ReplaceProductIngredients(context.Background(), 1, tc.input)
+			tc.checkResult(t, product, err)
+		})
+	}
+}
+
+func TestListProductCapacities(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productRepo := mockrepository.NewMockProductRepository(ctrl)
+	ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+	prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+
+	productRepo.EXPECT().ListProducts(gomock.Any()).Return([]models.Product{
+		{ID: 1, Name: "Burger", Ingredients: []models.ProductIngredient{
+			{ProductID: 1, IngredientID: 1, IngredientName: "Beef", Amount: 0.15, Unit: "kg", IngredientUnit: models.UnitGram},
+			{ProductID: 1, IngredientID: 2, IngredientName: "Cheese", Amount: 0.1, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+		}},
+		{ID: 2, Name: "Water"},
+		{ID: 3, Name: "Truffle Fries", Ingredients: []models.ProductIngredient{
+			{ProductID: 3, IngredientID: 3, IngredientName: "Truffle", Amount: 5},
+		}},
+		{ID: 4, Name: "Dip", Ingredients: []models.ProductIngredient{
+			{ProductID: 4, IngredientID: 4, IngredientName: "Sauce", Amount: 50},
+		}},
+		// Truffle shavings are optional, the line uses none of it
+		{ID: 5, Name: "Cheese Plate", Ingredients: []models.ProductIngredient{
+			{ProductID: 5, IngredientID: 3, IngredientName: "Truffle", Amount: 0},
+			{ProductID: 5, IngredientID: 2, IngredientName: "Cheese", Amount: 0.1, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+		}},
+	}, nil)
+	// Truffle is archived, so it is not listed
+	ingredientRepo.EXPECT().ListIngredients(gomock.Any()).Return([]models.Ingredient{
+		{ID: 1, CurrentStock: 1000},
+		{ID: 2, CurrentStock: 0.3},
+		{ID: 4, CurrentStock: 20},
+	}, nil)
+	// Sauce is prepared 100 g at a time from 200 g of beef
+	prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), nil).Return([]models.PrepRecipe{
+		{IngredientID: 4, Yield: 100, Ingredients: []models.PrepIngredient{
+			{PrepID: 4, IngredientID: 1, Amount: 200},
+		}},
+	}, nil)
+	// A triple burger takes three times the beef but keeps a single slice of cheese
+	productRepo.EXPECT().ListAllVariants(gomock.Any()).Return([]models.ProductVariant{
+		{ID: 7, ProductID: 1, Name: "Triple", Multiplier: 3, Overrides: []models.VariantIngredient{
+			{VariantID: 7, IngredientID: 2, Amount: 0.1, Unit: models.UnitGram},
+		}},
+	}, nil)
+
+	ps := NewProductService(productRepo, ingredientRepo, prepRepo)
+
+	capacities, err := ps.ListProductCapacities(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(capacities) != 6 {
+		t.Fatalf("expected 6 capacities, got %d", len(capacities))
+	}
+
+	burger := capacities[0]
+	if burger.MaxQuantity == nil || *burger.MaxQuantity != 3 || *burger.LimitingIngredientID != 2 {
+		t.Errorf("expected 3 burgers limited by cheese, got %+v", burger)
+	}
+	triple := capacities[1]
+	if triple.VariantID == nil || *triple.VariantID != 7 || triple.MaxQuantity == nil || *triple.MaxQuantity != 2 || *triple.LimitingIngredientID != 1 {
+		t.Errorf("expected 2 triple burgers limited by beef, got %+v", triple)
+	}
+	if water := capacities[2]; water.MaxQuantity != nil || water.LimitingIngredientID != nil {
+		t.Errorf("expected unlimited water, got %+v", water)
+	}
+	if fries := capacities[3]; fries.MaxQuantity == nil || *fries.MaxQuantity != 0 {
+		t.Errorf("expected sold out fries, got %+v", fries)
+	}
+	// 20 g of prepared sauce and 500 g more made from the beef
+	if dip := capacities[4]; dip.MaxQuantity == nil || *dip.MaxQuantity != 10 {
+		t.Errorf("expected 10 dips, got %+v", dip)
+	}
+	if plate := capacities[5]; plate.MaxQuantity == nil || *plate.MaxQuantity != 3 || *plate.LimitingIngredientID != 2 {
+		t.Errorf("expected 3 cheese plates limited by cheese, got %+v", plate)
+	}
+}
+
+func TestGetProduct(t *testing.T) {
+	// Dip uses 50 g of sauce, prepared 100 g at a time from 200 g of beef
+	dip := func() *models.Product {
+		return &models.Product{ID: 4, Name: "Dip", Ingredients: []models.ProductIngredient{
+			{ProductID: 4, IngredientID: 4, IngredientName: "Sauce", Amount: 50},
+		}}
+	}
+	sauceRecipe := []models.PrepRecipe{
+		{IngredientID: 4, Yield: 100, Ingredients: []models.PrepIngredient{
+			{PrepID: 4, IngredientID: 1, Amount: 200},
+		}},
+	}
+
+	testCases := []struct {
+		name        string
+		sauceStock  float64
+		beefStock   float64
+		beefArchive bool
+		inStock     bool
+	}{
+		{name: "Raw Ingredient Covers Prep Item", sauceStock: 0, beefStock: 100, inStock: true},
+		{name: "Raw Ingredient Runs Out", sauceStock: 0, beefStock: 99, inStock: false},
+		{name: "Raw Ingredient Archived", sauceStock: 0, beefStock: 1000, beefArchive: true, inStock: false},
+		{name: "Prepared Stock Covers", sauceStock: 50, beefStock: 0, inStock: true},
+		{name: "Prepared And Raw Stock Together", sauceStock: 30, beefStock: 40, inStock: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+
+			beef := &models.Ingredient{ID: 1, CurrentStock: tc.beefStock}
+			if tc.beefArchive {
+				archivedAt := time.Now()
+				beef.ArchivedAt = &archivedAt
+			}
+
+			productRepo.EXPECT().GetProductById(gomock.Any(), nil, 4).Return(dip(), nil)
+			prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), nil).Return(sauceRecipe, nil)
+			ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 1).Return(beef, nil)
+			ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 4).Return(&models.Ingredient{ID: 4, CurrentStock: tc.sauceStock}, nil)
+
+			ps := NewProductService(productRepo, ingredientRepo, prepRepo)
+
+			product, err := ps.GetProduct(context.Background(), 4)
+			if err != nil {
+				t.Fatalf("expected no error, got %v", err)
+			}
+			if product.InStock != tc.inStock || product.Available != tc.inStock {
+				t.Errorf("expected in stock %v, got %+v", tc.inStock, product)
+			}
+		})
+	}
+}
+
+func TestListMenuAvailability(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	productRepo := mockrepository.NewMockProductRepository(ctrl)
+	ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+	prepRepo := mockrepository.NewMockPrepRecipeRepository(ctrl)
+
+	// Beef is trimmed to 75%, so a burger takes 200 g of it from stock
+	beefYield := 75.0
+	productRepo.EXPECT().ListProducts(gomock.Any()).Return([]models.Product{
+		{ID: 1, Name: "Burger", Ingredients: []models.ProductIngredient{
+			{ProductID: 1, IngredientID: 1, IngredientName: "Beef", Amount: 0.15, Unit: "kg", IngredientUnit: models.UnitGram, IngredientYieldPercent: &beefYield},
+		}},
+		{ID: 2, Name: "Lemonade", Ingredients: []models.ProductIngredient{
+			{ProductID: 2, IngredientID: 2, IngredientName: "Lemon Juice", Amount: 2, Unit: "fl_oz", IngredientUnit: models.UnitMilliliter},
+		}},
+		{ID: 3, Name: "Water", MarkedUnavailable: true},
+	}, nil)
+	ingredientRepo.EXPECT().ListIngredients(gomock.Any()).Return([]models.Ingredient{
+		{ID: 1, CurrentStock: 199.99},
+		{ID: 2, CurrentStock: 59.2},
+	}, nil)
+	prepRepo.EXPECT().ListPrepRecipes(gomock.Any(), nil).Return(nil, nil)
+
+	ps := NewProductService(productRepo, ingredientRepo, prepRepo)
+
+	menu, err := ps.ListMenuAvailability(context.Background())
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+	if len(menu) != 3 {
+		t.Fatalf("expected 3 menu items, got %d", len(menu))
+	}
+	if burger := menu[0]; burger.InStock || burger.Available {
+		t.Errorf("expected burger out of stock after yield loss, got %+v", burger)
+	}
+	// Two fluid ounces are a little over 59.1 ml
+	if lemonade := menu[1]; !lemonade.InStock || !lemonade.Available {
+		t.Errorf("expected lemonade available, got %+v", lemonade)
+	}
+	if water := menu[2]; !water.InStock || water.Available {
+		t.Errorf("expected water in stock but marked unavailable, got %+v", water)
+	}
+}
+
+func TestCreateModifier(t *testing.T) {
+	burger := &models.Product{ID: 1, Name: "Burger", Ingredients: []models.ProductIngredient{
+		{ProductID: 1, IngredientID: 3, Amount: 20},
+	}}
+
+	testCases := []struct {
+		name       string
+		input      *models.ProductModifier
+		buildStubs func(
+			productRepo *mockrepository.MockProductRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+		)
+		checkResult func(t *testing.T, modifier *models.ProductModifier, err error)
+	}{
+		{
+			name:  "Add Takes Ingredient Base Unit",
+			input: &models.ProductModifier{ProductID: 1, Name: " Extra cheese ", Action: models.ModifierActionAdd, IngredientID: 2, Amount: 30},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+			) {
+				productRepo.EXPECT().GetProductById(gomock.Any(), nil, 1).Return(burger, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 2).Return(&models.Ingredient{ID: 2, Unit: models.UnitGram}, nil)
+				productRepo.EXPECT().CreateModifier(gomock.Any(), &models.ProductModifier{
+					ProductID: 1, Name: "Extra cheese", Action: models.ModifierActionAdd, IngredientID: 2, Amount: 30, Unit: models.UnitGram, IngredientUnit: models.UnitGram,
+				}).Return(nil)
+			},
+			checkResult: func(t *testing.T, modifier *models.ProductModifier, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "Remove Of Ingredient Outside Recipe Rejected",
+			input: &models.ProductModifier{ProductID: 1, Name: "No pickles", Action: models.ModifierActionRemove, IngredientID: 5},
+			buildStubs: func(
+				productRepo *mockrepository.MockProductRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+			) {
+				productRepo.EXPECT().GetProductById(gomock.Any(), nil, 1).Return(burger, nil)
+				productRepo.EXPECT().CreateModifier(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, modifier *models.ProductModifier, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Errorf("expected validation error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+
+			tc.buildStubs(productRepo, ingredientRepo)
+
+			ps := NewProductService(productRepo, ingredientRepo, mockrepository.NewMockPrepRecipeRepository(ctrl))
+
+			modifier, err := ps.CreateModifier(context.Background(), tc.input)
+			tc.checkResult(t, modifier, err)
+		})
+	}
+}
+
+func TestCreateVariant(t *testing.T) {
+	burger := &models.Product{ID: 1, Name: "Burger", Ingredients: []models.ProductIngredient{
+		{ProductID: 1, IngredientID: 1, IngredientName: "Beef", Amount: 150, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+		{ProductID: 1, IngredientID: 2, IngredientName: "Cheese", Amount: 30, Unit: models.UnitGram, IngredientUnit: models.UnitGram},
+	}}
+
+	testCases := []struct {
+		name        string
+		input       *models.ProductVariant
+		buildStubs  func(productRepo *mockrepository.MockProductRepository, tx *mockrepository.MockTransaction)
+		checkResult func(t *testing.T, variant *models.ProductVariant, err error)
+	}{
+		{
+			name: "Override Takes Ingredient Base Unit",
+			input: &models.ProductVariant{ProductID: 1, Name: " Double ", Multiplier: 2, Overrides: []models.VariantIngredient{
+				{IngredientID: 2, Amount: 45},
+			}},
+			buildStubs: func(productRepo *mockrepository.MockProductRepository, tx *mockrepository.MockTransaction) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(burger, nil)
+				productRepo.EXPECT().CreateVariant(gomock.Any(), tx, &models.ProductVariant{
+					ProductID: 1, Name: "Double", Multiplier: 2, Overrides: []models.VariantIngredient{
+						{IngredientID: 2, Amount: 45, Unit: models.UnitGram},
+					},
+				}).Return(nil)
+				tx.EXPECT().Commit().Return(nil)
+			},
+			checkResult: func(t *testing.T, variant *models.ProductVariant, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Override Of Ingredient Outside Recipe Rejected",
+			input: &models.ProductVariant{ProductID: 1, Name: "Double", Multiplier: 2, Overrides: []models.VariantIngredient{
+				{IngredientID: 5, Amount: 10},
+			}},
+			buildStubs: func(productRepo *mockrepository.MockProductRepository, tx *mockrepository.MockTransaction) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(burger, nil)
+				productRepo.EXPECT().CreateVariant(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, variant *models.ProductVariant, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Errorf("expected validation error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Override In Incompatible Unit Rejected",
+			input: &models.ProductVariant{ProductID: 1, Name: "Double", Multiplier: 2, Overrides: []models.VariantIngredient{
+				{IngredientID: 1, Amount: 2, Unit: models.UnitPiece},
+			}},
+			buildStubs: func(productRepo *mockrepository.MockProductRepository, tx *mockrepository.MockTransaction) {
+				productRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				productRepo.EXPECT().GetProductById(gomock.Any(), tx, 1).Return(burger, nil)
+				productRepo.EXPECT().CreateVariant(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, variant *models.ProductVariant, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Errorf("expected validation error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			productRepo := mockrepository.NewMockProductRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(productRepo, tx)
+
+			ps := NewProductService(productRepo, mockrepository.NewMockIngredientRepository(ctrl), mockrepository.NewMockPrepRecipeRepository(ctrl))
+
+			variant, err := ps.CreateVariant(context.Background(), tc.input)
+			tc.checkResult(t, variant, err)
+		})
+	}
+}
@@ -0,0 +1,132 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	"strings"
+)
+
+type ReceiptService interface {
+	ReceiveStock(ctx context.Context, receipt *models.StockReceipt) (*models.StockReceipt, error)
+	GetReceipt(ctx context.Context, receiptID int) (*models.StockReceipt, error)
+}
+
+type receiptService struct {
+	receiptRepo    repository.ReceiptRepository
+	ingredientRepo repository.IngredientRepository
+	movementRepo   repository.StockMovementRepository
+	packRepo       repository.IngredientPackRepository
+}
+
+func NewReceiptService(receiptRepo repository.ReceiptRepository, ingredientRepo repository.IngredientRepository, movementRepo repository.StockMovementRepository, packRepo repository.IngredientPackRepository) ReceiptService {
+	return &receiptService{
+		receiptRepo:    receiptRepo,
+		ingredientRepo: ingredientRepo,
+		movementRepo:   movementRepo,
+		packRepo:       packRepo,
+	}
+}
+
+var _ ReceiptService = (*receiptService)(nil)
+
+// ReceiveStock records a goods receipt and adds the received quantities to the
+// ingredients stock in a single transaction, each item as a new lot.
+// Ingredients brought back above their alert threshold get their low stock
+// alert re-armed. Items received in packs are converted to the ingredient base
+// unit first.
+func (rs *receiptService) ReceiveStock(ctx context.Context, receipt *models.StockReceipt) (*models.StockReceipt, error) {
+	receipt.ReceivedBy = strings.TrimSpace(receipt.ReceivedBy)
+	for i := range receipt.Items {
+		receipt.Items[i].LotNumber = strings.TrimSpace(receipt.Items[i].LotNumber)
+	}
+
+	tx, err := rs.receiptRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	for i := range receipt.Items {
+		if err = rs.resolvePacks(ctx, tx, &receipt.Items[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = rs.receiptRepo.CreateReceipt(ctx, tx, receipt); err != nil {
+		return nil, err
+	}
+
+	for _, item := range receipt.Items {
+		if err = rs.ingredientRepo.ReceiveLot(ctx, tx, &models.IngredientLot{
+			IngredientID:     item.IngredientID,
+			ReceiptID:        &receipt.ID,
+			ReceiptItemID:    &item.ID,
+			LotNumber:        item.LotNumber,
+			ExpiresAt:        item.ExpiresAt,
+			ReceivedQuantity: item.Quantity,
+		}); err != nil {
+			return nil, err
+		}
+		if err = rs.movementRepo.RecordMovement(ctx, tx, &models.StockMovement{
+			IngredientID: item.IngredientID,
+			Delta:        item.Quantity,
+			Reason:       models.MovementReasonRestock,
+			ReferenceID:  &receipt.ID,
+		}); err != nil {
+			return nil, err
+		}
+
+		if item.IncreaseTotalStock {
+			if err = rs.ingredientRepo.IncrementTotalStock(ctx, tx, item.IngredientID, item.Quantity); err != nil {
+				return nil, err
+			}
+		}
+
+		if err = rs.ingredientRepo.ResetAlertIfReplenished(ctx, tx, item.IngredientID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return receipt, nil
+}
+
+// resolvePacks sets the quantity of an item received in packs from the pack size.
+func (rs *receiptService) resolvePacks(ctx context.Context, tx repository.Transaction, item *models.StockReceiptItem) error {
+	if item.PackID == nil {
+		return nil
+	}
+
+	pack, err := rs.packRepo.GetPackByID(ctx, tx, *item.PackID)
+	if err != nil {
+		return err
+	}
+	if pack.IngredientID != item.IngredientID {
+		return internalErrors.NewAppError(
+			internalErrors.ErrCodeValidation,
+			"Invalid pack",
+			fmt.Sprintf("Pack with ID %d is not a pack of ingredient %d", pack.ID, item.IngredientID),
+		)
+	}
+
+	item.Quantity = item.Packs * pack.Size
+	return nil
+}
+
+func (rs *receiptService) GetReceipt(ctx context.Context, receiptID int) (*models.StockReceipt, error) {
+	return rs.receiptRepo.GetReceiptByID(ctx, receiptID)
+}
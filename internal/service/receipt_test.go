@@ -0,0 +1,263 @@
+package service
+
+import (
+	"context"
+	"errors"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestReceiveStock(t *testing.T) {
+	packID := 7
+	expiresAt := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name       string
+		input      *models.StockReceipt
+		buildStubs func(
+			receiptRepo *mockrepository.MockReceiptRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			movementRepo *mockrepository.MockStockMovementRepository,
+			packRepo *mockrepository.MockIngredientPackRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, receipt *models.StockReceipt, err error)
+	}{
+		{
+			name: "Success Receive Stock",
+			input: &models.StockReceipt{
+				ReceivedBy: " Sam ",
+				Items: []models.StockReceiptItem{
+					{IngredientID: 1, Quantity: 5000, IncreaseTotalStock: true, LotNumber: " L-2291 ", ExpiresAt: &expiresAt},
+					{IngredientID: 2, Quantity: 1000},
+				},
+			},
+			buildStubs: func(
+				receiptRepo *mockrepository.MockReceiptRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				packRepo *mockrepository.MockIngredientPackRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				receiptRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				receiptRepo.EXPECT().CreateReceipt(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, receipt *models.StockReceipt) error {
+						receipt.ID = 3
+						return nil
+					})
+
+				ingredientRepo.EXPECT().ReceiveLot(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, lot *models.IngredientLot) error {
+						if lot.IngredientID != 1 || lot.ReceivedQuantity != 5000 || *lot.ReceiptID != 3 || lot.LotNumber != "L-2291" || !lot.ExpiresAt.Equal(expiresAt) {
+							t.Errorf("unexpected lot %+v", lot)
+						}
+						return nil
+					})
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, movement *models.StockMovement) error {
+						if movement.IngredientID != 1 || movement.Delta != 5000 || movement.Reason != models.MovementReasonRestock || *movement.ReferenceID != 3 {
+							t.Errorf("unexpected movement %+v", movement)
+						}
+						return nil
+					})
+				ingredientRepo.EXPECT().IncrementTotalStock(gomock.Any(), tx, 1, float64(5000)).Return(nil)
+				ingredientRepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), tx, 1).Return(nil)
+
+				ingredientRepo.EXPECT().ReceiveLot(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, lot *models.IngredientLot) error {
+						if lot.IngredientID != 2 || lot.ReceivedQuantity != 1000 || lot.ExpiresAt != nil {
+							t.Errorf("unexpected lot %+v", lot)
+						}
+						return nil
+					})
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil)
+				ingredientRepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), tx, 2).Return(nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, receipt *models.StockReceipt, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if receipt.ID != 3 || receipt.ReceivedBy != "Sam" {
+					t.Errorf("unexpected receipt %+v", receipt)
+				}
+			},
+		},
+		{
+			name: "One Ingredient Received In Two Lots",
+			input: &models.StockReceipt{
+				ReceivedBy: "Sam",
+				Items: []models.StockReceiptItem{
+					{IngredientID: 1, Quantity: 3000, LotNumber: "L-2291", ExpiresAt: &expiresAt},
+					{IngredientID: 1, Quantity: 2000, LotNumber: "L-2304"},
+				},
+			},
+			buildStubs: func(
+				receiptRepo *mockrepository.MockReceiptRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				packRepo *mockrepository.MockIngredientPackRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				receiptRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				receiptRepo.EXPECT().CreateReceipt(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, receipt *models.StockReceipt) error {
+						receipt.ID = 3
+						receipt.Items[0].ID = 11
+						receipt.Items[1].ID = 12
+						return nil
+					})
+
+				// Each lot points at the receipt item it came in with
+				gomock.InOrder(
+					ingredientRepo.EXPECT().ReceiveLot(gomock.Any(), tx, gomock.Any()).
+						DoAndReturn(func(ctx context.Context, tx any, lot *models.IngredientLot) error {
+							if *lot.ReceiptItemID != 11 || lot.LotNumber != "L-2291" || lot.ReceivedQuantity != 3000 {
+								t.Errorf("unexpected lot %+v", lot)
+							}
+							return nil
+						}),
+					ingredientRepo.EXPECT().ReceiveLot(gomock.Any(), tx, gomock.Any()).
+						DoAndReturn(func(ctx context.Context, tx any, lot *models.IngredientLot) error {
+							if *lot.ReceiptItemID != 12 || lot.LotNumber != "L-2304" || lot.ReceivedQuantity != 2000 {
+								t.Errorf("unexpected lot %+v", lot)
+							}
+							return nil
+						}),
+				)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil).Times(2)
+				ingredientRepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), tx, 1).Return(nil).Times(2)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, receipt *models.StockReceipt, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(receipt.Items) != 2 {
+					t.Errorf("expected both lots on the receipt, got %+v", receipt.Items)
+				}
+			},
+		},
+		{
+			name: "Items Received In Packs",
+			input: &models.StockReceipt{
+				ReceivedBy: "Sam",
+				Items:      []models.StockReceiptItem{{IngredientID: 1, PackID: &packID, Packs: 3}},
+			},
+			buildStubs: func(
+				receiptRepo *mockrepository.MockReceiptRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				packRepo *mockrepository.MockIngredientPackRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				receiptRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				packRepo.EXPECT().GetPackByID(gomock.Any(), tx, 7).
+					Return(&models.IngredientPack{ID: 7, IngredientID: 1, Label: "5 kg box", Size: 5000}, nil)
+				receiptRepo.EXPECT().CreateReceipt(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, receipt *models.StockReceipt) error {
+						if receipt.Items[0].Quantity != 15000 {
+							t.Errorf("expected the packs to be stored as 15000, got %v", receipt.Items[0].Quantity)
+						}
+						receipt.ID = 4
+						return nil
+					})
+
+				ingredientRepo.EXPECT().ReceiveLot(gomock.Any(), tx, gomock.Any()).Return(nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).Return(nil)
+				ingredientRepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), tx, 1).Return(nil)
+
+				tx.EXPECT().Commit().Return(nil)
+			},
+			checkResult: func(t *testing.T, receipt *models.StockReceipt, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Pack Of Another Ingredient Rejected",
+			input: &models.StockReceipt{
+				ReceivedBy: "Sam",
+				Items:      []models.StockReceiptItem{{IngredientID: 2, PackID: &packID, Packs: 1}},
+			},
+			buildStubs: func(
+				receiptRepo *mockrepository.MockReceiptRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				packRepo *mockrepository.MockIngredientPackRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				receiptRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				packRepo.EXPECT().GetPackByID(gomock.Any(), tx, 7).
+					Return(&models.IngredientPack{ID: 7, IngredientID: 1, Label: "5 kg box", Size: 5000}, nil)
+				receiptRepo.EXPECT().CreateReceipt(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, receipt *models.StockReceipt, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeValidation {
+					t.Errorf("expected validation error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Unknown Ingredient Rolls Back",
+			input: &models.StockReceipt{
+				ReceivedBy: "Sam",
+				Items:      []models.StockReceiptItem{{IngredientID: 99, Quantity: 10}},
+			},
+			buildStubs: func(
+				receiptRepo *mockrepository.MockReceiptRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				packRepo *mockrepository.MockIngredientPackRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				receiptRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				receiptRepo.EXPECT().CreateReceipt(gomock.Any(), tx, gomock.Any()).Return(internalErrors.ErrNotFound)
+
+				ingredientRepo.EXPECT().ReceiveLot(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, receipt *models.StockReceipt, err error) {
+				if err == nil {
+					t.Errorf("expected error, got nil")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			receiptRepo := mockrepository.NewMockReceiptRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			packRepo := mockrepository.NewMockIngredientPackRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(receiptRepo, ingredientRepo, movementRepo, packRepo, tx)
+
+			rs := NewReceiptService(receiptRepo, ingredientRepo, movementRepo, packRepo)
+
+			receipt, err := rs.ReceiveStock(context.Background(), tc.input)
+			tc.checkResult(t, receipt, err)
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+)
+
+type ReportService interface {
+	GetUsageVarianceReport(ctx context.Context, filter models.UsageReportFilter) (*models.UsageVarianceReport, error)
+}
+
+type reportService struct {
+	reportRepo repository.ReportRepository
+}
+
+func NewReportService(reportRepo repository.ReportRepository) ReportService {
+	return &reportService{reportRepo: reportRepo}
+}
+
+var _ ReportService = (*reportService)(nil)
+
+// GetUsageVarianceReport compares the theoretical usage of every ingredient
+// in a period with its actual usage. Stock used beyond the recipes points at
+// over-portioning or theft, the waste recorded explains part of it.
+func (rs *reportService) GetUsageVarianceReport(ctx context.Context, filter models.UsageReportFilter) (*models.UsageVarianceReport, error) {
+	usages, err := rs.reportRepo.SumUsage(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range usages {
+		usage := &usages[i]
+		usage.Variance = usage.Actual - usage.Theoretical
+		if usage.Theoretical > 0 {
+			percent := usage.Variance * 100 / usage.Theoretical
+			usage.VariancePercent = &percent
+		}
+	}
+
+	return &models.UsageVarianceReport{From: filter.From, To: filter.To, Ingredients: usages}, nil
+}
@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"stockk/internal/models"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestGetUsageVarianceReport(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	reportRepo := mockrepository.NewMockReportRepository(ctrl)
+
+	// Beef went over the recipes, the salt was only ever wasted
+	reportRepo.EXPECT().SumUsage(gomock.Any(), gomock.Any()).Return([]models.UsageVariance{
+		{IngredientID: 1, IngredientName: "Beef", Theoretical: 4000, Actual: 4500, Waste: 200},
+		{IngredientID: 5, IngredientName: "Salt", Actual: 50, Waste: 50},
+	}, nil)
+
+	rs := NewReportService(reportRepo)
+
+	report, err := rs.GetUsageVarianceReport(context.Background(), models.UsageReportFilter{})
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if beef := report.Ingredients[0]; beef.Variance != 500 || beef.VariancePercent == nil || *beef.VariancePercent != 12.5 {
+		t.Errorf("expected beef to be 500 g or 12.5%% over, got %+v", beef)
+	}
+	if salt := report.Ingredients[1]; salt.Variance != 50 || salt.VariancePercent != nil {
+		t.Errorf("expected salt to have no variance percentage, got %+v", salt)
+	}
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"math"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	"strings"
+)
+
+type StockCountService interface {
+	OpenSession(ctx context.Context, session *models.StockCountSession) (*models.StockCountSession, error)
+	GetSession(ctx context.Context, sessionID int) (*models.StockCountSession, error)
+	SubmitCounts(ctx context.Context, sessionID int, counts []models.StockCount) (*models.StockCountSession, error)
+	CloseSession(ctx context.Context, sessionID int) (*models.StockCountReport, error)
+	GetVarianceReport(ctx context.Context, sessionID int) (*models.StockCountReport, error)
+}
+
+type stockCountService struct {
+	countRepo      repository.StockCountRepository
+	ingredientRepo repository.IngredientRepository
+	movementRepo   repository.StockMovementRepository
+	outboxRepo     repository.OutboxRepository
+}
+
+func NewStockCountService(countRepo repository.StockCountRepository, ingredientRepo repository.IngredientRepository, movementRepo repository.StockMovementRepository, outboxRepo repository.OutboxRepository) StockCountService {
+	return &stockCountService{
+		countRepo:      countRepo,
+		ingredientRepo: ingredientRepo,
+		movementRepo:   movementRepo,
+		outboxRepo:     outboxRepo,
+	}
+}
+
+var _ StockCountService = (*stockCountService)(nil)
+
+func (cs *stockCountService) OpenSession(ctx context.Context, session *models.StockCountSession) (*models.StockCountSession, error) {
+	session.OpenedBy = strings.TrimSpace(session.OpenedBy)
+	session.Notes = strings.TrimSpace(session.Notes)
+
+	if err := cs.countRepo.CreateSession(ctx, session); err != nil {
+		return nil, err
+	}
+
+	session.Counts = []models.StockCount{}
+	return session, nil
+}
+
+func (cs *stockCountService) GetSession(ctx context.Context, sessionID int) (*models.StockCountSession, error) {
+	return cs.countRepo.GetSession(ctx, sessionID)
+}
+
+// SubmitCounts stores the counts of a member of staff in an open session,
+// replacing what they counted of the same ingredients before.
+func (cs *stockCountService) SubmitCounts(ctx context.Context, sessionID int, counts []models.StockCount) (*models.StockCountSession, error) {
+	tx, err := cs.countRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	if err = cs.countRepo.LockOpenSession(ctx, tx, sessionID); err != nil {
+		return nil, err
+	}
+
+	for _, count := range counts {
+		count.SessionID = sessionID
+		count.CountedBy = strings.TrimSpace(count.CountedBy)
+		if err = cs.countRepo.SaveCount(ctx, tx, count); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return cs.countRepo.GetSession(ctx, sessionID)
+}
+
+// CloseSession closes a count session and corrects the current stock of every
+// ingredient counted by its variance, the quantity counted less the stock the
+// system had when it was counted, recording it in the stock ledger against
+// the session. Stock that moved between the count and the close, such as
+// orders placed meanwhile, stays deducted. Ingredients nobody counted keep
+// their stock.
+func (cs *stockCountService) CloseSession(ctx context.Context, sessionID int) (*models.StockCountReport, error) {
+	tx, err := cs.countRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	// Closing first waits for counts being submitted and keeps new ones out
+	if err = cs.countRepo.CloseSession(ctx, tx, sessionID); err != nil {
+		return nil, err
+	}
+
+	variances, err := cs.countRepo.SumCounts(ctx, tx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Lock the stock the variances are applied to, the variances themselves
+	// do not change with the movements recorded after the count
+	ingredientIDs := make([]int, len(variances))
+	for i, variance := range variances {
+		ingredientIDs[i] = variance.IngredientID
+	}
+	stock, err := cs.ingredientRepo.LockStock(ctx, tx, ingredientIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, variance := range variances {
+		if err = cs.postVariance(ctx, tx, sessionID, variance, stock[variance.IngredientID]); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = queueLowStockAlert(ctx, tx, cs.ingredientRepo, cs.outboxRepo, ingredientIDs); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return cs.GetVarianceReport(ctx, sessionID)
+}
+
+// postVariance corrects the locked current stock of a counted ingredient by
+// its variance and keeps the outcome of the count for the variance report.
+// The stock never goes below zero, the ledger records the change applied.
+func (cs *stockCountService) postVariance(ctx context.Context, tx repository.Transaction, sessionID int, variance models.StockCountVariance, currentStock float64) error {
+	if err := cs.countRepo.SaveCountResult(ctx, tx, sessionID, variance); err != nil {
+		return err
+	}
+
+	if variance.Variance == 0 {
+		return nil
+	}
+
+	newStock := math.Max(currentStock+variance.Variance, 0)
+	previousStock, err := cs.ingredientRepo.UpdateStock(ctx, tx, variance.IngredientID, newStock)
+	if err != nil {
+		return err
+	}
+
+	delta := newStock - previousStock
+	if delta == 0 {
+		return nil
+	}
+	if err := cs.movementRepo.RecordMovement(ctx, tx, &models.StockMovement{
+		IngredientID: variance.IngredientID,
+		Delta:        delta,
+		Reason:       models.MovementReasonCount,
+		ReferenceID:  &sessionID,
+	}); err != nil {
+		return err
+	}
+
+	if delta > 0 {
+		return cs.ingredientRepo.ResetAlertIfReplenished(ctx, tx, variance.IngredientID)
+	}
+	return nil
+}
+
+// GetVarianceReport compares the counts of a session with the system stock
+// when each ingredient was last counted, worked out from the current stock
+// while the session is open and as stored at closing after.
+func (cs *stockCountService) GetVarianceReport(ctx context.Context, sessionID int) (*models.StockCountReport, error) {
+	session, err := cs.countRepo.GetSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	var variances []models.StockCountVariance
+	if session.Status == models.CountStatusOpen {
+		variances, err = cs.countRepo.SumCounts(ctx, nil, sessionID)
+	} else {
+		variances, err = cs.countRepo.ListCountResults(ctx, sessionID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.StockCountReport{
+		SessionID:   session.ID,
+		Status:      session.Status,
+		ClosedAt:    session.ClosedAt,
+		Ingredients: variances,
+	}, nil
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"context"
+	"errors"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestCloseStockCountSession(t *testing.T) {
+	testCases := []struct {
+		name       string
+		buildStubs func(
+			countRepo *mockrepository.MockStockCountRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			movementRepo *mockrepository.MockStockMovementRepository,
+			outboxRepo *mockrepository.MockOutboxRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, report *models.StockCountReport, err error)
+	}{
+		{
+			name: "Variances Posted As Count Movements",
+			buildStubs: func(
+				countRepo *mockrepository.MockStockCountRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				countRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				countRepo.EXPECT().CloseSession(gomock.Any(), tx, 4).Return(nil)
+				// Variances are measured against the stock when each ingredient was counted
+				countRepo.EXPECT().SumCounts(gomock.Any(), tx, 4).Return([]models.StockCountVariance{
+					{IngredientID: 1, IngredientName: "Beef", Counted: 4500, SystemStock: 4800, Variance: -300},
+					{IngredientID: 2, IngredientName: "Cheese", Counted: 2000, SystemStock: 2000, Variance: 0},
+					{IngredientID: 3, IngredientName: "Onion", Counted: 900, SystemStock: 800, Variance: 100},
+					{IngredientID: 5, IngredientName: "Milk", Counted: 0, SystemStock: 50, Variance: -50},
+				}, nil)
+
+				// Orders took 100 g of beef and 20 ml of milk after they were counted
+				ingredientRepo.EXPECT().LockStock(gomock.Any(), tx, []int{1, 2, 3, 5}).
+					Return(map[int]float64{1: 4700, 2: 2000, 3: 800, 5: 30}, nil)
+
+				countRepo.EXPECT().SaveCountResult(gomock.Any(), tx, 4, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, sessionID int, variance models.StockCountVariance) error {
+						if variance.IngredientID == 1 && (variance.SystemStock != 4800 || variance.Variance != -300) {
+							t.Errorf("unexpected beef variance %+v", variance)
+						}
+						return nil
+					}).Times(4)
+
+				// The orders since the count stay deducted, cheese matches the
+				// system and is left alone, milk cannot go below zero
+				ingredientRepo.EXPECT().UpdateStock(gomock.Any(), tx, 1, float64(4400)).Return(float64(4700), nil)
+				ingredientRepo.EXPECT().UpdateStock(gomock.Any(), tx, 3, float64(900)).Return(float64(800), nil)
+				ingredientRepo.EXPECT().UpdateStock(gomock.Any(), tx, 5, float64(0)).Return(float64(30), nil)
+				expected := map[int]float64{1: -300, 3: 100, 5: -30}
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, movement *models.StockMovement) error {
+						if movement.Reason != models.MovementReasonCount || *movement.ReferenceID != 4 {
+							t.Errorf("unexpected movement %+v", movement)
+						}
+						if movement.Delta != expected[movement.IngredientID] {
+							t.Errorf("unexpected movement delta %+v", movement)
+						}
+						return nil
+					}).Times(3)
+				ingredientRepo.EXPECT().ResetAlertIfReplenished(gomock.Any(), tx, 3).Return(nil)
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{1, 2, 3, 5}).Return(nil, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+
+				// The report is read back from the stored results
+				countRepo.EXPECT().GetSession(gomock.Any(), 4).
+					Return(&models.StockCountSession{ID: 4, Status: models.CountStatusClosed}, nil)
+				countRepo.EXPECT().ListCountResults(gomock.Any(), 4).
+					Return([]models.StockCountVariance{{IngredientID: 1, SystemStock: 4800, Counted: 4500, Variance: -300}}, nil)
+			},
+			checkResult: func(t *testing.T, report *models.StockCountReport, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if report.SessionID != 4 || report.Status != models.CountStatusClosed || len(report.Ingredients) != 1 {
+					t.Errorf("unexpected report %+v", report)
+				}
+			},
+		},
+		{
+			name: "Already Closed",
+			buildStubs: func(
+				countRepo *mockrepository.MockStockCountRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				countRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				countRepo.EXPECT().CloseSession(gomock.Any(), tx, 4).
+					Return(internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Stock count session closed"))
+				countRepo.EXPECT().SumCounts(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+				ingredientRepo.EXPECT().UpdateStock(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, report *models.StockCountReport, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeConflict {
+					t.Errorf("expected conflict error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			countRepo := mockrepository.NewMockStockCountRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(countRepo, ingredientRepo, movementRepo, outboxRepo, tx)
+
+			cs := NewStockCountService(countRepo, ingredientRepo, movementRepo, outboxRepo)
+
+			report, err := cs.CloseSession(context.Background(), 4)
+			tc.checkResult(t, report, err)
+		})
+	}
+}
+
+func TestSubmitStockCounts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	countRepo := mockrepository.NewMockStockCountRepository(ctrl)
+	tx := mockrepository.NewMockTransaction(ctrl)
+
+	// A closed session takes no more counts
+	countRepo.EXPECT().BeginTransaction().Return(tx, nil)
+	countRepo.EXPECT().LockOpenSession(gomock.Any(), tx, 4).
+		Return(internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Stock count session closed"))
+	countRepo.EXPECT().SaveCount(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+	tx.EXPECT().Rollback().Return(nil)
+
+	cs := NewStockCountService(countRepo, nil, nil, nil)
+
+	_, err := cs.SubmitCounts(context.Background(), 4, []models.StockCount{{IngredientID: 1, CountedBy: "Sam", Quantity: 4500}})
+
+	var appErr *internalErrors.AppError
+	if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeConflict {
+		t.Errorf("expected conflict error, got %v", err)
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	"strings"
+)
+
+type SupplierService interface {
+	CreateSupplier(ctx context.Context, supplier *models.Supplier) (*models.Supplier, error)
+	GetSupplier(ctx context.Context, supplierID int) (*models.Supplier, error)
+	ListSuppliers(ctx context.Context) ([]models.Supplier, error)
+	UpdateSupplier(ctx context.Context, supplier *models.Supplier) (*models.Supplier, error)
+	DeleteSupplier(ctx context.Context, supplierID int) error
+	ListSupplierIngredients(ctx context.Context, supplierID int) ([]models.SupplierIngredient, error)
+	SetSupplierIngredient(ctx context.Context, item models.SupplierIngredient) ([]models.SupplierIngredient, error)
+	RemoveSupplierIngredient(ctx context.Context, supplierID int, ingredientID int) error
+	ListIngredientSuppliers(ctx context.Context, ingredientID int) ([]models.SupplierIngredient, error)
+}
+
+type supplierService struct {
+	supplierRepo   repository.SupplierRepository
+	ingredientRepo repository.IngredientRepository
+}
+
+func NewSupplierService(supplierRepo repository.SupplierRepository, ingredientRepo repository.IngredientRepository) SupplierService {
+	return &supplierService{
+		supplierRepo:   supplierRepo,
+		ingredientRepo: ingredientRepo,
+	}
+}
+
+var _ SupplierService = (*supplierService)(nil)
+
+func (ss *supplierService) CreateSupplier(ctx context.Context, supplier *models.Supplier) (*models.Supplier, error) {
+	trimSupplier(supplier)
+	if err := ss.supplierRepo.CreateSupplier(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+func (ss *supplierService) GetSupplier(ctx context.Context, supplierID int) (*models.Supplier, error) {
+	return ss.supplierRepo.GetSupplierByID(ctx, supplierID)
+}
+
+func (ss *supplierService) ListSuppliers(ctx context.Context) ([]models.Supplier, error) {
+	return ss.supplierRepo.ListSuppliers(ctx)
+}
+
+// UpdateSupplier replaces the details of a supplier, its price list is kept.
+func (ss *supplierService) UpdateSupplier(ctx context.Context, supplier *models.Supplier) (*models.Supplier, error) {
+	trimSupplier(supplier)
+	if err := ss.supplierRepo.UpdateSupplier(ctx, supplier); err != nil {
+		return nil, err
+	}
+	return supplier, nil
+}
+
+// DeleteSupplier removes a supplier together with its price list.
+func (ss *supplierService) DeleteSupplier(ctx context.Context, supplierID int) error {
+	return ss.supplierRepo.DeleteSupplier(ctx, supplierID)
+}
+
+// ListSupplierIngredients returns the price list of a supplier.
+func (ss *supplierService) ListSupplierIngredients(ctx context.Context, supplierID int) ([]models.SupplierIngredient, error) {
+	if _, err := ss.supplierRepo.GetSupplierByID(ctx, supplierID); err != nil {
+		return nil, err
+	}
+	return ss.supplierRepo.ListSupplierIngredients(ctx, nil, supplierID)
+}
+
+// SetSupplierIngredient adds an active ingredient to the price list of a
+// supplier or replaces its offer, returning the updated price list. Making
+// the supplier preferred takes the preference away from the other suppliers
+// of the ingredient.
+func (ss *supplierService) SetSupplierIngredient(ctx context.Context, item models.SupplierIngredient) (items []models.SupplierIngredient, err error) {
+	// Make sure the supplier exists so a foreign key failure points at the ingredient
+	if _, err := ss.supplierRepo.GetSupplierByID(ctx, item.SupplierID); err != nil {
+		return nil, err
+	}
+
+	ingredient, err := ss.ingredientRepo.GetIngredientByID(ctx, nil, item.IngredientID)
+	if err != nil {
+		return nil, err
+	}
+	if ingredient.ArchivedAt != nil {
+		return nil, internalErrors.NewAppError(
+			internalErrors.ErrCodeConflict,
+			"Ingredient archived",
+			fmt.Sprintf("Ingredient with ID %d is archived", item.IngredientID),
+		)
+	}
+
+	tx, err := ss.supplierRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	if err = ss.supplierRepo.SetSupplierIngredient(ctx, tx, item); err != nil {
+		return nil, err
+	}
+
+	items, err = ss.supplierRepo.ListSupplierIngredients(ctx, tx, item.SupplierID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
+
+func (ss *supplierService) RemoveSupplierIngredient(ctx context.Context, supplierID int, ingredientID int) error {
+	return ss.supplierRepo.RemoveSupplierIngredient(ctx, supplierID, ingredientID)
+}
+
+// ListIngredientSuppliers returns the offers for an ingredient, the preferred
+// supplier first and then the cheapest.
+func (ss *supplierService) ListIngredientSuppliers(ctx context.Context, ingredientID int) ([]models.SupplierIngredient, error) {
+	if _, err := ss.ingredientRepo.GetIngredientByID(ctx, nil, ingredientID); err != nil {
+		return nil, err
+	}
+	return ss.supplierRepo.ListIngredientSuppliers(ctx, ingredientID)
+}
+
+func trimSupplier(supplier *models.Supplier) {
+	supplier.Name = strings.TrimSpace(supplier.Name)
+	supplier.ContactEmail = strings.TrimSpace(supplier.ContactEmail)
+}
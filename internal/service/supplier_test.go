@@ -0,0 +1,144 @@
+package service
+
+import (
+	"context"
+	"errors"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+	"time"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestSetSupplierIngredient(t *testing.T) {
+	archivedAt := time.Now()
+
+	testCases := []struct {
+		name       string
+		input      models.SupplierIngredient
+		buildStubs func(
+			supplierRepo *mockrepository.MockSupplierRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, items []models.SupplierIngredient, err error)
+	}{
+		{
+			name:  "Success Set Preferred Offer",
+			input: models.SupplierIngredient{SupplierID: 5, IngredientID: 1, PackSize: 5000, UnitPrice: 0.012, Preferred: true},
+			buildStubs: func(
+				supplierRepo *mockrepository.MockSupplierRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				supplierRepo.EXPECT().GetSupplierByID(gomock.Any(), 5).Return(&models.Supplier{ID: 5, Name: "Green Farms"}, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 1).Return(&models.Ingredient{ID: 1, Name: "Beef"}, nil)
+
+				supplierRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				supplierRepo.EXPECT().SetSupplierIngredient(gomock.Any(), tx, models.SupplierIngredient{
+					SupplierID: 5, IngredientID: 1, PackSize: 5000, UnitPrice: 0.012, Preferred: true,
+				}).Return(nil)
+				supplierRepo.EXPECT().ListSupplierIngredients(gomock.Any(), tx, 5).
+					Return([]models.SupplierIngredient{{SupplierID: 5, IngredientID: 1, IngredientName: "Beef", Preferred: true}}, nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, items []models.SupplierIngredient, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if len(items) != 1 || !items[0].Preferred {
+					t.Errorf("unexpected price list %+v", items)
+				}
+			},
+		},
+		{
+			name:  "Unknown Supplier",
+			input: models.SupplierIngredient{SupplierID: 99, IngredientID: 1, PackSize: 5000, UnitPrice: 0.012},
+			buildStubs: func(
+				supplierRepo *mockrepository.MockSupplierRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				supplierRepo.EXPECT().GetSupplierByID(gomock.Any(), 99).
+					Return(nil, internalErrors.NewAppError(internalErrors.ErrCodeNotFound, "Resource not found"))
+
+				supplierRepo.EXPECT().BeginTransaction().Times(0)
+			},
+			checkResult: func(t *testing.T, items []models.SupplierIngredient, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeNotFound {
+					t.Errorf("expected not found error, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "Archived Ingredient Rejected",
+			input: models.SupplierIngredient{SupplierID: 5, IngredientID: 3, PackSize: 1000, UnitPrice: 0.004},
+			buildStubs: func(
+				supplierRepo *mockrepository.MockSupplierRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				supplierRepo.EXPECT().GetSupplierByID(gomock.Any(), 5).Return(&models.Supplier{ID: 5, Name: "Green Farms"}, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 3).
+					Return(&models.Ingredient{ID: 3, Name: "Onion", ArchivedAt: &archivedAt}, nil)
+
+				supplierRepo.EXPECT().BeginTransaction().Times(0)
+			},
+			checkResult: func(t *testing.T, items []models.SupplierIngredient, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeConflict {
+					t.Errorf("expected conflict error, got %v", err)
+				}
+			},
+		},
+		{
+			name:  "Failed Upsert Rolls Back",
+			input: models.SupplierIngredient{SupplierID: 5, IngredientID: 1, PackSize: 5000, UnitPrice: 0.012, Preferred: true},
+			buildStubs: func(
+				supplierRepo *mockrepository.MockSupplierRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				supplierRepo.EXPECT().GetSupplierByID(gomock.Any(), 5).Return(&models.Supplier{ID: 5, Name: "Green Farms"}, nil)
+				ingredientRepo.EXPECT().GetIngredientByID(gomock.Any(), nil, 1).Return(&models.Ingredient{ID: 1, Name: "Beef"}, nil)
+
+				supplierRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				supplierRepo.EXPECT().SetSupplierIngredient(gomock.Any(), tx, gomock.Any()).
+					Return(internalErrors.NewAppError(internalErrors.ErrCodeConflict, "Preferred supplier changed"))
+				supplierRepo.EXPECT().ListSupplierIngredients(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, items []models.SupplierIngredient, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeConflict {
+					t.Errorf("expected conflict error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			supplierRepo := mockrepository.NewMockSupplierRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(supplierRepo, ingredientRepo, tx)
+
+			ss := NewSupplierService(supplierRepo, ingredientRepo)
+
+			items, err := ss.SetSupplierIngredient(context.Background(), tc.input)
+			tc.checkResult(t, items, err)
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	"strings"
+)
+
+type WasteService interface {
+	RecordWaste(ctx context.Context, entry *models.WasteEntry) (*models.WasteEntry, error)
+	GetWasteEntry(ctx context.Context, entryID int) (*models.WasteEntry, error)
+	GetWasteReport(ctx context.Context, filter models.WasteReportFilter) (*models.WasteReport, error)
+}
+
+type wasteService struct {
+	wasteRepo      repository.WasteRepository
+	ingredientRepo repository.IngredientRepository
+	movementRepo   repository.StockMovementRepository
+	outboxRepo     repository.OutboxRepository
+}
+
+func NewWasteService(wasteRepo repository.WasteRepository, ingredientRepo repository.IngredientRepository, movementRepo repository.StockMovementRepository, outboxRepo repository.OutboxRepository) WasteService {
+	return &wasteService{
+		wasteRepo:      wasteRepo,
+		ingredientRepo: ingredientRepo,
+		movementRepo:   movementRepo,
+		outboxRepo:     outboxRepo,
+	}
+}
+
+var _ WasteService = (*wasteService)(nil)
+
+// RecordWaste logs wasted stock and deducts it from the ingredient in a single
+// transaction, recording the deduction in the stock ledger against the entry.
+// Waste bringing the ingredient below its threshold raises a low stock alert
+// like an order would.
+func (ws *wasteService) RecordWaste(ctx context.Context, entry *models.WasteEntry) (*models.WasteEntry, error) {
+	entry.Notes = strings.TrimSpace(entry.Notes)
+
+	tx, err := ws.wasteRepo.BeginTransaction()
+	if err != nil {
+		return nil, internalErrors.Wrap(internalErrors.ErrInternalServer, "failed to begin transaction")
+	}
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	if err = ws.wasteRepo.CreateWasteEntry(ctx, tx, entry); err != nil {
+		return nil, err
+	}
+
+	// Nothing can be wasted that is not in stock
+	if err = ws.ingredientRepo.DecrementStock(ctx, tx, entry.IngredientID, entry.Quantity); err != nil {
+		return nil, err
+	}
+
+	if err = ws.movementRepo.RecordMovement(ctx, tx, &models.StockMovement{
+		IngredientID: entry.IngredientID,
+		Delta:        -entry.Quantity,
+		Reason:       models.MovementReasonWaste,
+		ReferenceID:  &entry.ID,
+	}); err != nil {
+		return nil, err
+	}
+
+	if err = queueLowStockAlert(ctx, tx, ws.ingredientRepo, ws.outboxRepo, []int{entry.IngredientID}); err != nil {
+		return nil, err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func (ws *wasteService) GetWasteEntry(ctx context.Context, entryID int) (*models.WasteEntry, error) {
+	return ws.wasteRepo.GetWasteEntryByID(ctx, entryID)
+}
+
+// GetWasteReport sums up the waste recorded within filter by reason.
+func (ws *wasteService) GetWasteReport(ctx context.Context, filter models.WasteReportFilter) (*models.WasteReport, error) {
+	reasons, err := ws.wasteRepo.SumWaste(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.WasteReport{From: filter.From, To: filter.To, Reasons: reasons}, nil
+}
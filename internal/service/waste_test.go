@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"errors"
+	internalErrors "stockk/internal/errors"
+	"stockk/internal/models"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+
+	"go.uber.org/mock/gomock"
+)
+
+func TestRecordWaste(t *testing.T) {
+	testCases := []struct {
+		name       string
+		input      *models.WasteEntry
+		buildStubs func(
+			wasteRepo *mockrepository.MockWasteRepository,
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			movementRepo *mockrepository.MockStockMovementRepository,
+			outboxRepo *mockrepository.MockOutboxRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, entry *models.WasteEntry, err error)
+	}{
+		{
+			name:  "Success Record Waste",
+			input: &models.WasteEntry{IngredientID: 2, Quantity: 500, Reason: models.WasteReasonDropped, Notes: " Tray fell "},
+			buildStubs: func(
+				wasteRepo *mockrepository.MockWasteRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				wasteRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				wasteRepo.EXPECT().CreateWasteEntry(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, entry *models.WasteEntry) error {
+						entry.ID = 9
+						return nil
+					})
+
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 2, float64(500)).Return(nil)
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), tx, gomock.Any()).
+					DoAndReturn(func(ctx context.Context, tx any, movement *models.StockMovement) error {
+						if movement.IngredientID != 2 || movement.Delta != -500 || movement.Reason != models.MovementReasonWaste || *movement.ReferenceID != 9 {
+							t.Errorf("unexpected movement %+v", movement)
+						}
+						return nil
+					})
+
+				// The waste takes cheese below its threshold
+				ingredientRepo.EXPECT().ClaimLowStockAlerts(gomock.Any(), tx, []int{2}).
+					Return([]models.Ingredient{{ID: 2, Name: "Cheese"}}, nil)
+				outboxRepo.EXPECT().AddMessage(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				tx.EXPECT().Commit().Return(nil)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, entry *models.WasteEntry, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+				if entry.ID != 9 || entry.Notes != "Tray fell" {
+					t.Errorf("unexpected waste entry %+v", entry)
+				}
+			},
+		},
+		{
+			name:  "More Than In Stock Rolls Back",
+			input: &models.WasteEntry{IngredientID: 2, Quantity: 5000, Reason: models.WasteReasonSpoiled},
+			buildStubs: func(
+				wasteRepo *mockrepository.MockWasteRepository,
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				movementRepo *mockrepository.MockStockMovementRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				wasteRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				wasteRepo.EXPECT().CreateWasteEntry(gomock.Any(), tx, gomock.Any()).Return(nil)
+
+				ingredientRepo.EXPECT().DecrementStock(gomock.Any(), tx, 2, float64(5000)).
+					Return(internalErrors.NewAppError(internalErrors.ErrCodeInsufficientStock, "Insufficient Ingredient stock"))
+				movementRepo.EXPECT().RecordMovement(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, entry *models.WasteEntry, err error) {
+				var appErr *internalErrors.AppError
+				if !errors.As(err, &appErr) || appErr.Code != internalErrors.ErrCodeInsufficientStock {
+					t.Errorf("expected insufficient stock error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			wasteRepo := mockrepository.NewMockWasteRepository(ctrl)
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			movementRepo := mockrepository.NewMockStockMovementRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(wasteRepo, ingredientRepo, movementRepo, outboxRepo, tx)
+
+			ws := NewWasteService(wasteRepo, ingredientRepo, movementRepo, outboxRepo)
+
+			entry, err := ws.RecordWaste(context.Background(), tc.input)
+			tc.checkResult(t, entry, err)
+		})
+	}
+}
@@ -1,6 +1,15 @@
 package validator
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"stockk/internal/models"
+)
+
+const maxNameLength = 100
 
 func ValidateID(value int) error {
 	if value <= 0 {
@@ -15,3 +24,104 @@ func ValidateQuantity(value int) error {
 	}
 	return nil
 }
+
+func ValidateName(value string) error {
+	name := strings.TrimSpace(value)
+	if name == "" {
+		return errors.New("Name must not be empty")
+	}
+	if len(name) > maxNameLength {
+		return errors.New("Name must not exceed 100 characters")
+	}
+	return nil
+}
+
+func ValidateStock(value float64) error {
+	if value < 0 {
+		return errors.New("Stock must be a non-negative number")
+	}
+	return nil
+}
+
+func ValidateAmount(value float64) error {
+	if value <= 0 {
+		return errors.New("Amount must be a positive number")
+	}
+	return nil
+}
+
+func ValidateBaseUnit(value string) error {
+	if !models.IsBaseUnit(value) {
+		return fmt.Errorf("Unit must be one of %s, %s or %s", models.UnitGram, models.UnitMilliliter, models.UnitPiece)
+	}
+	return nil
+}
+
+func ValidateUnit(value string) error {
+	if !models.IsUnit(value) {
+		return fmt.Errorf("Unit %q is not a known unit of measure", value)
+	}
+	return nil
+}
+
+func ValidateThreshold(thresholdType string, value float64) error {
+	switch thresholdType {
+	case models.ThresholdTypePercentage:
+		if value < 0 || value > 100 {
+			return errors.New("Percentage threshold must be between 0 and 100")
+		}
+	case models.ThresholdTypeQuantity:
+		if value < 0 {
+			return errors.New("Quantity threshold must be a non-negative number")
+		}
+	default:
+		return errors.New("Threshold type must be either percentage or quantity")
+	}
+	return nil
+}
+
+func ValidateYieldPercent(value float64) error {
+	if value <= 0 || value > 100 {
+		return errors.New("Yield percentage must be greater than 0 and at most 100")
+	}
+	return nil
+}
+
+func ValidateModifierAction(value string) error {
+	switch value {
+	case models.ModifierActionAdd, models.ModifierActionRemove, models.ModifierActionReplace:
+		return nil
+	}
+	return fmt.Errorf("Modifier action must be one of %s, %s or %s", models.ModifierActionAdd, models.ModifierActionRemove, models.ModifierActionReplace)
+}
+
+func ValidateWasteReason(value string) error {
+	switch value {
+	case models.WasteReasonSpoiled, models.WasteReasonDropped, models.WasteReasonExpired, models.WasteReasonComp:
+		return nil
+	}
+	return fmt.Errorf("Waste reason must be one of %s, %s, %s or %s", models.WasteReasonSpoiled, models.WasteReasonDropped, models.WasteReasonExpired, models.WasteReasonComp)
+}
+
+func ValidateEmail(value string) error {
+	email := strings.TrimSpace(value)
+	address, err := mail.ParseAddress(email)
+	if err != nil || address.Address != email {
+		return errors.New("Email must be a valid email address")
+	}
+	return nil
+}
+
+func ValidatePrice(value float64) error {
+	if value < 0 {
+		return errors.New("Price must be a non-negative number")
+	}
+	return nil
+}
+
+func ValidateLeadTime(value int) error {
+	if value < 0 {
+		return errors.New("Lead time must be a non-negative number of days")
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"log/slog"
+	"stockk/internal/models"
 	"stockk/internal/repository"
 	"strings"
 
@@ -24,15 +25,8 @@ func (processor *RedisTaskProcessor) ProcessTaskSendAlertEmail(ctx context.Conte
 	The following ingredients are running low on stock:<br/><ul>`)
 
 	for _, ingredient := range payload.Ingredients {
-		// Calculate the remaining percentage of the stock
-		percentRemaining := (ingredient.CurrentStock / ingredient.TotalStock) * 100
-
 		// Add warning details to the email content
-		contentBuilder.WriteString(fmt.Sprintf(
-			`<li>%s: %.2f%% remaining</li>`,
-			ingredient.Name, percentRemaining,
-		))
-
+		contentBuilder.WriteString(fmt.Sprintf(`<li>%s</li>`, describeLowStock(ingredient)))
 	}
 
 	contentBuilder.WriteString("</ul><br/>Please replenish these ingredients soon to avoid disruption.<br/>Best regards,<br/>The Stockk Team")
@@ -44,18 +38,10 @@ func (processor *RedisTaskProcessor) ProcessTaskSendAlertEmail(ctx context.Conte
 	testMerchantEmail := processor.testMerchantEmail
 	subject := "Stockk Alert: Low Stock Warning"
 	to := []string{testMerchantEmail} // Test email for demonstration purposes
+	// The ingredients were marked alerted when the alert was queued
 	err := processor.mailer.SendEmail(subject, content, to, nil, nil, nil)
 	if err != nil {
 		return fmt.Errorf("failed to send warning email: %w", err)
-	} else {
-		// email has been sent successfully
-		for _, ingredient := range payload.Ingredients {
-			// Mark the alert as sent for the ingredient
-			err := processor.ingredientRepo.MarkAlertSent(ctx, ingredient.ID)
-			if err != nil {
-				return fmt.Errorf("failed to mark alert as sent for ingredient ID %d: %w", ingredient.ID, err)
-			}
-		}
 	}
 
 	// Log the processed task
@@ -69,3 +55,24 @@ func (processor *RedisTaskProcessor) ProcessTaskSendAlertEmail(ctx context.Conte
 
 	return nil
 }
+
+// describeLowStock formats the remaining stock of an ingredient against its threshold.
+func describeLowStock(ingredient models.Ingredient) string {
+	threshold := ingredient.LowStockThreshold
+	if threshold.Type == models.ThresholdTypeQuantity {
+		return fmt.Sprintf(
+			"%s: %.2f remaining (alert threshold: %.2f)",
+			ingredient.Name, ingredient.CurrentStock, threshold.Value,
+		)
+	}
+
+	// Calculate the remaining percentage of the stock
+	percentRemaining := 0.0
+	if ingredient.TotalStock > 0 {
+		percentRemaining = (ingredient.CurrentStock / ingredient.TotalStock) * 100
+	}
+	return fmt.Sprintf(
+		"%s: %.2f%% remaining (alert threshold: %.2f%%)",
+		ingredient.Name, percentRemaining, threshold.Value,
+	)
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	"strings"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskScanExpiringLots claims the lots that came within the expiry
+// window since the last scan and stores their alert email in the outbox in
+// the same transaction, so a lot is alerted once per window and the email is
+// only published once the claim commits.
+func (processor *RedisTaskProcessor) ProcessTaskScanExpiringLots(ctx context.Context, task *asynq.Task) (err error) {
+	var payload repository.PayloadScanExpiringLots
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload: %w", asynq.SkipRetry)
+	}
+	if payload.WindowDays <= 0 {
+		return fmt.Errorf("invalid expiry alert window %d: %w", payload.WindowDays, asynq.SkipRetry)
+	}
+
+	tx, err := processor.ingredientRepo.BeginTransaction()
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	// Rollback if anything fails before the commit succeeds
+	defer func() {
+		if err != nil {
+			if rbErr := tx.Rollback(); rbErr != nil {
+				slog.Error("failed to rollback transaction", "error", rbErr)
+			}
+		}
+	}()
+
+	lots, err := processor.ingredientRepo.ClaimExpiryAlerts(ctx, tx, payload.WindowDays)
+	if err != nil {
+		return fmt.Errorf("failed to claim expiry alerts: %w", err)
+	}
+
+	if len(lots) > 0 {
+		var message []byte
+		message, err = json.Marshal(&repository.PayloadSendExpiryAlertEmail{WindowDays: payload.WindowDays, Lots: lots})
+		if err != nil {
+			return fmt.Errorf("failed to encode expiry alert email task: %w", err)
+		}
+		if err = processor.outboxRepo.AddMessage(ctx, tx, &models.OutboxMessage{
+			TaskType: repository.TaskSendExpiryAlertEmail,
+			Payload:  message,
+		}); err != nil {
+			return fmt.Errorf("failed to queue expiry alert email: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit expiry alerts: %w", err)
+	}
+
+	// Log the processed task
+	slog.LogAttrs(ctx,
+		slog.LevelInfo,
+		"processed task",
+		slog.String("type", task.Type()),
+		slog.String("payload", string(task.Payload())),
+		slog.Int("lots", len(lots)),
+	)
+
+	return nil
+}
+
+// ProcessTaskSendExpiryAlertEmail emails the lots claimed by a scan.
+func (processor *RedisTaskProcessor) ProcessTaskSendExpiryAlertEmail(ctx context.Context, task *asynq.Task) error {
+	var payload repository.PayloadSendExpiryAlertEmail
+	if err := json.Unmarshal(task.Payload(), &payload); err != nil {
+		return fmt.Errorf("failed to unmarshal task payload: %w", asynq.SkipRetry)
+	}
+	if len(payload.Lots) == 0 {
+		return nil
+	}
+
+	// Prepare the email content
+	contentBuilder := strings.Builder{}
+	contentBuilder.WriteString(fmt.Sprintf(`Hello,<br/>
+	The following lots expire within %d days:<br/><ul>`, payload.WindowDays))
+
+	now := time.Now()
+	for _, lot := range payload.Lots {
+		contentBuilder.WriteString(fmt.Sprintf(`<li>%s</li>`, describeExpiringLot(lot, now)))
+	}
+
+	contentBuilder.WriteString("</ul><br/>Please use or discard these lots before they go to waste.<br/>Best regards,<br/>The Stockk Team")
+
+	subject := "Stockk Alert: Lots Expiring Soon"
+	to := []string{processor.testMerchantEmail} // Test email for demonstration purposes
+	if err := processor.mailer.SendEmail(subject, contentBuilder.String(), to, nil, nil, nil); err != nil {
+		return fmt.Errorf("failed to send expiry alert email: %w", err)
+	}
+
+	// Log the processed task
+	slog.LogAttrs(ctx,
+		slog.LevelInfo,
+		"processed task",
+		slog.String("type", task.Type()),
+		slog.String("payload", string(task.Payload())),
+		slog.String("email", processor.testMerchantEmail),
+	)
+
+	return nil
+}
+
+// describeExpiringLot formats the remaining quantity of a lot against its expiry.
+func describeExpiringLot(lot models.IngredientLot, now time.Time) string {
+	name := lot.IngredientName
+	if lot.LotNumber != "" {
+		name = fmt.Sprintf("%s (lot %s)", name, lot.LotNumber)
+	}
+
+	verb := "expires"
+	if lot.ExpiresAt.Before(now) {
+		verb = "expired"
+	}
+	return fmt.Sprintf(
+		"%s: %.2f remaining, %s on %s",
+		name, lot.RemainingQuantity, verb, lot.ExpiresAt.Format(time.DateOnly),
+	)
+}
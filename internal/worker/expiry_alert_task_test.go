@@ -0,0 +1,242 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	mockmail "stockk/internal/mail/mock"
+	"stockk/internal/models"
+	"stockk/internal/repository"
+	mockrepository "stockk/internal/repository/mock"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskScanExpiringLots(t *testing.T) {
+	expiresAt := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+	lots := []models.IngredientLot{
+		{ID: 12, IngredientID: 1, IngredientName: "Beef", LotNumber: "L-2291", ExpiresAt: &expiresAt, RemainingQuantity: 1200},
+	}
+
+	testCases := []struct {
+		name       string
+		payload    string
+		buildStubs func(
+			ingredientRepo *mockrepository.MockIngredientRepository,
+			outboxRepo *mockrepository.MockOutboxRepository,
+			tx *mockrepository.MockTransaction,
+		)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name:    "Claimed Lots Queued Before Commit",
+			payload: `{"window_days": 3}`,
+			buildStubs: func(
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				gomock.InOrder(
+					ingredientRepo.EXPECT().BeginTransaction().Return(tx, nil),
+					ingredientRepo.EXPECT().ClaimExpiryAlerts(gomock.Any(), tx, 3).Return(lots, nil),
+					outboxRepo.EXPECT().AddMessage(gomock.Any(), tx, gomock.Any()).
+						DoAndReturn(func(ctx context.Context, tx any, message *models.OutboxMessage) error {
+							var payload repository.PayloadSendExpiryAlertEmail
+							if err := json.Unmarshal(message.Payload, &payload); err != nil {
+								t.Fatalf("failed to decode outbox payload: %v", err)
+							}
+							if message.TaskType != repository.TaskSendExpiryAlertEmail || payload.WindowDays != 3 || len(payload.Lots) != 1 || payload.Lots[0].ID != 12 {
+								t.Errorf("unexpected outbox message %s %s", message.TaskType, message.Payload)
+							}
+							return nil
+						}),
+					tx.EXPECT().Commit().Return(nil),
+				)
+				tx.EXPECT().Rollback().Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "No Lots Expiring",
+			payload: `{"window_days": 3}`,
+			buildStubs: func(
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				ingredientRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientRepo.EXPECT().ClaimExpiryAlerts(gomock.Any(), tx, 3).Return([]models.IngredientLot{}, nil)
+				outboxRepo.EXPECT().AddMessage(gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+
+				tx.EXPECT().Commit().Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "Outbox Failure Releases Claim",
+			payload: `{"window_days": 3}`,
+			buildStubs: func(
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				ingredientRepo.EXPECT().BeginTransaction().Return(tx, nil)
+				ingredientRepo.EXPECT().ClaimExpiryAlerts(gomock.Any(), tx, 3).Return(lots, nil)
+				outboxRepo.EXPECT().AddMessage(gomock.Any(), tx, gomock.Any()).Return(errors.New("query failed"))
+
+				tx.EXPECT().Commit().Times(0)
+				tx.EXPECT().Rollback().Return(nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err == nil {
+					t.Fatal("expected an error so the scan is retried")
+				}
+			},
+		},
+		{
+			name:    "Invalid Window",
+			payload: `{"window_days": 0}`,
+			buildStubs: func(
+				ingredientRepo *mockrepository.MockIngredientRepository,
+				outboxRepo *mockrepository.MockOutboxRepository,
+				tx *mockrepository.MockTransaction,
+			) {
+				ingredientRepo.EXPECT().BeginTransaction().Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if !errors.Is(err, asynq.SkipRetry) {
+					t.Errorf("expected the task not to be retried, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ingredientRepo := mockrepository.NewMockIngredientRepository(ctrl)
+			outboxRepo := mockrepository.NewMockOutboxRepository(ctrl)
+			tx := mockrepository.NewMockTransaction(ctrl)
+
+			tc.buildStubs(ingredientRepo, outboxRepo, tx)
+
+			processor := &RedisTaskProcessor{ingredientRepo: ingredientRepo, outboxRepo: outboxRepo}
+
+			err := processor.ProcessTaskScanExpiringLots(context.Background(), asynq.NewTask(repository.TaskScanExpiringLots, []byte(tc.payload)))
+			tc.checkResult(t, err)
+		})
+	}
+}
+
+func TestProcessTaskSendExpiryAlertEmail(t *testing.T) {
+	testCases := []struct {
+		name        string
+		payload     string
+		buildStubs  func(mailer *mockmail.MockEmailSender)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name:    "Lots Emailed",
+			payload: `{"window_days": 3, "lots": [{"id": 12, "ingredient_name": "Beef", "lot_number": "L-2291", "expires_at": "2026-03-14T00:00:00Z", "remaining_quantity": 1200}]}`,
+			buildStubs: func(mailer *mockmail.MockEmailSender) {
+				mailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), []string{"merchant@example.com"}, nil, nil, nil).
+					DoAndReturn(func(subject, content string, to, cc, bcc, attachFiles []string) error {
+						if !strings.Contains(content, "within 3 days") || !strings.Contains(content, "Beef (lot L-2291): 1200.00 remaining") {
+							t.Errorf("unexpected email content %q", content)
+						}
+						return nil
+					})
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "No Lots",
+			payload: `{"window_days": 3, "lots": []}`,
+			buildStubs: func(mailer *mockmail.MockEmailSender) {
+				mailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name:    "Send Failure Retried",
+			payload: `{"window_days": 3, "lots": [{"id": 12, "ingredient_name": "Beef", "expires_at": "2026-03-14T00:00:00Z", "remaining_quantity": 1200}]}`,
+			buildStubs: func(mailer *mockmail.MockEmailSender) {
+				mailer.EXPECT().SendEmail(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(errors.New("smtp unavailable"))
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err == nil || errors.Is(err, asynq.SkipRetry) {
+					t.Errorf("expected a retryable error, got %v", err)
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mailer := mockmail.NewMockEmailSender(ctrl)
+			tc.buildStubs(mailer)
+
+			processor := &RedisTaskProcessor{mailer: mailer, testMerchantEmail: "merchant@example.com"}
+
+			err := processor.ProcessTaskSendExpiryAlertEmail(context.Background(), asynq.NewTask(repository.TaskSendExpiryAlertEmail, []byte(tc.payload)))
+			tc.checkResult(t, err)
+		})
+	}
+}
+
+func TestDescribeExpiringLot(t *testing.T) {
+	now := time.Date(2026, 3, 12, 9, 0, 0, 0, time.UTC)
+	past := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	future := time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		lot      models.IngredientLot
+		expected string
+	}{
+		{
+			name:     "Lot Number And Expiry Ahead",
+			lot:      models.IngredientLot{IngredientName: "Beef", LotNumber: "L-2291", ExpiresAt: &future, RemainingQuantity: 1200},
+			expected: "Beef (lot L-2291): 1200.00 remaining, expires on 2026-03-14",
+		},
+		{
+			name:     "Without Lot Number Already Expired",
+			lot:      models.IngredientLot{IngredientName: "Milk", ExpiresAt: &past, RemainingQuantity: 2.5},
+			expected: "Milk: 2.50 remaining, expired on 2026-03-10",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := describeExpiringLot(tc.lot, now); got != tc.expected {
+				t.Errorf("expected %q, got %q", tc.expected, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package worker
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/hibiken/asynq"
+)
+
+// ProcessTaskPurgeIdempotencyKeys deletes the expired order idempotency keys.
+func (processor *RedisTaskProcessor) ProcessTaskPurgeIdempotencyKeys(ctx context.Context, task *asynq.Task) error {
+	purged, err := processor.idempotencyRepo.PurgeExpiredKeys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to purge expired idempotency keys: %w", err)
+	}
+
+	// Log the processed task
+	slog.LogAttrs(ctx,
+		slog.LevelInfo,
+		"processed task",
+		slog.String("type", task.Type()),
+		slog.Int64("purged", purged),
+	)
+
+	return nil
+}
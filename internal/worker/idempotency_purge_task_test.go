@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"stockk/internal/repository"
+	mockrepository "stockk/internal/repository/mock"
+	"testing"
+
+	"github.com/hibiken/asynq"
+	"go.uber.org/mock/gomock"
+)
+
+func TestProcessTaskPurgeIdempotencyKeys(t *testing.T) {
+	testCases := []struct {
+		name        string
+		buildStubs  func(idempotencyRepo *mockrepository.MockIdempotencyRepository)
+		checkResult func(t *testing.T, err error)
+	}{
+		{
+			name: "Expired Keys Purged",
+			buildStubs: func(idempotencyRepo *mockrepository.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().PurgeExpiredKeys(gomock.Any()).Return(int64(4), nil)
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err != nil {
+					t.Fatalf("expected no error, got %v", err)
+				}
+			},
+		},
+		{
+			name: "Purge Failure Retried",
+			buildStubs: func(idempotencyRepo *mockrepository.MockIdempotencyRepository) {
+				idempotencyRepo.EXPECT().PurgeExpiredKeys(gomock.Any()).Return(int64(0), errors.New("query failed"))
+			},
+			checkResult: func(t *testing.T, err error) {
+				if err == nil {
+					t.Fatal("expected an error so the purge is retried")
+				}
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			idempotencyRepo := mockrepository.NewMockIdempotencyRepository(ctrl)
+			tc.buildStubs(idempotencyRepo)
+
+			processor := &RedisTaskProcessor{idempotencyRepo: idempotencyRepo}
+
+			err := processor.ProcessTaskPurgeIdempotencyKeys(context.Background(), asynq.NewTask(repository.TaskPurgeIdempotencyKeys, nil))
+			tc.checkResult(t, err)
+		})
+	}
+}
@@ -0,0 +1,33 @@
+package worker
+
+import (
+	"context"
+	"log/slog"
+	"stockk/internal/service"
+	"time"
+)
+
+// RunOutboxRelay publishes pending outbox messages to the task queue every
+// interval until ctx is cancelled. Messages that cannot be published, e.g.
+// while Redis is down, stay in the outbox and are retried on the next tick.
+func RunOutboxRelay(ctx context.Context, outboxService service.OutboxService, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	slog.Info("start outbox relay", "interval", interval.String())
+	for {
+		dispatched, err := outboxService.DispatchPending(ctx)
+		if err != nil {
+			slog.Error("failed to dispatch outbox messages", "error", err)
+		} else if dispatched > 0 {
+			slog.Info("dispatched outbox messages", "count", dispatched)
+		}
+
+		select {
+		case <-ctx.Done():
+			slog.Info("outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -21,16 +21,21 @@ const (
 type TaskProcessor interface {
 	Start() error
 	ProcessTaskSendAlertEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskScanExpiringLots(ctx context.Context, task *asynq.Task) error
+	ProcessTaskSendExpiryAlertEmail(ctx context.Context, task *asynq.Task) error
+	ProcessTaskPurgeIdempotencyKeys(ctx context.Context, task *asynq.Task) error
 }
 
 type RedisTaskProcessor struct {
 	server            *asynq.Server
 	ingredientRepo    repository.IngredientRepository
+	outboxRepo        repository.OutboxRepository
+	idempotencyRepo   repository.IdempotencyRepository
 	mailer            mail.EmailSender
 	testMerchantEmail string
 }
 
-func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, ingredientRepo repository.IngredientRepository, mailer mail.EmailSender, testMerchantEmail string) TaskProcessor {
+func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, ingredientRepo repository.IngredientRepository, outboxRepo repository.OutboxRepository, idempotencyRepo repository.IdempotencyRepository, mailer mail.EmailSender, testMerchantEmail string) TaskProcessor {
 	server := asynq.NewServer(redisOpt, asynq.Config{
 		ErrorHandler: asynq.ErrorHandlerFunc(func(ctx context.Context, task *asynq.Task, err error) {
 			slog.LogAttrs(ctx,
@@ -46,6 +51,8 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, ingredientRepo reposit
 	return &RedisTaskProcessor{
 		server:            server,
 		ingredientRepo:    ingredientRepo,
+		outboxRepo:        outboxRepo,
+		idempotencyRepo:   idempotencyRepo,
 		mailer:            mailer,
 		testMerchantEmail: testMerchantEmail,
 	}
@@ -54,14 +61,17 @@ func NewRedisTaskProcessor(redisOpt asynq.RedisClientOpt, ingredientRepo reposit
 func (processor *RedisTaskProcessor) Start() error {
 	mux := asynq.NewServeMux()
 	mux.HandleFunc(repository.TaskSendAlertEmail, processor.ProcessTaskSendAlertEmail)
+	mux.HandleFunc(repository.TaskScanExpiringLots, processor.ProcessTaskScanExpiringLots)
+	mux.HandleFunc(repository.TaskSendExpiryAlertEmail, processor.ProcessTaskSendExpiryAlertEmail)
+	mux.HandleFunc(repository.TaskPurgeIdempotencyKeys, processor.ProcessTaskPurgeIdempotencyKeys)
 	return processor.server.Start(mux)
 }
 
 // RunTaskProcessor runs the task processor.
-func RunTaskProcessor(config config.Config, redisOpts asynq.RedisClientOpt, ingredientRepo repository.IngredientRepository) {
+func RunTaskProcessor(config config.Config, redisOpts asynq.RedisClientOpt, ingredientRepo repository.IngredientRepository, outboxRepo repository.OutboxRepository, idempotencyRepo repository.IdempotencyRepository) {
 	mailer := mail.NewGmailSender(config.EmailSenderName, config.EmailSenderAddress, config.EmailSenderPassword)
 
-	taskProcessor := NewRedisTaskProcessor(redisOpts, ingredientRepo, mailer, config.TestMerchantEmail)
+	taskProcessor := NewRedisTaskProcessor(redisOpts, ingredientRepo, outboxRepo, idempotencyRepo, mailer, config.TestMerchantEmail)
 	slog.Info("start task processor")
 	err := taskProcessor.Start()
 	if err != nil {
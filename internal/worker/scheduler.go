@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"stockk/internal/config"
+	"stockk/internal/repository"
+
+	"github.com/hibiken/asynq"
+)
+
+// periodicTaskRegistrar registers tasks enqueued on a cron schedule, as
+// *asynq.Scheduler does.
+type periodicTaskRegistrar interface {
+	Register(cronspec string, task *asynq.Task, opts ...asynq.Option) (string, error)
+}
+
+// RunTaskScheduler enqueues the periodic tasks, the scan for lots expiring
+// within the configured window and the purge of expired idempotency keys.
+// Every instance may run a scheduler, the task handlers deduplicate what they
+// alert and purging twice deletes nothing more.
+func RunTaskScheduler(config config.Config, redisOpts asynq.RedisClientOpt) {
+	scheduler := asynq.NewScheduler(redisOpts, &asynq.SchedulerOpts{Logger: NewLogger()})
+
+	if err := registerPeriodicTasks(scheduler, config); err != nil {
+		slog.Error(fmt.Sprintf("%s: %v", "err", err))
+		os.Exit(1)
+	}
+
+	slog.Info("start task scheduler",
+		"expiry_alert_schedule", config.ExpiryAlertSchedule,
+		"expiry_alert_window_days", config.ExpiryAlertWindowDays,
+		"idempotency_purge_schedule", config.IdempotencyPurgeSchedule,
+	)
+	if err := scheduler.Start(); err != nil {
+		slog.Error(fmt.Sprintf("%s: %v", "err", err))
+		os.Exit(1)
+	}
+}
+
+// registerPeriodicTasks registers the periodic tasks on their configured schedules.
+func registerPeriodicTasks(scheduler periodicTaskRegistrar, config config.Config) error {
+	payload, err := json.Marshal(&repository.PayloadScanExpiringLots{WindowDays: config.ExpiryAlertWindowDays})
+	if err != nil {
+		return fmt.Errorf("failed to encode expiry alert task: %w", err)
+	}
+
+	task := asynq.NewTask(repository.TaskScanExpiringLots, payload)
+	if _, err := scheduler.Register(config.ExpiryAlertSchedule, task); err != nil {
+		return fmt.Errorf("failed to register expiry alert task with schedule %q: %w", config.ExpiryAlertSchedule, err)
+	}
+
+	purge := asynq.NewTask(repository.TaskPurgeIdempotencyKeys, nil)
+	if _, err := scheduler.Register(config.IdempotencyPurgeSchedule, purge); err != nil {
+		return fmt.Errorf("failed to register idempotency key purge task with schedule %q: %w", config.IdempotencyPurgeSchedule, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"encoding/json"
+	"errors"
+	"stockk/internal/config"
+	"stockk/internal/repository"
+	"testing"
+
+	"github.com/hibiken/asynq"
+)
+
+// recordingRegistrar records the tasks registered with it.
+type recordingRegistrar struct {
+	cronspecs []string
+	tasks     []*asynq.Task
+	err       error
+}
+
+func (r *recordingRegistrar) Register(cronspec string, task *asynq.Task, opts ...asynq.Option) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	r.cronspecs = append(r.cronspecs, cronspec)
+	r.tasks = append(r.tasks, task)
+	return cronspec, nil
+}
+
+func TestRegisterPeriodicTasks(t *testing.T) {
+	registrar := &recordingRegistrar{}
+	cfg := config.Config{ExpiryAlertSchedule: "@hourly", ExpiryAlertWindowDays: 3, IdempotencyPurgeSchedule: "@daily"}
+
+	if err := registerPeriodicTasks(registrar, cfg); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(registrar.tasks) != 2 {
+		t.Fatalf("expected 2 registered tasks, got %d", len(registrar.tasks))
+	}
+	if registrar.cronspecs[0] != "@hourly" || registrar.tasks[0].Type() != repository.TaskScanExpiringLots {
+		t.Errorf("unexpected task %s on %q", registrar.tasks[0].Type(), registrar.cronspecs[0])
+	}
+
+	var payload repository.PayloadScanExpiringLots
+	if err := json.Unmarshal(registrar.tasks[0].Payload(), &payload); err != nil {
+		t.Fatalf("failed to decode task payload: %v", err)
+	}
+	if payload.WindowDays != 3 {
+		t.Errorf("expected a 3 day window, got %d", payload.WindowDays)
+	}
+	if registrar.cronspecs[1] != "@daily" || registrar.tasks[1].Type() != repository.TaskPurgeIdempotencyKeys {
+		t.Errorf("unexpected task %s on %q", registrar.tasks[1].Type(), registrar.cronspecs[1])
+	}
+}
+
+func TestRegisterPeriodicTasks_InvalidSchedule(t *testing.T) {
+	registrar := &recordingRegistrar{err: errors.New("invalid cronspec")}
+	cfg := config.Config{ExpiryAlertSchedule: "not a schedule", ExpiryAlertWindowDays: 3}
+
+	if err := registerPeriodicTasks(registrar, cfg); err == nil {
+		t.Fatal("expected an error for an invalid schedule")
+	}
+}
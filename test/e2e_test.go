@@ -43,15 +43,19 @@ func TestCreateOrderE2EWithTable(t *testing.T) {
 	asynqClient := setupAsynqClient(redisAddress)
 	defer asynqClient.Close()
 
-	ingredientRepo := repository.NewIngredientRepository(dbConn)
+	ingredientRepo := repository.NewIngredientRepository(dbConn, cfg.LowStockThresholdPercent)
 	orderRepo := repository.NewOrderRepository(dbConn)
 	productRepo := repository.NewProductRepository(dbConn)
+	movementRepo := repository.NewStockMovementRepository(dbConn)
+	idempotencyRepo := repository.NewIdempotencyRepository(dbConn, cfg.IdempotencyKeyTTL)
+	outboxRepo := repository.NewOutboxRepository(dbConn)
+	prepRepo := repository.NewPrepRecipeRepository(dbConn)
 	taskQueueRepo := repository.NewTaskQueueRepository(asynqClient)
 
-	orderService := service.NewOrderService(orderRepo, productRepo, ingredientRepo)
-	ingredientService := service.NewIngredientService(ingredientRepo, taskQueueRepo)
+	orderService := service.NewOrderService(orderRepo, productRepo, ingredientRepo, movementRepo, idempotencyRepo, outboxRepo, prepRepo)
+	outboxService := service.NewOutboxService(outboxRepo, taskQueueRepo)
 
-	orderController := controllers.NewOrderController(orderService, ingredientService)
+	orderController := controllers.NewOrderController(orderService)
 
 	router := setupRouter(orderController)
 	server := httptest.NewServer(router)
@@ -139,6 +143,10 @@ func TestCreateOrderE2EWithTable(t *testing.T) {
 			require.Equal(t, tt.expectedStatus, resp.StatusCode)
 			assertIngredientStockUpdated(t, dbConn, tt.expectedStock)
 
+			// Run the outbox relay once to publish the alerts queued by the order
+			_, err = outboxService.DispatchPending(ctx)
+			require.NoError(t, err)
+
 			inspector := asynq.NewInspector(asynq.RedisClientOpt{Addr: redisAddress})
 			defer inspector.Close()
 
@@ -201,6 +209,9 @@ func setupConfig(t *testing.T, ctx context.Context, postgresContainer *postgres.
 		MigrationsURL:     "file://../db/migrations",
 		TestMerchantEmail: "expected@domain.com",
 		DBSource:          dbURL,
+
+		LowStockThresholdPercent: 50,
+		IdempotencyKeyTTL:        time.Hour,
 	}
 }
 